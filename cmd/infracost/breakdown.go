@@ -22,7 +22,7 @@ func breakdownCmd(ctx *config.RunContext) *cobra.Command {
       infracost breakdown --path plan.json`,
 		ValidArgs: []string{"--", "-"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+			if err := checkPricingSource(cmd, ctx.Config); err != nil {
 				return err
 			}
 
@@ -46,11 +46,13 @@ func breakdownCmd(ctx *config.RunContext) *cobra.Command {
 	addRunFlags(cmd)
 
 	cmd.Flags().Bool("terraform-use-state", false, "Use Terraform state instead of generating a plan. Applicable when path is a Terraform directory")
-	cmd.Flags().String("format", "table", "Output format: json, table, html")
+	cmd.Flags().String("format", "table", "Output format: json, table, html, html-report, markdown, csv, xlsx, sarif, junit, prometheus")
 	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: all,price,monthlyQuantity,unit,hourlyCost,monthlyCost.\nSupported by table and html output formats")
 
+	cmd.Flags().StringSlice("group-by", []string{}, "Comma separated list of dimensions to subtotal costs by: tag:<key>, type, module, provider, region.\nSupported by table and json output formats")
+
 	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"table", "json", "html"}, cobra.ShellCompDirectiveDefault
+		return []string{"table", "json", "html", "html-report", "markdown", "csv", "xlsx", "sarif", "junit", "prometheus"}, cobra.ShellCompDirectiveDefault
 	})
 
 	return cmd
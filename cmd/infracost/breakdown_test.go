@@ -1,6 +1,7 @@
 package main_test
 
 import (
+	"os"
 	"testing"
 
 	"github.com/infracost/infracost/internal/testutil"
@@ -34,6 +35,58 @@ func TestBreakdownTerraformPlanJSON(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"breakdown", "--path", "../../examples/terraform/plan.json"}, nil)
 }
 
+func TestBreakdownPricingSnapshot(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"breakdown", "--path", "./testdata/azure_firewall_plan.json", "--pricing-snapshot", "./testdata/azure_firewall_prices.json"}, nil)
+}
+
+func TestBreakdownStrictPricing(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--path", "./testdata/azure_firewall_plan.json", "--pricing-snapshot", "./testdata/azure_firewall_prices_incomplete.json", "--strict-pricing", "--format", "json"},
+		&GoldenFileOptions{Currency: "USD", CaptureLogs: true, IsJSON: true},
+	)
+}
+
+func TestBreakdownPriceOverrides(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--path", "./testdata/azure_firewall_plan.json", "--pricing-snapshot", "./testdata/azure_firewall_prices.json", "--price-overrides", "./testdata/azure_firewall_price_overrides.yml", "--format", "json"},
+		&GoldenFileOptions{IsJSON: true},
+	)
+}
+
+func TestBreakdownCommitments(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--path", "./testdata/aws_instances_plan.json", "--pricing-snapshot", "./testdata/aws_instances_prices.json", "--commitments", "./testdata/aws_commitments.yml"},
+		nil,
+	)
+}
+
+func TestBreakdownCommitmentsAndCredits(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--path", "./testdata/aws_instances_plan.json", "--pricing-snapshot", "./testdata/aws_instances_prices.json", "--commitments", "./testdata/aws_commitments.yml", "--price-overrides", "./testdata/aws_instances_credits.yml", "--format", "json"},
+		nil,
+	)
+}
+
+func TestBreakdownFormatPrometheusSnapshot(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--path", "./testdata/aws_instances_plan.json", "--pricing-snapshot", "./testdata/aws_instances_prices.json", "--format", "prometheus"},
+		nil,
+	)
+}
+
+func TestBreakdownSpotPriceHistory(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--path", "./testdata/aws_spot_plan.json", "--pricing-snapshot", "./testdata/aws_instances_prices.json", "--spot-price-history", "./testdata/aws_spot_price_history.csv", "--spot-price-statistic", "p90", "--usage-file", "./testdata/aws_spot_usage.yml"},
+		nil,
+	)
+}
+
 func TestBreakdownTerraformDirectory(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"breakdown", "--path", "../../examples/terraform"}, nil)
 }
@@ -83,3 +136,104 @@ func TestBreakdownTerraform_v0_14(t *testing.T) {
 func TestBreakdownTerraformUseState_v0_14(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"breakdown", "--path", "./testdata/terraform_v0.14_state.json", "--terraform-use-state"}, nil)
 }
+
+func TestBreakdownExchangeRates(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--path", "./testdata/aws_instances_plan.json", "--pricing-snapshot", "./testdata/aws_instances_prices.json", "--exchange-rates", "./testdata/exchange_rates.json"},
+		&GoldenFileOptions{Currency: "EUR"},
+	)
+}
+
+func TestBreakdownAsOf(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--path", "./testdata/aws_instances_plan.json", "--pricing-snapshot", "./testdata/aws_instances_price_history.json", "--as-of", "2020-06-01"},
+		nil,
+	)
+}
+
+func TestBreakdownGroupBy(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--path", "./testdata/aws_instances_plan.json", "--pricing-snapshot", "./testdata/aws_instances_prices.json", "--group-by", "type,region,tag:team"},
+		nil,
+	)
+}
+
+func TestBreakdownGroupByInvalid(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--path", "./testdata/aws_instances_plan.json", "--group-by", "team"},
+		nil,
+	)
+}
+
+func TestBreakdownBudgets(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--config-file", "./testdata/aws_instances_budgets.yml", "--pricing-snapshot", "./testdata/aws_instances_prices.json"},
+		nil,
+	)
+}
+
+func TestBreakdownConfigFilePricingSnapshotFlagAndEnv(t *testing.T) {
+	os.Setenv("INFRACOST_PRICING_SNAPSHOT", "./testdata/does_not_exist.json")
+	defer os.Unsetenv("INFRACOST_PRICING_SNAPSHOT")
+
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--config-file", "./testdata/source_locations/infracost.yml", "--pricing-snapshot", "./testdata/aws_instances_prices.json"},
+		nil,
+	)
+}
+
+func TestBreakdownBudgetsInvalid(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--config-file", "./testdata/invalid_budgets.yml", "--pricing-snapshot", "./testdata/aws_instances_prices.json"},
+		nil,
+	)
+}
+
+func TestBreakdownFormatSARIF(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--config-file", "./testdata/source_locations/infracost.yml", "--pricing-snapshot", "./testdata/aws_instances_prices.json", "--format", "sarif"},
+		nil,
+	)
+}
+
+func TestBreakdownFormatJUnit(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--config-file", "./testdata/source_locations/infracost.yml", "--pricing-snapshot", "./testdata/aws_instances_prices.json", "--format", "junit"},
+		nil,
+	)
+}
+
+func TestBreakdownSourceLocations(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--path", "./testdata/source_locations/plan.json", "--terraform-source-dir", "./testdata/source_locations", "--pricing-snapshot", "./testdata/aws_instances_prices.json", "--format", "markdown"},
+		nil,
+	)
+}
+
+func TestBreakdownFormatJSONSourceLocations(t *testing.T) {
+	opts := DefaultOptions()
+	opts.IsJSON = true
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--path", "./testdata/source_locations/plan.json", "--terraform-source-dir", "./testdata/source_locations", "--pricing-snapshot", "./testdata/aws_instances_prices.json", "--format", "json"},
+		opts,
+	)
+}
+
+func TestBreakdownFormatHTMLSourceLocations(t *testing.T) {
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"breakdown", "--path", "./testdata/source_locations/plan.json", "--terraform-source-dir", "./testdata/source_locations", "--pricing-snapshot", "./testdata/aws_instances_prices.json", "--format", "html"},
+		nil,
+	)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func cacheCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the local cache of Cloud Pricing API results",
+		Long: `Manage the local cache of Cloud Pricing API results.
+
+Results are cached for the pricing_cache_ttl set using infracost configure
+(default 24h). Use the --no-cache flag to skip the cache for a single run.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Show the help
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(cacheClearCmd(ctx), cacheStatsCmd(ctx))
+
+	return cmd
+}
+
+func cacheClearCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Remove all cached Cloud Pricing API results",
+		Long:  "Remove all cached Cloud Pricing API results",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := apiclient.NewPricingCache(config.PricingCacheDir(), ctx.Config.PricingCacheTTL)
+
+			count, err := c.Clear()
+			if err != nil {
+				return err
+			}
+
+			ui.PrintSuccessf(cmd.ErrOrStderr(), "Removed %d cached results from %s", count, c.Dir)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func cacheStatsCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show statistics about the cached Cloud Pricing API results",
+		Long:  "Show statistics about the cached Cloud Pricing API results",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := apiclient.NewPricingCache(config.PricingCacheDir(), ctx.Config.PricingCacheTTL)
+
+			stats, err := c.Stats()
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("%s %s\n", ui.BoldString("Directory:"), c.Dir)
+			cmd.Printf("%s       %s\n", ui.BoldString("TTL:"), c.TTL)
+			cmd.Printf("%s   %d (%d expired)\n", ui.BoldString("Entries:"), stats.Entries, stats.ExpiredEntries)
+			cmd.Printf("%s      %s\n", ui.BoldString("Size:"), humanize.Bytes(uint64(stats.SizeBytes)))
+
+			if stats.OldestEntry != nil && stats.NewestEntry != nil {
+				cmd.Printf("%s    %s\n", ui.BoldString("Oldest:"), humanize.Time(*stats.OldestEntry))
+				cmd.Printf("%s    %s\n", ui.BoldString("Newest:"), humanize.Time(*stats.NewestEntry))
+			}
+
+			if ctx.Config.PricingCacheDisabled {
+				cmd.Println(fmt.Sprintf("\nThe pricing cache is disabled by the %s environment variable.", ui.PrimaryString("INFRACOST_PRICING_CACHE_DISABLED")))
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -16,6 +16,7 @@ import (
 )
 
 var timestampRegex = regexp.MustCompile(`(\d{4})-(\d{2})-(\d{2})(T| )(\d{2}):(\d{2}):(\d{2}(?:\.\d*)?)(([\+-](\d{2}):(\d{2})|Z| [A-Z]{3})?)`)
+var toolVersionRegex = regexp.MustCompile(`("name": "Infracost",\s+"version": )"[^"]*"`)
 
 type GoldenFileOptions = struct {
 	Currency    string
@@ -98,6 +99,8 @@ func GoldenFileCommandTest(t *testing.T, testName string, args []string, options
 
 	// strip out any timestamps
 	actual = timestampRegex.ReplaceAll(actual, []byte("REPLACED_TIME"))
+	// strip out the version of the tool from the SARIF output
+	actual = toolVersionRegex.ReplaceAll(actual, []byte(`${1}"REPLACED_VERSION"`))
 
 	goldenFilePath := filepath.Join("testdata", testName, testName+".golden")
 	testutil.AssertGoldenFile(t, goldenFilePath, actual)
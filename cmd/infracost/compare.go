@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Rhymond/go-money"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func compareCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare the costs of two Infracost JSON files",
+		Long: `Compare the costs of two Infracost JSON files.
+
+Projects are matched by name, or if both files have a single project, with each
+other. Resources are matched by address, so the files can be from different
+branches, dates or variants of the same code. Globs are combined, like with
+infracost output.`,
+		Example: `  Compare the main branch with a pull request branch:
+
+      infracost breakdown --path /path/to/main --format json > base.json
+      infracost breakdown --path /path/to/pr --format json > head.json
+      infracost compare --past base.json --current head.json
+
+  Compare two regions as a markdown table:
+
+      infracost compare --past us-east-1.json --current eu-west-1.json --format markdown`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			currency, _ := cmd.Flags().GetString("currency")
+			currency = strings.ToUpper(currency)
+			if currency != "" && money.GetCurrency(currency) == nil {
+				return fmt.Errorf("Unknown currency %s", currency)
+			}
+
+			rateSource := ctx.Config.ExchangeRates
+			if cmd.Flags().Changed("exchange-rates") {
+				rateSource, _ = cmd.Flags().GetString("exchange-rates")
+			}
+
+			opts := output.Options{
+				DashboardEnabled: ctx.Config.EnableDashboard,
+				NoColor:          ctx.Config.NoColor,
+				GroupKey:         "filename",
+				GroupLabel:       "File",
+			}
+			opts.ShowSkipped, _ = cmd.Flags().GetBool("show-skipped")
+			opts.Fields, _ = cmd.Flags().GetStringSlice("fields")
+
+			pastPaths, _ := cmd.Flags().GetStringArray("past")
+			pastInputs, currency, err := loadReportInputs(pastPaths, currency, rateSource)
+			if err != nil {
+				return errors.Wrap(err, "Error loading past Infracost JSON files")
+			}
+
+			currentPaths, _ := cmd.Flags().GetStringArray("current")
+			currentInputs, currency, err := loadReportInputs(currentPaths, currency, rateSource)
+			if err != nil {
+				return errors.Wrap(err, "Error loading current Infracost JSON files")
+			}
+
+			past := output.Combine(currency, pastInputs, opts)
+			current := output.Combine(currency, currentInputs, opts)
+
+			format, _ := cmd.Flags().GetString("format")
+
+			b, err := formatOutput(format, output.Compare(past, current), opts)
+			if err != nil {
+				return err
+			}
+
+			if strings.ToLower(format) == "xlsx" {
+				_, err = cmd.OutOrStdout().Write(b)
+				return err
+			}
+
+			cmd.Println(string(b))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArray("past", []string{}, "Path to the Infracost JSON files to compare against")
+	cmd.Flags().StringArray("current", []string{}, "Path to the Infracost JSON files to compare")
+	cmd.Flags().String("format", "diff", "Output format: json, diff, table, html, html-report, markdown, csv, xlsx, sarif, junit, prometheus")
+	cmd.Flags().Bool("show-skipped", false, "Show unsupported resources, some of which might be free")
+	cmd.Flags().String("currency", "", "Currency to compare the files in. Defaults to the currency of the first file")
+	cmd.Flags().String("exchange-rates", "", "Path or URL of exchange rates used to convert files to the same currency")
+	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: all,price,monthlyQuantity,unit,hourlyCost,monthlyCost.\nSupported by table and html output formats")
+
+	_ = cmd.MarkFlagRequired("past")
+	_ = cmd.MarkFlagRequired("current")
+	_ = cmd.MarkFlagFilename("past", "json")
+	_ = cmd.MarkFlagFilename("current", "json")
+	_ = cmd.MarkFlagFilename("exchange-rates", "json", "yml")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"table", "json", "html", "html-report", "diff", "markdown", "csv", "xlsx", "sarif", "junit", "prometheus"}, cobra.ShellCompDirectiveDefault
+	})
+
+	return cmd
+}
@@ -0,0 +1,33 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestCompareHelp(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"compare", "--help"}, nil)
+}
+
+func TestCompareFormatDiff(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"compare", "--past", "./testdata/compare_past_out.json", "--current", "./testdata/example_out.json"}, nil)
+}
+
+func TestCompareFormatJSON(t *testing.T) {
+	opts := DefaultOptions()
+	opts.IsJSON = true
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"compare", "--past", "./testdata/compare_past_out.json", "--current", "./testdata/example_out.json", "--format", "json"}, opts)
+}
+
+func TestCompareFormatMarkdownNewProject(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"compare", "--past", "./testdata/compare_past_out.json", "--current", "./testdata/example_out.json", "--current", "./testdata/azure_firewall_out.json", "--format", "markdown"}, nil)
+}
+
+func TestCompareMissingFile(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"compare", "--past", "./testdata/does_not_exist.json", "--current", "./testdata/example_out.json"}, nil)
+}
+
+func TestCompareMovedResource(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"compare", "--past", "./testdata/example_out.json", "--current", "./testdata/compare_moved_out.json"}, nil)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/ui"
@@ -10,7 +11,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var validConfigureKeys = []string{"api_key", "pricing_api_endpoint", "currency"}
+var validConfigureKeys = []string{"api_key", "pricing_api_endpoint", "currency", "pricing_cache_ttl"}
 
 func configureCmd(ctx *config.RunContext) *cobra.Command {
 	cmd := &cobra.Command{
@@ -22,6 +23,7 @@ Supported settings:
   - api_key: Infracost API key
   - pricing_api_endpoint: endpoint of the Cloud Pricing API
   - currency: convert output from USD to your preferred currency
+  - pricing_cache_ttl: how long to cache Cloud Pricing API results for, e.g. 12h
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Show the help
@@ -44,6 +46,7 @@ Supported settings:
   - api_key: Infracost API key
   - pricing_api_endpoint: endpoint of the Cloud Pricing API
   - currency: convert output from USD to your preferred currency
+  - pricing_cache_ttl: how long to cache Cloud Pricing API results for, e.g. 12h
 `,
 		Example: `  Set your Infracost API key:
 
@@ -55,7 +58,11 @@ Supported settings:
 
   Set your preferred currency code (ISO 4217):
 
-      infracost	configure set currency EUR`,
+      infracost	configure set currency EUR
+
+  Set how long Cloud Pricing API results are cached for:
+
+      infracost	configure set pricing_cache_ttl 12h`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 2 {
 				return errors.New("Too many arguments")
@@ -102,6 +109,19 @@ Supported settings:
 				}
 			}
 
+			if key == "pricing_cache_ttl" {
+				if _, err := time.ParseDuration(value); err != nil {
+					return fmt.Errorf("Invalid duration %s, use a value like 30m or 12h", value)
+				}
+
+				ctx.Config.Configuration.PricingCacheTTL = value
+
+				err := ctx.Config.Configuration.Save()
+				if err != nil {
+					return err
+				}
+			}
+
 			return nil
 		},
 	}
@@ -119,6 +139,7 @@ Supported settings:
   - api_key: Infracost API key
   - pricing_api_endpoint: endpoint of the Cloud Pricing API
   - currency: convert output from USD to your preferred currency
+  - pricing_cache_ttl: how long to cache Cloud Pricing API results for, e.g. 12h
 `,
 		Example: `  Get your saved Infracost API key:
 
@@ -130,7 +151,11 @@ Supported settings:
 
   Get your preferred currency:
 
-      infracost	configure get currency`,
+      infracost	configure get currency
+
+  Get how long Cloud Pricing API results are cached for:
+
+      infracost	configure get pricing_cache_ttl`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 1 {
 				return errors.New("Too many arguments")
@@ -181,6 +206,17 @@ Supported settings:
 					)
 					ui.PrintWarning(cmd.ErrOrStderr(), msg)
 				}
+			} else if key == "pricing_cache_ttl" {
+				value = ctx.Config.Configuration.PricingCacheTTL
+
+				if value == "" {
+					msg := fmt.Sprintf("No pricing cache TTL in your saved config (%s), defaulting to %s.\nSet a pricing cache TTL using %s.",
+						config.ConfigurationFilePath(),
+						ctx.Config.PricingCacheTTL,
+						ui.PrimaryString("infracost configure set pricing_cache_ttl 12h"),
+					)
+					ui.PrintWarning(cmd.ErrOrStderr(), msg)
+				}
 			}
 
 			if value != "" {
@@ -20,10 +20,24 @@ func diffCmd(ctx *config.RunContext) *cobra.Command {
 
       terraform plan -out tfplan.binary
       terraform show -json tfplan.binary > plan.json
-      infracost diff --path plan.json`,
+      infracost diff --path plan.json
+
+  Compare a Terraform directory with a Terraform state JSON file:
+
+      terraform show -json > state.json
+      infracost diff --path /path/to/code --compare-to state.json
+
+  Compare two CloudFormation templates:
+
+      infracost diff --path new-template.yml --compare-to old-template.yml
+
+  Show the cost of a CloudFormation change set created from a template:
+
+      aws cloudformation describe-change-set --stack-name my-stack --change-set-name my-change-set > change-set.json
+      infracost diff --path template.yml --compare-to change-set.json`,
 		ValidArgs: []string{"--", "-"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+			if err := checkPricingSource(cmd, ctx.Config); err != nil {
 				return err
 			}
 
@@ -44,7 +58,9 @@ func diffCmd(ctx *config.RunContext) *cobra.Command {
 				return err
 			}
 
-			ctx.Config.Format = "diff"
+			if ctx.Config.Format != "markdown" {
+				ctx.Config.Format = "diff"
+			}
 
 			return runMain(cmd, ctx)
 		},
@@ -52,6 +68,14 @@ func diffCmd(ctx *config.RunContext) *cobra.Command {
 
 	addRunFlags(cmd)
 
+	cmd.Flags().String("format", "diff", "Output format: diff, markdown")
+	cmd.Flags().String("compare-to", "", "Path to compare the costs with instead of the prior state, e.g. a Terraform state JSON file,\nCloudFormation template or CloudFormation change set JSON file")
+	_ = cmd.MarkFlagFilename("compare-to", "json", "yml", "yaml")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"diff", "markdown"}, cobra.ShellCompDirectiveDefault
+	})
+
 	return cmd
 }
 
@@ -47,3 +47,21 @@ func TestDiffTerraform_v0_12(t *testing.T) {
 func TestDiffTerraform_v0_14(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"diff", "--path", "./testdata/terraform_v0.14_plan.json"}, nil)
 }
+
+func TestDiffCompareToTerraformState(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"diff", "--path", "./testdata/aws_instances_plan.json", "--compare-to", "./testdata/aws_instances_state.json", "--pricing-snapshot", "./testdata/aws_instances_prices.json"},
+		nil)
+}
+
+func TestDiffCompareToCloudFormationTemplate(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"diff", "--path", "./testdata/cloudformation_dynamodb_v2.json", "--compare-to", "./testdata/cloudformation_dynamodb_v1.json", "--pricing-snapshot", "./testdata/aws_dynamodb_prices.json"},
+		nil)
+}
+
+func TestDiffCompareToCloudFormationChangeSet(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"diff", "--path", "./testdata/cloudformation_dynamodb_v2.json", "--compare-to", "./testdata/cloudformation_dynamodb_change_set.json", "--pricing-snapshot", "./testdata/aws_dynamodb_prices.json"},
+		nil)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/explore"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/mattn/go-isatty"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func exploreCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explore",
+		Short: "Explore a breakdown of costs in an interactive terminal UI",
+		Long: `Explore a breakdown of costs in an interactive terminal UI.
+
+The path can be an Infracost JSON file, or anything that infracost breakdown
+accepts, in which case the breakdown is run first. Resources and sub-resources
+can be expanded and collapsed, sorted by cost, filtered by type or tag and
+searched by address. Projects with a previous breakdown can be switched between
+the past, current and diff views.`,
+		Example: `  Explore an Infracost JSON file:
+
+      infracost breakdown --path plan.json --format json > infracost.json
+      infracost explore --path infracost.json
+
+  Run a breakdown of a Terraform directory and explore it:
+
+      infracost explore --path /path/to/code`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !isatty.IsTerminal(os.Stdout.Fd()) && !isatty.IsCygwinTerminal(os.Stdout.Fd()) {
+				return errors.New("explore needs an interactive terminal. Use infracost breakdown to output the costs instead")
+			}
+
+			r, err := loadExploreOutput(cmd, ctx)
+			if err != nil {
+				return err
+			}
+
+			opts := output.Options{
+				DashboardEnabled: ctx.Config.EnableDashboard,
+			}
+
+			p := tea.NewProgram(explore.New(r, opts), tea.WithAltScreen())
+			return p.Start()
+		},
+	}
+
+	addRunFlags(cmd)
+
+	cmd.Flags().Bool("terraform-use-state", false, "Use Terraform state instead of generating a plan. Applicable when path is a Terraform directory")
+
+	return cmd
+}
+
+// loadExploreOutput loads the path if it's an Infracost JSON file, otherwise
+// it runs a breakdown.
+func loadExploreOutput(cmd *cobra.Command, ctx *config.RunContext) (output.Root, error) {
+	path, _ := cmd.Flags().GetString("path")
+	if path != "" && isInfracostJSON(path) {
+		return loadInfracostJSON(path)
+	}
+
+	if err := checkPricingSource(cmd, ctx.Config); err != nil {
+		return output.Root{}, err
+	}
+
+	err := loadRunFlags(ctx.Config, cmd)
+	if err != nil {
+		return output.Root{}, err
+	}
+
+	ctx.SetContextValue("outputFormat", "explore")
+
+	err = checkRunConfig(cmd.ErrOrStderr(), ctx.Config)
+	if err != nil {
+		ui.PrintUsage(cmd)
+		return output.Root{}, err
+	}
+
+	return runBreakdown(cmd, ctx)
+}
+
+// isInfracostJSON returns true if the file is an Infracost JSON file rather
+// than a Terraform plan or state JSON file, which don't have a version string
+// and projects.
+func isInfracostJSON(path string) bool {
+	if !strings.HasSuffix(strings.ToLower(path), ".json") {
+		return false
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	var j struct {
+		Version  interface{}     `json:"version"`
+		Projects json.RawMessage `json:"projects"`
+	}
+
+	if err := json.Unmarshal(data, &j); err != nil {
+		return false
+	}
+
+	_, ok := j.Version.(string)
+	return ok && j.Projects != nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Rhymond/go-money"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Timeouts of the exporter's HTTP server. Scrapes re-read the Infracost JSON
+// files, so the write timeout allows for large files.
+const (
+	exporterReadTimeout  = 30 * time.Second
+	exporterWriteTimeout = 60 * time.Second
+	exporterIdleTimeout  = 120 * time.Second
+)
+
+func exporterCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exporter",
+		Short: "Serve the costs of Infracost JSON files as Prometheus metrics",
+		Long: `Serve the costs of Infracost JSON files as Prometheus metrics.
+
+The files are re-read on every scrape, so a job that regularly writes new
+Infracost JSON files to the paths keeps the metrics up to date. The metrics are
+the same as infracost output --format prometheus.`,
+		Example: `  Serve the metrics of Infracost JSON files on port 9184:
+
+      infracost exporter --path "out*.json" --listen :9184
+
+  Add the team and env tags as labels:
+
+      infracost exporter --path "out*.json" --metric-tags team,env`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths, _ := cmd.Flags().GetStringArray("path")
+
+			currency, _ := cmd.Flags().GetString("currency")
+			currency = strings.ToUpper(currency)
+			if currency != "" && money.GetCurrency(currency) == nil {
+				return fmt.Errorf("Unknown currency %s", currency)
+			}
+
+			rateSource := ctx.Config.ExchangeRates
+			if cmd.Flags().Changed("exchange-rates") {
+				rateSource, _ = cmd.Flags().GetString("exchange-rates")
+			}
+
+			opts := output.Options{
+				DashboardEnabled: ctx.Config.EnableDashboard,
+				GroupKey:         "filename",
+				GroupLabel:       "File",
+			}
+			opts.MetricTags, _ = cmd.Flags().GetStringSlice("metric-tags")
+
+			// Check the files can be loaded and the metrics generated before
+			// serving them
+			_, err := metrics(paths, currency, rateSource, opts)
+			if err != nil {
+				return err
+			}
+
+			listen, _ := cmd.Flags().GetString("listen")
+
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", metricsHandler(paths, currency, rateSource, opts))
+
+			server := &http.Server{
+				Addr:              listen,
+				Handler:           mux,
+				ReadHeaderTimeout: exporterReadTimeout,
+				ReadTimeout:       exporterReadTimeout,
+				WriteTimeout:      exporterWriteTimeout,
+				IdleTimeout:       exporterIdleTimeout,
+			}
+
+			ui.PrintSuccessf(cmd.ErrOrStderr(), "Serving metrics on %s/metrics", listen)
+
+			return server.ListenAndServe()
+		},
+	}
+
+	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files")
+	cmd.Flags().String("listen", ":9184", "Address to listen on")
+	cmd.Flags().String("currency", "", "Currency to combine the files in. Defaults to the currency of the first file")
+	cmd.Flags().String("exchange-rates", "", "Path or URL of exchange rates used to convert files to the same currency")
+	cmd.Flags().StringSlice("metric-tags", []string{}, "Comma separated list of resource tags to add as labels to the metrics")
+
+	_ = cmd.MarkFlagRequired("path")
+	_ = cmd.MarkFlagFilename("path", "json")
+	_ = cmd.MarkFlagFilename("exchange-rates", "json", "yml")
+
+	return cmd
+}
+
+// metricsHandler serves the costs of the Infracost JSON files in the
+// Prometheus text format. The files are loaded on each request.
+func metricsHandler(paths []string, currency string, rateSource string, opts output.Options) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := metrics(paths, currency, rateSource, opts)
+		if err != nil {
+			log.Errorf("Error generating metrics: %s", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = w.Write(b)
+	})
+}
+
+func metrics(paths []string, currency string, rateSource string, opts output.Options) ([]byte, error) {
+	inputs, c, err := loadReportInputs(paths, currency, rateSource)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error loading Infracost JSON files")
+	}
+
+	return output.ToPrometheus(output.Combine(c, inputs, opts), opts)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/infracost/infracost/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	server := httptest.NewServer(metricsHandler([]string{"./testdata/example_out.json"}, "", "", output.Options{}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), `infracost_monthly_cost{project="infracost/infracost/examples/terraform",resource_type="aws_instance",resource="aws_instance.web_app",provider="aws",currency="USD"} 742.64`)
+	assert.Contains(t, string(body), `infracost_total_monthly_cost{currency="USD"} 742.64`)
+}
+
+func TestMetricsHandlerMissingFile(t *testing.T) {
+	server := httptest.NewServer(metricsHandler([]string{"./testdata/does_not_exist.json"}, "", "", output.Options{}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestMetricsHandlerDuplicateTagLabels(t *testing.T) {
+	server := httptest.NewServer(metricsHandler([]string{"./testdata/example_out.json"}, "", "", output.Options{MetricTags: []string{"cost-center", "cost.center"}}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Contains(t, string(body), "Metric tags cost-center and cost.center both have the label name tag_cost_center")
+}
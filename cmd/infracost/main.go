@@ -7,10 +7,12 @@ import (
 	"runtime/debug"
 
 	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/clierror"
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/ui"
 	"github.com/infracost/infracost/internal/update"
 	"github.com/infracost/infracost/internal/version"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -44,6 +46,11 @@ func main() {
 
 		handleUpdateMessage(updateMessageChan)
 
+		var exitCodeErr *clierror.ExitCodeError
+		if errors.As(appErr, &exitCodeErr) {
+			os.Exit(exitCodeErr.ExitCode())
+		}
+
 		if appErr != nil || unexpectedErr != nil {
 			os.Exit(1)
 		}
@@ -92,6 +99,13 @@ func NewRootCommand(ctx *config.RunContext) *cobra.Command {
 	rootCmd.AddCommand(diffCmd(ctx))
 	rootCmd.AddCommand(breakdownCmd(ctx))
 	rootCmd.AddCommand(outputCmd(ctx))
+	rootCmd.AddCommand(compareCmd(ctx))
+	rootCmd.AddCommand(exploreCmd(ctx))
+	rootCmd.AddCommand(exporterCmd(ctx))
+	rootCmd.AddCommand(policyCmd(ctx))
+	rootCmd.AddCommand(cacheCmd(ctx))
+	rootCmd.AddCommand(pricesCmd(ctx))
+	rootCmd.AddCommand(pricingServerCmd(ctx))
 	rootCmd.AddCommand(completionCmd())
 
 	rootCmd.SetUsageTemplate(fmt.Sprintf(`%s{{if .Runnable}}
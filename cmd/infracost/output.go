@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/Rhymond/go-money"
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/output"
 	"github.com/infracost/infracost/internal/ui"
@@ -31,57 +32,55 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 
       infracost output --format html --path "out*.json" > output.html
 
-  Merge multiple Infracost JSON files:
+  Create an HTML report with charts and sortable tables that works offline, e.g. as a CI artifact:
 
-      infracost output --format json --path "out*.json"`,
-		ValidArgs: []string{"--", "-"},
-		RunE: func(cmd *cobra.Command, args []string) error {
-			inputFiles := []string{}
+      infracost output --format html-report --path "out*.json" > report.html
 
-			paths, _ := cmd.Flags().GetStringArray("path")
-			for _, path := range paths {
-				expanded, err := homedir.Expand(path)
-				if err != nil {
-					return errors.Wrap(err, "Failed to expand path")
-				}
+  Export the cost components of multiple Infracost JSON files to a spreadsheet:
 
-				matches, _ := filepath.Glob(expanded)
-				if len(matches) > 0 {
-					inputFiles = append(inputFiles, matches...)
-				} else {
-					inputFiles = append(inputFiles, path)
-				}
-			}
+      infracost output --format xlsx --path "out*.json" > costs.xlsx
 
-			inputs := make([]output.ReportInput, 0, len(inputFiles))
-			currency := ""
+  Show the cost per team and Terraform module across multiple Infracost JSON files:
 
-			for _, f := range inputFiles {
-				data, err := ioutil.ReadFile(f)
-				if err != nil {
-					return errors.Wrap(err, "Error reading JSON file")
-				}
+      infracost output --path "out*.json" --group-by tag:team,module
 
-				j, err := output.Load(data)
-				if err != nil {
-					return errors.Wrap(err, "Error parsing JSON file")
-				}
+  Check the costs of multiple Infracost JSON files against the budgets in a config file:
 
-				if !checkOutputVersion(j.Version) {
-					return fmt.Errorf("Invalid Infracost JSON file version. Supported versions are %s ≤ x ≤ %s", minOutputVersion, maxOutputVersion)
-				}
+      infracost output --path "out*.json" --config-file infracost.yml
 
-				currency, err = checkCurrency(currency, j.Currency)
-				if err != nil {
-					return err
-				}
+  Upload the skipped resources, missing costs and budget violations to a code scanning tool:
+
+      infracost output --format sarif --path "out*.json" > infracost.sarif
+
+  Push the costs to a Prometheus Pushgateway, labelled by the team tag:
+
+      infracost output --format prometheus --path "out*.json" --metric-tags team | curl --data-binary @- http://pushgateway:9091/metrics/job/infracost
+
+  Merge multiple Infracost JSON files:
+
+      infracost output --format json --path "out*.json"
+
+  Merge Infracost JSON files with different currencies into EUR:
 
-				inputs = append(inputs, output.ReportInput{
-					Metadata: map[string]string{
-						"filename": f,
-					},
-					Root: j,
-				})
+      infracost output --path "out*.json" --currency EUR --exchange-rates rates.json`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths, _ := cmd.Flags().GetStringArray("path")
+
+			currency, _ := cmd.Flags().GetString("currency")
+			currency = strings.ToUpper(currency)
+			if currency != "" && money.GetCurrency(currency) == nil {
+				return fmt.Errorf("Unknown currency %s", currency)
+			}
+
+			rateSource := ctx.Config.ExchangeRates
+			if cmd.Flags().Changed("exchange-rates") {
+				rateSource, _ = cmd.Flags().GetString("exchange-rates")
+			}
+
+			inputs, currency, err := loadReportInputs(paths, currency, rateSource)
+			if err != nil {
+				return err
 			}
 
 			format, _ := cmd.Flags().GetString("format")
@@ -116,34 +115,46 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 				Fields:           fields,
 			}
 			opts.ShowSkipped, _ = cmd.Flags().GetBool("show-skipped")
+			opts.MetricTags, _ = cmd.Flags().GetStringSlice("metric-tags")
+
+			groupBy, _ := cmd.Flags().GetStringSlice("group-by")
+			if err := output.ValidateGroupBy(groupBy); err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
 
 			combined := output.Combine(currency, inputs, opts)
+			combined.Rollups = output.BuildRollups(combined.Projects, groupBy)
+
+			if cmd.Flags().Changed("config-file") {
+				cfgFilePath, _ := cmd.Flags().GetString("config-file")
+				cfgFile, err := config.LoadConfigFile(cfgFilePath)
+				if err != nil {
+					return err
+				}
 
-			var (
-				b   []byte
-				err error
-			)
+				combined.PolicyViolations = output.EvaluateBudgets(combined, cfgFile.Policies, opts)
+			}
 
 			validFieldsFormats := []string{"table", "html"}
 
 			if cmd.Flags().Changed("fields") && !contains(validFieldsFormats, format) {
 				ui.PrintWarning(cmd.ErrOrStderr(), "fields is only supported for table and html output formats")
 			}
-			switch strings.ToLower(format) {
-			case "json":
-				b, err = output.ToJSON(combined, opts)
-			case "html":
-				b, err = output.ToHTML(combined, opts)
-			case "diff":
-				b, err = output.ToDiff(combined, opts)
-			default:
-				b, err = output.ToTable(combined, opts)
+
+			b, err := formatOutput(format, combined, opts)
+			if err != nil {
+				return err
 			}
+
+			err = writeOutput(cmd, format, b, false)
 			if err != nil {
 				return err
 			}
 
-			cmd.Println(string(b))
+			if len(combined.PolicyViolations) > 0 {
+				return policyViolationsError(combined.PolicyViolations)
+			}
 
 			return nil
 		},
@@ -153,21 +164,177 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 	_ = cmd.MarkFlagRequired("path")
 	_ = cmd.MarkFlagFilename("path", "json")
 
-	cmd.Flags().String("format", "table", "Output format: json, diff, table, html")
+	cmd.Flags().String("format", "table", "Output format: json, diff, table, html, html-report, markdown, csv, xlsx, sarif, junit, prometheus")
 	cmd.Flags().Bool("show-skipped", false, "Show unsupported resources, some of which might be free")
+	cmd.Flags().String("currency", "", "Currency to combine the files in. Defaults to the currency of the first file")
+	cmd.Flags().String("exchange-rates", "", "Path or URL of exchange rates used to convert files to the same currency")
 	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: all,price,monthlyQuantity,unit,hourlyCost,monthlyCost.\nSupported by table and html output formats")
+	cmd.Flags().StringSlice("group-by", []string{}, "Comma separated list of dimensions to subtotal costs by: tag:<key>, type, module, provider, region.\nSupported by table and json output formats")
+	cmd.Flags().String("config-file", "", "Path to Infracost config file with budget policies to check the costs against")
+	cmd.Flags().StringSlice("metric-tags", []string{}, "Comma separated list of resource tags to add as labels to the metrics.\nSupported by prometheus output format")
+	_ = cmd.MarkFlagFilename("exchange-rates", "json", "yml")
+	_ = cmd.MarkFlagFilename("config-file", "yml")
 
 	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"table", "json", "html"}, cobra.ShellCompDirectiveDefault
+		return []string{"table", "json", "html", "html-report", "diff", "markdown", "csv", "xlsx", "sarif", "junit", "prometheus"}, cobra.ShellCompDirectiveDefault
 	})
 
 	return cmd
 }
 
-func checkCurrency(inputCurrency, fileCurrency string) (string, error) {
-	if fileCurrency == "" {
-		fileCurrency = "USD" // default to USD
+// formatOutput renders the output in the format. Unknown formats are rendered
+// as a table.
+func formatOutput(format string, r output.Root, opts output.Options) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		return output.ToJSON(r, opts)
+	case "html":
+		return output.ToHTML(r, opts)
+	case "html-report":
+		return output.ToHTMLReport(r, opts)
+	case "diff":
+		return output.ToDiff(r, opts)
+	case "markdown":
+		return output.ToMarkdown(r, opts)
+	case "csv":
+		return output.ToCSV(r, opts)
+	case "xlsx":
+		return output.ToXLSX(r, opts)
+	case "sarif":
+		return output.ToSARIF(r, opts)
+	case "junit":
+		return output.ToJUnit(r, opts)
+	case "prometheus":
+		return output.ToPrometheus(r, opts)
+	default:
+		return output.ToTable(r, opts)
 	}
+}
+
+// writeOutput writes the rendered output to stdout. The xlsx workbook is binary
+// so it's written as is. If padTable is set, the table and diff outputs start
+// with an empty line so they're separated from the progress output.
+func writeOutput(cmd *cobra.Command, format string, b []byte, padTable bool) error {
+	switch strings.ToLower(format) {
+	case "xlsx":
+		_, err := cmd.OutOrStdout().Write(b)
+		return err
+	case "json", "html", "html-report", "markdown", "csv", "sarif", "junit", "prometheus":
+		cmd.Printf("%s\n", b)
+	default:
+		if padTable {
+			cmd.Printf("\n%s\n", b)
+		} else {
+			cmd.Printf("%s\n", b)
+		}
+	}
+
+	return nil
+}
+
+// loadReportInputs loads the Infracost JSON files matching the paths. If an
+// exchange rate source is set the files are converted to the currency, or the
+// currency of the first file if it's empty. It returns the currency the files
+// can be combined in.
+func loadReportInputs(paths []string, currency string, rateSource string) ([]output.ReportInput, string, error) {
+	inputFiles, err := expandPaths(paths)
+	if err != nil {
+		return nil, currency, err
+	}
+
+	inputs := make([]output.ReportInput, 0, len(inputFiles))
+
+	var exchangeRates *output.ExchangeRates
+	if rateSource != "" {
+		exchangeRates, err = output.NewRateSource(rateSource).ExchangeRates()
+		if err != nil {
+			return nil, currency, err
+		}
+	}
+
+	for _, f := range inputFiles {
+		j, err := loadInfracostJSON(f)
+		if err != nil {
+			return nil, currency, err
+		}
+
+		if exchangeRates != nil {
+			if currency == "" {
+				currency = currencyOrDefault(j.Currency)
+			}
+
+			err = output.ConvertCurrency(&j, currency, exchangeRates)
+			if err != nil {
+				return nil, currency, errors.Wrapf(err, "Error converting %s to %s", f, currency)
+			}
+		}
+
+		currency, err = checkCurrency(currency, j.Currency)
+		if err != nil {
+			return nil, currency, err
+		}
+
+		inputs = append(inputs, output.ReportInput{
+			Metadata: map[string]string{
+				"filename": f,
+			},
+			Root: j,
+		})
+	}
+
+	return inputs, currency, nil
+}
+
+// expandPaths expands the home directory and globs in the paths.
+func expandPaths(paths []string) ([]string, error) {
+	expandedPaths := []string{}
+
+	for _, path := range paths {
+		expanded, err := homedir.Expand(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to expand path")
+		}
+
+		matches, _ := filepath.Glob(expanded)
+		if len(matches) > 0 {
+			expandedPaths = append(expandedPaths, matches...)
+		} else {
+			expandedPaths = append(expandedPaths, path)
+		}
+	}
+
+	return expandedPaths, nil
+}
+
+// loadInfracostJSON reads an Infracost JSON file and checks its version is
+// supported.
+func loadInfracostJSON(path string) (output.Root, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return output.Root{}, errors.Wrap(err, "Error reading JSON file")
+	}
+
+	j, err := output.Load(data)
+	if err != nil {
+		return output.Root{}, errors.Wrap(err, "Error parsing JSON file")
+	}
+
+	if !checkOutputVersion(j.Version) {
+		return output.Root{}, fmt.Errorf("Invalid Infracost JSON file version. Supported versions are %s ≤ x ≤ %s", minOutputVersion, maxOutputVersion)
+	}
+
+	return j, nil
+}
+
+func currencyOrDefault(currency string) string {
+	if currency == "" {
+		return "USD" // default to USD
+	}
+	return currency
+}
+
+func checkCurrency(inputCurrency, fileCurrency string) (string, error) {
+	fileCurrency = currencyOrDefault(fileCurrency)
 
 	if inputCurrency == "" {
 		// this must be the first file, save the input currency
@@ -175,7 +342,7 @@ func checkCurrency(inputCurrency, fileCurrency string) (string, error) {
 	}
 
 	if inputCurrency != fileCurrency {
-		return "", fmt.Errorf("Invalid Infracost JSON file currency mismatch.  Can't combine %s and %s", inputCurrency, fileCurrency)
+		return "", fmt.Errorf("Invalid Infracost JSON file currency mismatch.  Can't combine %s and %s.  Use --exchange-rates to convert them", inputCurrency, fileCurrency)
 	}
 
 	return inputCurrency, nil
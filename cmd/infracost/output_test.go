@@ -19,6 +19,22 @@ func TestOutputFormatJSON(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "json", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, opts)
 }
 
+func TestOutputFormatMarkdown(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "markdown", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, nil)
+}
+
+func TestOutputFormatCSV(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "csv", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, nil)
+}
+
+func TestOutputFormatSARIF(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "sarif", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, nil)
+}
+
+func TestOutputFormatJUnit(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "junit", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, nil)
+}
+
 func TestOutputFormatTable(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "table", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, nil)
 }
@@ -26,3 +42,23 @@ func TestOutputFormatTable(t *testing.T) {
 func TestOutputTerraformFieldsAll(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json", "--fields", "all"}, nil)
 }
+
+func TestOutputExchangeRates(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--path", "./testdata/example_out.json", "--path", "./testdata/aws_instances_eur_out.json", "--exchange-rates", "./testdata/exchange_rates.json", "--currency", "GBP"}, nil)
+}
+
+func TestOutputCurrencyMismatch(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--path", "./testdata/example_out.json", "--path", "./testdata/aws_instances_eur_out.json"}, nil)
+}
+
+func TestOutputBudgets(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "diff", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json", "--config-file", "./testdata/output_budgets.yml"}, nil)
+}
+
+func TestOutputFormatHTMLReport(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "html-report", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, nil)
+}
+
+func TestOutputFormatPrometheus(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "prometheus", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json", "--metric-tags", "environment"}, nil)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/infracost/infracost/internal/clierror"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/policy"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func policyCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Check Infracost JSON files against Rego policies",
+		Long:  "Check Infracost JSON files against Rego policies",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Show the help
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(policyCheckCmd(ctx))
+
+	return cmd
+}
+
+func policyCheckCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Evaluate Rego policies against Infracost JSON files",
+		Long: `Evaluate Rego policies against Infracost JSON files.
+
+Policies are written in the infracost package and add messages to the deny
+and warn sets. The input is the Infracost JSON with a resourceType added to
+each resource and sub-resource. A message can be a string, or an object with
+a msg and the resource address or project it's for:
+
+  package infracost
+
+  deny[{"msg": msg, "resource": r.name}] {
+    r := input.projects[_].breakdown.resources[_]
+    r.resourceType == "aws_instance"
+    to_number(r.monthlyCost) > 500
+    msg := sprintf("costs %v per month, more than the limit of 500", [r.monthlyCost])
+  }
+
+The command fails with exit code 3 if there are any deny results.`,
+		Example: `  Check an Infracost JSON file against the policies in a directory:
+
+      infracost breakdown --path plan.json --format json > infracost.json
+      infracost policy check --policy policies/ --path infracost.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths, _ := cmd.Flags().GetStringArray("path")
+			inputFiles, err := expandPaths(paths)
+			if err != nil {
+				return err
+			}
+
+			policyPaths, _ := cmd.Flags().GetStringArray("policy")
+
+			evaluator, err := policy.NewEvaluator(cmd.Context(), policyPaths)
+			if err != nil {
+				return err
+			}
+
+			inputs := make([]output.ReportInput, 0, len(inputFiles))
+			currency := ""
+
+			for _, f := range inputFiles {
+				j, err := loadInfracostJSON(f)
+				if err != nil {
+					return err
+				}
+
+				currency, err = checkCurrency(currency, j.Currency)
+				if err != nil {
+					return err
+				}
+
+				inputs = append(inputs, output.ReportInput{
+					Metadata: map[string]string{
+						"filename": f,
+					},
+					Root: j,
+				})
+			}
+
+			combined := output.Combine(currency, inputs, output.Options{})
+
+			results, err := evaluator.Eval(cmd.Context(), combined)
+			if err != nil {
+				return err
+			}
+
+			format, _ := cmd.Flags().GetString("format")
+			switch strings.ToLower(format) {
+			case "json":
+				b, err := json.Marshal(results)
+				if err != nil {
+					return err
+				}
+				cmd.Println(string(b))
+			default:
+				writePolicyResults(cmd.OutOrStdout(), results)
+			}
+
+			if len(results.Deny) > 0 {
+				err := clierror.NewSanitizedError(fmt.Errorf("Policy check failed: %d denied", len(results.Deny)), "Policy check failed")
+				return clierror.NewExitCodeError(err, clierror.PolicyViolationExitCode)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files")
+	cmd.Flags().StringArray("policy", []string{}, "Path to a Rego policy file or a directory of them")
+	cmd.Flags().String("format", "table", "Output format: table, json")
+
+	_ = cmd.MarkFlagRequired("path")
+	_ = cmd.MarkFlagRequired("policy")
+	_ = cmd.MarkFlagFilename("path", "json")
+	_ = cmd.MarkFlagFilename("policy", "rego")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"table", "json"}, cobra.ShellCompDirectiveDefault
+	})
+
+	return cmd
+}
+
+func writePolicyResults(w io.Writer, results *policy.Results) {
+	lines := make([]string, 0, len(results.Deny)+len(results.Warn))
+
+	for _, r := range results.Deny {
+		lines = append(lines, fmt.Sprintf("%s %s", ui.ErrorString("DENY"), policyResultMessage(r)))
+	}
+
+	for _, r := range results.Warn {
+		lines = append(lines, fmt.Sprintf("%s %s", ui.WarningString("WARN"), policyResultMessage(r)))
+	}
+
+	if len(lines) > 0 {
+		fmt.Fprintf(w, "%s\n\n", strings.Join(lines, "\n"))
+	}
+
+	fmt.Fprintf(w, "%d denied, %d warnings\n", len(results.Deny), len(results.Warn))
+}
+
+func policyResultMessage(r policy.Result) string {
+	prefix := ""
+
+	if r.Project != "" {
+		prefix = r.Project
+	}
+
+	if r.Resource != "" {
+		if prefix != "" {
+			prefix += ": "
+		}
+		prefix += r.Resource
+	}
+
+	if prefix == "" {
+		return r.Message
+	}
+
+	return fmt.Sprintf("%s: %s", ui.BoldString(prefix), r.Message)
+}
@@ -0,0 +1,21 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestPolicyCheckHelp(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"policy", "check", "--help"}, nil)
+}
+
+func TestPolicyCheck(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"policy", "check", "--policy", "./testdata/policies", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, nil)
+}
+
+func TestPolicyCheckFormatJSON(t *testing.T) {
+	opts := DefaultOptions()
+	opts.IsJSON = true
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"policy", "check", "--format", "json", "--policy", "./testdata/policies/projects.rego", "--path", "./testdata/azure_firewall_out.json"}, opts)
+}
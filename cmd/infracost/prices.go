@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/prices"
+	"github.com/infracost/infracost/internal/pricestore"
+	"github.com/infracost/infracost/internal/providers"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/infracost/infracost/internal/usage"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func pricesCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prices",
+		Short: "Manage local price snapshots",
+		Long:  "Manage local price snapshots",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Show the help
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(pricesExportCmd(ctx))
+
+	return cmd
+}
+
+func pricesExportCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the prices used by a project or resource types to a price snapshot file",
+		Long: `Export the prices used by a project or resource types to a price snapshot file.
+
+The snapshot contains every product and price matched by the resources in the
+project. It can be used with the --pricing-snapshot flag to run infracost
+breakdown and diff without access to the Cloud Pricing API.
+
+Use the --resource-type and --region flags instead of a path to export the
+prices of every product the resource types can use in the region, whatever
+their attributes are.`,
+		Example: `  Export the prices for a Terraform directory:
+
+      infracost prices export --path /path/to/code --out prices.json
+
+  Export the prices for AWS instances and RDS databases in us-east-1:
+
+      infracost prices export --resource-type aws_instance,aws_db_instance --region us-east-1 --out prices.json
+
+  Use the snapshot on a machine with no network access:
+
+      infracost breakdown --path /path/to/code --pricing-snapshot prices.json`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+				return err
+			}
+
+			resourceTypes, _ := cmd.Flags().GetStringSlice("resource-type")
+			region, _ := cmd.Flags().GetString("region")
+
+			if len(resourceTypes) > 0 && (cmd.Flags().Changed("path") || cmd.Flags().Changed("config-file")) {
+				ui.PrintUsage(cmd)
+				return errors.New("--resource-type flag cannot be used with the --path or --config-file flags")
+			}
+
+			if len(resourceTypes) > 0 && region == "" {
+				ui.PrintUsage(cmd)
+				return errors.New("--region flag is required with the --resource-type flag")
+			}
+
+			var (
+				resources []*schema.Resource
+				projects  []*schema.Project
+				err       error
+			)
+
+			if len(resourceTypes) > 0 {
+				resources, err = terraform.NewResourceTypeResources(resourceTypes, region)
+				if err != nil {
+					return err
+				}
+			} else {
+				err = loadRunFlags(ctx.Config, cmd)
+				if err != nil {
+					return err
+				}
+
+				projects, err = loadPricesExportProjects(ctx)
+				if err != nil {
+					return err
+				}
+			}
+
+			spinnerOpts := ui.SpinnerOptions{
+				EnableLogging: ctx.Config.IsLogging(),
+				NoColor:       ctx.Config.NoColor,
+			}
+			spinner = ui.NewSpinner("Exporting prices", spinnerOpts)
+
+			var s *pricestore.Store
+			if len(resourceTypes) > 0 {
+				s, err = prices.ExportResourceTypesSnapshot(ctx.Config, resources)
+			} else {
+				s, err = prices.ExportSnapshot(ctx.Config, projects)
+			}
+			if err != nil {
+				spinner.Fail()
+
+				if e := unwrapped(err); errors.Is(e, apiclient.ErrInvalidAPIKey) {
+					return fmt.Errorf("%v\nPlease check your %s file or %s environment variable.",
+						e.Error(),
+						ui.PrimaryString(config.CredentialsFilePath()),
+						ui.PrimaryString("INFRACOST_API_KEY"),
+					)
+				}
+
+				return err
+			}
+
+			out, _ := cmd.Flags().GetString("out")
+			err = s.Save(out)
+			if err != nil {
+				spinner.Fail()
+				return err
+			}
+
+			spinner.Success()
+
+			ui.PrintSuccessf(cmd.ErrOrStderr(), "Exported %d products to %s", len(s.Products), out)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("path", "p", "", "Path to the Terraform directory or JSON/plan file")
+	cmd.Flags().String("config-file", "", "Path to Infracost config file. Cannot be used with path, terraform* or usage-file flags")
+	cmd.Flags().String("usage-file", "", "Path to Infracost usage file that specifies values for usage-based resources")
+	cmd.Flags().String("terraform-plan-flags", "", "Flags to pass to 'terraform plan'. Applicable when path is a Terraform directory")
+	cmd.Flags().String("terraform-workspace", "", "Terraform workspace to use. Applicable when path is a Terraform directory")
+	cmd.Flags().Bool("terraform-use-state", false, "Use Terraform state instead of generating a plan. Applicable when path is a Terraform directory")
+	cmd.Flags().StringSlice("resource-type", []string{}, "Comma separated list of Terraform resource types to export the prices of, e.g. aws_instance. Cannot be used with path or config-file flags")
+	cmd.Flags().String("region", "", "Region to export the prices of the resource types in")
+	cmd.Flags().String("out", "", "Path to write the price snapshot file to")
+
+	_ = cmd.MarkFlagRequired("out")
+	_ = cmd.MarkFlagFilename("path", "json", "tf")
+	_ = cmd.MarkFlagFilename("config-file", "yml")
+	_ = cmd.MarkFlagFilename("usage-file", "yml")
+	_ = cmd.MarkFlagFilename("out", "json")
+
+	return cmd
+}
+
+func loadPricesExportProjects(runCtx *config.RunContext) ([]*schema.Project, error) {
+	projects := make([]*schema.Project, 0)
+
+	for _, projectCfg := range runCtx.Config.Projects {
+		ctx := config.NewProjectContext(runCtx, projectCfg)
+		runCtx.SetCurrentProjectContext(ctx)
+
+		provider, err := providers.Detect(ctx)
+		if err != nil {
+			return projects, err
+		}
+
+		m := fmt.Sprintf("Detected %s at %s", provider.DisplayType(), ui.DisplayPath(projectCfg.Path))
+		if runCtx.Config.IsLogging() {
+			log.Info(m)
+		} else {
+			fmt.Fprintln(os.Stderr, m)
+		}
+
+		u, err := usage.LoadFromFile(projectCfg.UsageFile, false)
+		if err != nil {
+			return projects, err
+		}
+
+		providerProjects, err := provider.LoadResources(u)
+		if err != nil {
+			return projects, err
+		}
+
+		projects = append(projects, providerProjects...)
+	}
+
+	return projects, nil
+}
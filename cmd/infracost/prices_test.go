@@ -0,0 +1,19 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestPricesExportResourceTypeWithoutRegion(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"prices", "export", "--resource-type", "aws_instance", "--out", "prices.json"}, nil)
+}
+
+func TestPricesExportResourceTypeAndPath(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"prices", "export", "--resource-type", "aws_instance", "--region", "us-east-1", "--path", "../../examples/terraform/plan.json", "--out", "prices.json"}, nil)
+}
+
+func TestPricesExportUnsupportedResourceType(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"prices", "export", "--resource-type", "aws_not_a_resource", "--region", "us-east-1", "--out", "prices.json"}, nil)
+}
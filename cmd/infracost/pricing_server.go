@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/pricestore"
+	"github.com/infracost/infracost/internal/pricingserver"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func pricingServerCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pricing-server",
+		Short: "Run a self-hosted pricing API from local price files",
+		Long: `Run a self-hosted pricing API from local price files.
+
+The server answers the same GraphQL queries as the Cloud Pricing API using a
+local price database, so no resource details are sent outside your network.
+Use infracost pricing-server load to build the database from the bulk price
+files published by the cloud vendors, then point infracost at the server:
+
+      infracost configure set pricing_api_endpoint http://localhost:4000`,
+		Example: `  Build a price database and serve it:
+
+      infracost pricing-server load --db prices.db --aws-price-list AmazonEC2.json
+      infracost pricing-server --db prices.db --listen :4000`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("db")
+
+			db, err := pricestore.OpenDB(path, true)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			server, err := pricingserver.NewServer(db)
+			if err != nil {
+				return err
+			}
+
+			count, err := db.ProductCount()
+			if err != nil {
+				return errors.Wrap(err, "Error reading price database")
+			}
+
+			listen, _ := cmd.Flags().GetString("listen")
+
+			ui.PrintSuccessf(cmd.ErrOrStderr(), "Serving %d products on %s", count, listen)
+
+			return server.HTTPServer(listen).ListenAndServe()
+		},
+	}
+
+	cmd.Flags().String("db", "", "Path to a price database created with infracost pricing-server load")
+	cmd.Flags().String("listen", ":4000", "Address to listen on")
+
+	_ = cmd.MarkFlagRequired("db")
+	_ = cmd.MarkFlagFilename("db", "db")
+
+	cmd.AddCommand(pricingServerLoadCmd(ctx))
+
+	return cmd
+}
+
+func pricingServerLoadCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "load",
+		Short: "Build a price database from bulk price files",
+		Long: `Build a price database from bulk price files.
+
+Supported files:
+  - AWS Price List bulk JSON offer files, e.g. https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonEC2/current/index.json
+  - Azure Retail Prices API JSON responses from https://prices.azure.com/api/retail/prices
+  - GCP Cloud Billing Catalog API SKU list JSON responses
+
+Prices keep the effective dates from the price files. Loading price files from
+different dates into the same database builds up the price history, which can
+be used with the --as-of flag of infracost breakdown and diff.`,
+		Example: `  Build a database from AWS and Azure price files:
+
+      infracost pricing-server load --db prices.db --aws-price-list AmazonEC2.json --azure-retail-prices azure-page-1.json
+
+  Add GCP prices to the database:
+
+      infracost pricing-server load --db prices.db --gcp-skus compute-skus.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("db")
+
+			db, err := pricestore.OpenDB(path, false)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			formats := []struct {
+				flag   string
+				format string
+			}{
+				{"aws-price-list", "aws"},
+				{"azure-retail-prices", "azure"},
+				{"gcp-skus", "gcp"},
+			}
+
+			for _, f := range formats {
+				files, _ := cmd.Flags().GetStringArray(f.flag)
+
+				for _, file := range files {
+					// Each file is imported into its own store so only one
+					// file is held in memory at a time
+					store := pricestore.NewStore()
+
+					count, err := store.ImportFile(f.format, file)
+					if err != nil {
+						return err
+					}
+
+					err = db.AddProducts(store.Products)
+					if err != nil {
+						return errors.Wrap(err, "Error writing price database")
+					}
+
+					cmd.PrintErrln(fmt.Sprintf("Imported %d products from %s", count, ui.DisplayPath(file)))
+				}
+			}
+
+			count, err := db.ProductCount()
+			if err != nil {
+				return errors.Wrap(err, "Error reading price database")
+			}
+
+			ui.PrintSuccessf(cmd.ErrOrStderr(), "Saved %d products to %s", count, path)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("db", "", "Path to the price database to add the prices to. It is created if it doesn't exist")
+	cmd.Flags().StringArray("aws-price-list", []string{}, "Path to an AWS Price List bulk JSON offer file. Can be repeated")
+	cmd.Flags().StringArray("azure-retail-prices", []string{}, "Path to an Azure Retail Prices API JSON file. Can be repeated")
+	cmd.Flags().StringArray("gcp-skus", []string{}, "Path to a GCP Cloud Billing Catalog API SKUs JSON file. Can be repeated")
+
+	_ = cmd.MarkFlagRequired("db")
+	_ = cmd.MarkFlagFilename("db", "db")
+	_ = cmd.MarkFlagFilename("aws-price-list", "json")
+	_ = cmd.MarkFlagFilename("azure-retail-prices", "json")
+	_ = cmd.MarkFlagFilename("gcp-skus", "json")
+
+	return cmd
+}
@@ -11,9 +11,11 @@ import (
 
 	"github.com/infracost/infracost/internal/apiclient"
 	"github.com/infracost/infracost/internal/clierror"
+	"github.com/infracost/infracost/internal/commitments"
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/output"
 	"github.com/infracost/infracost/internal/prices"
+	"github.com/infracost/infracost/internal/pricestore"
 	"github.com/infracost/infracost/internal/providers"
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
@@ -32,17 +34,107 @@ func addRunFlags(cmd *cobra.Command) {
 
 	cmd.Flags().String("terraform-plan-flags", "", "Flags to pass to 'terraform plan'. Applicable when path is a Terraform directory")
 	cmd.Flags().String("terraform-workspace", "", "Terraform workspace to use. Applicable when path is a Terraform directory")
+	cmd.Flags().String("terraform-source-dir", "", "Path to the Terraform files a plan or state JSON file was generated from, used to find the source locations of resources")
 
 	cmd.Flags().Bool("show-skipped", false, "Show unsupported resources, some of which might be free")
 
 	cmd.Flags().Bool("sync-usage-file", false, "Sync usage-file with missing resources, needs usage-file too (experimental)")
+	cmd.Flags().Bool("sync-usage-file-dry-run", false, "Show a diff of the changes sync-usage-file would make without writing usage-file")
+
+	cmd.Flags().String("pricing-snapshot", "", "Path to a price snapshot file to use instead of the Cloud Pricing API")
+	cmd.Flags().Bool("no-cache", false, "Don't use cached results from the Cloud Pricing API")
+	cmd.Flags().Bool("strict-pricing", false, "Fail if any cost component doesn't match exactly one price")
+	cmd.Flags().String("price-overrides", "", "Path to a price overrides file with negotiated prices, discounts and credits")
+	cmd.Flags().String("commitments", "", "Path to a file of AWS Reserved Instances and Savings Plans to allocate across the resources")
+	cmd.Flags().String("spot-price-history", "", "Path to an AWS spot price history JSON or CSV file to price spot instances with")
+	cmd.Flags().String("spot-price-statistic", "p50", "Statistic of the spot price history to use: mean, min, max or a percentile such as p90")
+	cmd.Flags().String("exchange-rates", "", "Path or URL of exchange rates used to convert USD prices to INFRACOST_CURRENCY")
+	cmd.Flags().String("as-of", "", "Use the prices effective on this date (YYYY-MM-DD). Needs a price snapshot or self-hosted pricing API with price history")
 
 	_ = cmd.MarkFlagFilename("path", "json", "tf")
 	_ = cmd.MarkFlagFilename("config-file", "yml")
 	_ = cmd.MarkFlagFilename("usage-file", "yml")
+	_ = cmd.MarkFlagDirname("terraform-source-dir")
+	_ = cmd.MarkFlagFilename("pricing-snapshot", "json")
+	_ = cmd.MarkFlagFilename("price-overrides", "yml")
+	_ = cmd.MarkFlagFilename("commitments", "yml")
+	_ = cmd.MarkFlagFilename("spot-price-history", "json", "csv")
+	_ = cmd.MarkFlagFilename("exchange-rates", "json", "yml")
+}
+
+// checkAsOf checks the as-of date is valid and that the prices can be fetched
+// for it. The Cloud Pricing API only has the current prices.
+func checkAsOf(cfg *config.Config) error {
+	if cfg.AsOf == "" {
+		return nil
+	}
+
+	if _, err := time.Parse(pricestore.EffectiveDateLayout, cfg.AsOf); err != nil {
+		return fmt.Errorf("Invalid --as-of date %s. Expected format: YYYY-MM-DD", cfg.AsOf)
+	}
+
+	if cfg.PricingSnapshot == "" && cfg.PricingAPIEndpoint == cfg.DefaultPricingAPIEndpoint {
+		return errors.New("--as-of needs a price snapshot or a self-hosted pricing API with price history since the Cloud Pricing API only has the current prices")
+	}
+
+	return nil
+}
+
+// checkPricingSource checks that prices can be fetched, either from a price
+// snapshot file or from the Cloud Pricing API.
+func checkPricingSource(cmd *cobra.Command, cfg *config.Config) error {
+	if cmd.Flags().Changed("pricing-snapshot") {
+		cfg.PricingSnapshot, _ = cmd.Flags().GetString("pricing-snapshot")
+	}
+
+	if cfg.PricingSnapshot != "" {
+		if !config.FileExists(cfg.PricingSnapshot) {
+			return fmt.Errorf("Price snapshot file does not exist at %s", cfg.PricingSnapshot)
+		}
+
+		return nil
+	}
+
+	return checkAPIKey(cfg.APIKey, cfg.PricingAPIEndpoint, cfg.DefaultPricingAPIEndpoint)
 }
 
 func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
+	r, err := runBreakdown(cmd, runCtx)
+	if err != nil {
+		return err
+	}
+
+	opts := output.Options{
+		DashboardEnabled: runCtx.Config.EnableDashboard,
+		ShowSkipped:      runCtx.Config.ShowSkipped,
+		NoColor:          runCtx.Config.NoColor,
+		Fields:           runCtx.Config.Fields,
+	}
+
+	b, err := formatOutput(runCtx.Config.Format, r, opts)
+	if err != nil {
+		return errors.Wrap(err, "Error generating output")
+	}
+
+	err = writeOutput(cmd, runCtx.Config.Format, b, true)
+	if err != nil {
+		return errors.Wrap(err, "Error writing output")
+	}
+
+	if len(r.PricingIssues) > 0 {
+		return clierror.NewSanitizedError(errors.New(output.PricingIssuesMessage(r.PricingIssues)), "Strict pricing failed")
+	}
+
+	if len(r.PolicyViolations) > 0 {
+		return policyViolationsError(r.PolicyViolations)
+	}
+
+	return nil
+}
+
+// runBreakdown loads the resources of the projects, prices them and returns
+// the output before it's rendered in a format.
+func runBreakdown(cmd *cobra.Command, runCtx *config.RunContext) (output.Root, error) {
 	projects := make([]*schema.Project, 0)
 	projectContexts := make([]*config.ProjectContext, 0)
 
@@ -62,7 +154,7 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 				m += "\n - Terraform state JSON file"
 			}
 
-			return clierror.NewSanitizedError(errors.New(m), "Could not detect path type")
+			return output.Root{}, clierror.NewSanitizedError(errors.New(m), "Could not detect path type")
 		}
 		ctx.SetContextValue("projectType", provider.Type())
 		projectContexts = append(projectContexts, ctx)
@@ -71,7 +163,8 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 			m := "Cannot use Terraform state JSON with the infracost diff command.\n\n"
 			m += fmt.Sprintf("Use the %s flag to specify the path to one of the following:\n", ui.PrimaryString("--path"))
 			m += " - Terraform plan JSON file\n - Terraform/Terragrunt directory\n - Terraform plan file"
-			return clierror.NewSanitizedError(errors.New(m), "Cannot use Terraform state JSON with the infracost diff command")
+			m += fmt.Sprintf("\n\nTo compare with the state, use the %s flag with the Terraform state JSON file", ui.PrimaryString("--compare-to"))
+			return output.Root{}, clierror.NewSanitizedError(errors.New(m), "Cannot use Terraform state JSON with the infracost diff command")
 		}
 
 		m := fmt.Sprintf("Detected %s at %s", provider.DisplayType(), ui.DisplayPath(projectCfg.Path))
@@ -81,9 +174,9 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 			fmt.Fprintln(os.Stderr, m)
 		}
 
-		u, err := usage.LoadFromFile(projectCfg.UsageFile, runCtx.Config.SyncUsageFile)
+		u, err := loadUsageFile(runCtx.Config, projectCfg.UsageFile)
 		if err != nil {
-			return err
+			return output.Root{}, err
 		}
 		if len(u) > 0 {
 			ctx.SetContextValue("hasUsageFile", true)
@@ -91,7 +184,12 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 
 		providerProjects, err := provider.LoadResources(u)
 		if err != nil {
-			return err
+			return output.Root{}, err
+		}
+
+		err = providers.LoadBaseline(ctx, providerProjects, u)
+		if err != nil {
+			return output.Root{}, err
 		}
 
 		if runCtx.Config.SyncUsageFile && projectCfg.UsageFile != "" {
@@ -102,24 +200,33 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 			}
 			spinner = ui.NewSpinner("Syncing usage data from cloud", spinnerOpts)
 
-			syncResult, err := usage.SyncUsageData(providerProjects, u, projectCfg.UsageFile)
+			syncResult, err := usage.SyncUsageData(providerProjects, u, projectCfg.UsageFile, runCtx.Config.SyncUsageFileDryRun)
 			summarizeUsage(ctx, syncResult)
 			if err != nil {
 				spinner.Fail()
-				return err
+				return output.Root{}, err
 			}
 
 			remediateUsage(runCtx, ctx, syncResult)
 
-			u, err := usage.LoadFromFile(projectCfg.UsageFile, runCtx.Config.SyncUsageFile)
-			if err != nil {
-				spinner.Fail()
-				return err
-			}
-			providerProjects, err = provider.LoadResources(u)
-			if err != nil {
-				spinner.Fail()
-				return err
+			// The usage file is unchanged on a dry run so the resources don't need reloading
+			if !runCtx.Config.SyncUsageFileDryRun {
+				u, err := usage.LoadFromFile(projectCfg.UsageFile, runCtx.Config.SyncUsageFile)
+				if err != nil {
+					spinner.Fail()
+					return output.Root{}, err
+				}
+				providerProjects, err = provider.LoadResources(u)
+				if err != nil {
+					spinner.Fail()
+					return output.Root{}, err
+				}
+
+				err = providers.LoadBaseline(ctx, providerProjects, u)
+				if err != nil {
+					spinner.Fail()
+					return output.Root{}, err
+				}
 			}
 
 			if syncResult == nil {
@@ -141,6 +248,10 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 					successes,
 					resources,
 					pluralized))
+
+				if runCtx.Config.SyncUsageFileDryRun {
+					printUsageFileDiff(cmd, projectCfg.UsageFile, syncResult.Diff)
+				}
 			}
 		}
 
@@ -151,36 +262,70 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 		}
 	}
 
+	var err error
+
+	var portfolio *commitments.Portfolio
+	if runCtx.Config.CommitmentsFile != "" {
+		portfolio, err = commitments.LoadPortfolio(runCtx.Config.CommitmentsFile)
+		if err != nil {
+			return output.Root{}, err
+		}
+	}
+
+	var exchangeRates *output.ExchangeRates
+	if runCtx.Config.ExchangeRates != "" {
+		exchangeRates, err = output.NewRateSource(runCtx.Config.ExchangeRates).ExchangeRates()
+		if err != nil {
+			return output.Root{}, err
+		}
+
+		// Check the rate exists before fetching any prices
+		_, err = exchangeRates.Rate(runCtx.Config.PricingCurrency(), runCtx.Config.Currency)
+		if err != nil {
+			return output.Root{}, err
+		}
+	}
+
 	spinnerOpts := ui.SpinnerOptions{
 		EnableLogging: runCtx.Config.IsLogging(),
 		NoColor:       runCtx.Config.NoColor,
 	}
 	spinner = ui.NewSpinner("Calculating monthly cost estimate", spinnerOpts)
 
-	for _, project := range projects {
-		if err := prices.PopulatePrices(runCtx.Config, project); err != nil {
-			spinner.Fail()
-			fmt.Fprintln(os.Stderr, "")
+	if err := prices.PopulatePrices(runCtx.Config, projects); err != nil {
+		spinner.Fail()
+		fmt.Fprintln(os.Stderr, "")
+
+		if e := unwrapped(err); errors.Is(e, apiclient.ErrInvalidAPIKey) {
+			return output.Root{}, fmt.Errorf("%v\n%s %s %s %s %s\n%s",
+				e.Error(),
+				"Please check your",
+				ui.PrimaryString(config.CredentialsFilePath()),
+				"file or",
+				ui.PrimaryString("INFRACOST_API_KEY"),
+				"environment variable.",
+				"If you continue having issues please email hello@infracost.io",
+			)
+		}
 
-			if e := unwrapped(err); errors.Is(e, apiclient.ErrInvalidAPIKey) {
-				return fmt.Errorf("%v\n%s %s %s %s %s\n%s",
-					e.Error(),
-					"Please check your",
-					ui.PrimaryString(config.CredentialsFilePath()),
-					"file or",
-					ui.PrimaryString("INFRACOST_API_KEY"),
-					"environment variable.",
-					"If you continue having issues please email hello@infracost.io",
-				)
-			}
+		if e, ok := err.(*apiclient.APIError); ok {
+			return output.Root{}, fmt.Errorf("%v\n%s", e.Error(), "We have been notified of this issue.")
+		}
 
-			if e, ok := err.(*apiclient.APIError); ok {
-				return fmt.Errorf("%v\n%s", e.Error(), "We have been notified of this issue.")
-			}
+		return output.Root{}, err
+	}
 
-			return err
-		}
+	var commitmentReport *schema.CommitmentReport
+	if portfolio != nil {
+		commitmentReport = portfolio.Apply(projects)
+	}
+
+	if err := prices.ApplyCredits(runCtx.Config, projects); err != nil {
+		spinner.Fail()
+		return output.Root{}, err
+	}
 
+	for _, project := range projects {
 		schema.CalculateCosts(project)
 		project.CalculateDiff()
 	}
@@ -188,9 +333,23 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 	spinner.Success()
 
 	r := output.ToOutputFormat(projects)
-	r.Currency = runCtx.Config.Currency
+	r.Currency = runCtx.Config.PricingCurrency()
+	r.PriceEffectiveDate = runCtx.Config.AsOf
+	r.Commitments = output.BuildCommitmentSummary(commitmentReport)
 
-	var err error
+	if exchangeRates != nil {
+		err = output.ConvertCurrency(&r, runCtx.Config.Currency, exchangeRates)
+		if err != nil {
+			return output.Root{}, err
+		}
+	}
+
+	r.Rollups = output.BuildRollups(r.Projects, runCtx.Config.GroupBy)
+	r.PolicyViolations = output.EvaluateBudgets(r, runCtx.Config.Policies, output.Options{DashboardEnabled: runCtx.Config.EnableDashboard})
+
+	if runCtx.Config.StrictPricing {
+		r.PricingIssues = output.BuildPricingIssues(projects)
+	}
 
 	dashboardClient := apiclient.NewDashboardAPIClient(runCtx)
 	r.RunID, err = dashboardClient.AddRun(runCtx, projectContexts, r)
@@ -206,40 +365,32 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 		log.Errorf("Error reporting event: %s", err)
 	}
 
-	opts := output.Options{
-		DashboardEnabled: runCtx.Config.EnableDashboard,
-		ShowSkipped:      runCtx.Config.ShowSkipped,
-		NoColor:          runCtx.Config.NoColor,
-		Fields:           runCtx.Config.Fields,
-	}
+	return r, nil
+}
 
-	var (
-		b   []byte
-		out string
-	)
+// loadUsageFile loads the usage file, creating it if it's going to be synced.
+// A dry run of the sync doesn't create it so a missing file is empty instead.
+func loadUsageFile(cfg *config.Config, path string) (map[string]*schema.UsageData, error) {
+	if cfg.SyncUsageFileDryRun && path != "" {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return schema.NewEmptyUsageMap(), nil
+		}
 
-	switch strings.ToLower(runCtx.Config.Format) {
-	case "json":
-		b, err = output.ToJSON(r, opts)
-		out = string(b)
-	case "html":
-		b, err = output.ToHTML(r, opts)
-		out = string(b)
-	case "diff":
-		b, err = output.ToDiff(r, opts)
-		out = fmt.Sprintf("\n%s", string(b))
-	default:
-		b, err = output.ToTable(r, opts)
-		out = fmt.Sprintf("\n%s", string(b))
+		return usage.LoadFromFile(path, false)
 	}
 
-	if err != nil {
-		return errors.Wrap(err, "Error generating output")
-	}
+	return usage.LoadFromFile(path, cfg.SyncUsageFile)
+}
 
-	cmd.Printf("%s\n", out)
+func printUsageFileDiff(cmd *cobra.Command, path string, diff string) {
+	if diff == "" {
+		cmd.Println(fmt.Sprintf("    %s No changes to %s", ui.FaintString("└─"), path))
+		return
+	}
 
-	return nil
+	cmd.Println(fmt.Sprintf("    %s Changes sync-usage-file would make to %s:", ui.FaintString("└─"), path))
+	cmd.Println("")
+	cmd.Print(diff)
 }
 
 func summarizeUsage(ctx *config.ProjectContext, syncResult *usage.SyncResult) {
@@ -293,7 +444,9 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 		cmd.Flags().Changed("usage-file") ||
 		cmd.Flags().Changed("terraform-plan-flags") ||
 		cmd.Flags().Changed("terraform-workspace") ||
-		cmd.Flags().Changed("terraform-use-state"))
+		cmd.Flags().Changed("terraform-use-state") ||
+		cmd.Flags().Changed("terraform-source-dir") ||
+		cmd.Flags().Changed("compare-to"))
 
 	projectCfg := cfg.Projects[0]
 
@@ -305,7 +458,7 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 
 	if hasConfigFile && (hasProjectFlags || hasProjectEnvs) {
 		m := "--config-file flag cannot be used with the following flags or equivalent environment variables: "
-		m += "--path, --terraform-*, --usage-file"
+		m += "--path, --terraform-*, --usage-file, --compare-to"
 		ui.PrintUsage(cmd)
 		return errors.New(m)
 	}
@@ -315,6 +468,11 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 		projectCfg.UsageFile, _ = cmd.Flags().GetString("usage-file")
 		projectCfg.TerraformPlanFlags, _ = cmd.Flags().GetString("terraform-plan-flags")
 		projectCfg.TerraformUseState, _ = cmd.Flags().GetBool("terraform-use-state")
+		projectCfg.TerraformSourceDir, _ = cmd.Flags().GetString("terraform-source-dir")
+
+		if cmd.Flags().Changed("compare-to") {
+			projectCfg.CompareTo, _ = cmd.Flags().GetString("compare-to")
+		}
 
 		if cmd.Flags().Changed("terraform-workspace") {
 			projectCfg.TerraformWorkspace, _ = cmd.Flags().GetString("terraform-workspace")
@@ -328,11 +486,72 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 		if err != nil {
 			return err
 		}
+
+		// Loading the config file reloads the environment variables, so the
+		// flag needs to be set again to take precedence over them
+		if cmd.Flags().Changed("pricing-snapshot") {
+			cfg.PricingSnapshot, _ = cmd.Flags().GetString("pricing-snapshot")
+		}
 	}
 
 	cfg.Format, _ = cmd.Flags().GetString("format")
 	cfg.ShowSkipped, _ = cmd.Flags().GetBool("show-skipped")
 	cfg.SyncUsageFile, _ = cmd.Flags().GetBool("sync-usage-file")
+	cfg.SyncUsageFileDryRun, _ = cmd.Flags().GetBool("sync-usage-file-dry-run")
+	if cfg.SyncUsageFileDryRun {
+		cfg.SyncUsageFile = true
+	}
+
+	if cmd.Flags().Changed("no-cache") {
+		cfg.PricingCacheDisabled, _ = cmd.Flags().GetBool("no-cache")
+	}
+
+	if cmd.Flags().Changed("strict-pricing") {
+		cfg.StrictPricing, _ = cmd.Flags().GetBool("strict-pricing")
+	}
+
+	if cmd.Flags().Changed("price-overrides") {
+		cfg.PriceOverridesFile, _ = cmd.Flags().GetString("price-overrides")
+	}
+
+	if cmd.Flags().Changed("commitments") {
+		cfg.CommitmentsFile, _ = cmd.Flags().GetString("commitments")
+	}
+
+	if cmd.Flags().Changed("spot-price-history") {
+		cfg.SpotPriceHistoryFile, _ = cmd.Flags().GetString("spot-price-history")
+	}
+
+	if cmd.Flags().Changed("spot-price-statistic") {
+		cfg.SpotPriceStatistic, _ = cmd.Flags().GetString("spot-price-statistic")
+	}
+
+	if cmd.Flags().Changed("exchange-rates") {
+		cfg.ExchangeRates, _ = cmd.Flags().GetString("exchange-rates")
+	}
+
+	if cmd.Flags().Changed("as-of") {
+		cfg.AsOf, _ = cmd.Flags().GetString("as-of")
+	}
+
+	if err := checkAsOf(cfg); err != nil {
+		ui.PrintUsage(cmd)
+		return err
+	}
+
+	if err := prices.ValidateSpotPriceStatistic(cfg.SpotPriceStatistic); err != nil {
+		ui.PrintUsage(cmd)
+		return err
+	}
+
+	if cmd.Flags().Changed("group-by") {
+		cfg.GroupBy, _ = cmd.Flags().GetStringSlice("group-by")
+	}
+
+	if err := output.ValidateGroupBy(cfg.GroupBy); err != nil {
+		ui.PrintUsage(cmd)
+		return err
+	}
 
 	includeAllFields := "all"
 	validFields := []string{"price", "monthlyQuantity", "unit", "hourlyCost", "monthlyCost"}
@@ -421,3 +640,10 @@ func unwrapped(err error) error {
 
 	return e
 }
+
+// policyViolationsError returns an error with a distinct exit code so CI can
+// fail the build when the costs violate the budget policies.
+func policyViolationsError(violations []output.PolicyViolation) error {
+	err := clierror.NewSanitizedError(errors.New(output.PolicyViolationsMessage(violations)), "Budget policy violated")
+	return clierror.NewExitCodeError(err, clierror.PolicyViolationExitCode)
+}
@@ -28,3 +28,7 @@ func TestFlagErrorsTerraformWorkspaceFlagAndEnv(t *testing.T) {
 	os.Setenv("INFRACOST_TERRAFORM_WORKSPACE", "dev")
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"breakdown", "--path", "../../examples/terraform", "--terraform-workspace", "prod"}, nil)
 }
+
+func TestFlagErrorsAsOfInvalidDate(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"breakdown", "--path", "./testdata/aws_instances_plan.json", "--pricing-snapshot", "./testdata/aws_instances_price_history.json", "--as-of", "01/06/2020"}, nil)
+}
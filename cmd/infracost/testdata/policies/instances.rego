@@ -0,0 +1,16 @@
+package infracost
+
+deny[{"msg": msg, "resource": r.name}] {
+	r := input.projects[_].breakdown.resources[_]
+	r.resourceType == "aws_instance"
+	to_number(r.monthlyCost) > 500
+	msg := sprintf("costs %v per month, more than the limit of 500", [r.monthlyCost])
+}
+
+warn[{"msg": msg, "resource": r.name}] {
+	r := input.projects[_].breakdown.resources[_]
+	c := r.subresources[_].costComponents[_]
+	contains(c.name, "provisioned IOPS SSD, io1")
+	to_number(c.monthlyQuantity) > 500
+	msg := sprintf("has an io1 volume of %v GB, consider gp3", [c.monthlyQuantity])
+}
@@ -0,0 +1,12 @@
+package infracost
+
+deny[{"msg": msg, "project": p.name}] {
+	p := input.projects[_]
+	contains(p.name, "azure_firewall")
+	to_number(p.breakdown.totalMonthlyCost) > 4000
+	msg := sprintf("costs %v per month, more than the limit of 4000", [p.breakdown.totalMonthlyCost])
+}
+
+warn["Total monthly cost is over 1000"] {
+	to_number(input.totalMonthlyCost) > 1000
+}
@@ -0,0 +1,34 @@
+provider "aws" {
+  region = "us-east-1"
+}
+
+resource "aws_instance" "web" {
+  ami           = "ami-674cbc1e"
+  instance_type = "m5.large"
+
+  root_block_device {
+    volume_size = 10
+  }
+}
+
+resource "aws_instance" "app" {
+  ami           = "ami-674cbc1e"
+  instance_type = "m5.xlarge"
+
+  root_block_device {
+    volume_size = 10
+  }
+}
+
+resource "aws_instance" "worker" {
+  ami           = "ami-674cbc1e"
+  instance_type = "c5.large"
+
+  root_block_device {
+    volume_size = 10
+  }
+}
+
+resource "aws_ses_domain_identity" "example" {
+  domain = "example.com"
+}
@@ -1,10 +1,14 @@
 package apiclient
 
 import (
+	"encoding/json"
 	"fmt"
+
 	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/pricestore"
 	"github.com/infracost/infracost/internal/schema"
 
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
 )
@@ -13,6 +17,13 @@ type PricingAPIClient struct {
 	APIClient
 	Currency       string
 	EventsDisabled bool
+	// EffectiveDate is the date the prices should be effective on. The
+	// current prices are used if it is empty.
+	EffectiveDate string
+	// Snapshot is used to answer the price queries locally instead of
+	// sending them to the Cloud Pricing API.
+	Snapshot *pricestore.Store
+	Cache    *PricingCache
 }
 
 type PriceQueryKey struct {
@@ -26,19 +37,21 @@ type PriceQueryResult struct {
 }
 
 func NewPricingAPIClient(cfg *config.Config) *PricingAPIClient {
-	currency := cfg.Currency
-	if currency == "" {
-		currency = "USD"
-	}
-
-	return &PricingAPIClient{
+	c := &PricingAPIClient{
 		APIClient: APIClient{
 			endpoint: cfg.PricingAPIEndpoint,
 			apiKey:   cfg.APIKey,
 		},
-		Currency:       currency,
-		EventsDisabled: cfg.EventsDisabled,
+		Currency:       cfg.PricingCurrency(),
+		EffectiveDate:  cfg.AsOf,
+		EventsDisabled: cfg.EventsDisabled || cfg.PricingSnapshot != "",
+	}
+
+	if !cfg.PricingCacheDisabled {
+		c.Cache = NewPricingCache(config.PricingCacheDir(), cfg.PricingCacheTTL)
 	}
+
+	return c
 }
 
 func (c *PricingAPIClient) AddEvent(name string, env map[string]interface{}) error {
@@ -63,7 +76,31 @@ func (c *PricingAPIClient) RunQueries(r *schema.Resource) ([]PriceQueryResult, e
 		return []PriceQueryResult{}, nil
 	}
 
-	log.Debugf("Getting pricing details from %s for %s", c.endpoint, r.Name)
+	log.Debugf("Getting pricing details from %s for %s", c.source(), r.Name)
+
+	results, err := c.performQueries(queries)
+	if err != nil {
+		return []PriceQueryResult{}, err
+	}
+
+	return c.zipQueryResults(keys, results), nil
+}
+
+// RunExportQueries runs the queries for the resource requesting the full
+// product and price details, so the results can be saved to a price snapshot.
+func (c *PricingAPIClient) RunExportQueries(r *schema.Resource) ([]PriceQueryResult, error) {
+	keys, _ := c.batchQueries(r)
+
+	queries := make([]GraphQLQuery, 0, len(keys))
+	for _, k := range keys {
+		queries = append(queries, c.buildExportQuery(k.CostComponent.ProductFilter, k.CostComponent.PriceFilter))
+	}
+
+	if len(queries) == 0 {
+		return []PriceQueryResult{}, nil
+	}
+
+	log.Debugf("Getting full pricing details from %s for %s", c.endpoint, r.Name)
 
 	results, err := c.doQueries(queries)
 	if err != nil {
@@ -73,10 +110,144 @@ func (c *PricingAPIClient) RunQueries(r *schema.Resource) ([]PriceQueryResult, e
 	return c.zipQueryResults(keys, results), nil
 }
 
+func (c *PricingAPIClient) performQueries(queries []GraphQLQuery) ([]gjson.Result, error) {
+	if c.Snapshot != nil {
+		return c.doSnapshotQueries(queries)
+	}
+
+	if c.Cache == nil {
+		return c.doQueries(queries)
+	}
+
+	return c.doCachedQueries(queries)
+}
+
+// doCachedQueries only sends the queries that aren't in the pricing cache to
+// the Cloud Pricing API and then stores their results in the cache.
+func (c *PricingAPIClient) doCachedQueries(queries []GraphQLQuery) ([]gjson.Result, error) {
+	results := make([]gjson.Result, len(queries))
+	keys := make([]string, len(queries))
+
+	missedIndexes := make([]int, 0)
+	missedQueries := make([]GraphQLQuery, 0)
+
+	for i, q := range queries {
+		key, err := c.Cache.Key(c.endpoint, c.Currency, q)
+		if err != nil {
+			log.Debugf("Error generating pricing cache key: %s", err)
+		}
+		keys[i] = key
+
+		if key != "" {
+			if r, ok := c.Cache.Get(key); ok {
+				results[i] = r
+				continue
+			}
+		}
+
+		missedIndexes = append(missedIndexes, i)
+		missedQueries = append(missedQueries, q)
+	}
+
+	log.Debugf("Found %d of %d queries in the pricing cache", len(queries)-len(missedQueries), len(queries))
+
+	if len(missedQueries) == 0 {
+		return results, nil
+	}
+
+	missedResults, err := c.doQueries(missedQueries)
+	if err != nil {
+		return []gjson.Result{}, err
+	}
+
+	for j, i := range missedIndexes {
+		if j >= len(missedResults) {
+			break
+		}
+
+		results[i] = missedResults[j]
+
+		if keys[i] == "" || missedResults[j].Get("errors").Exists() {
+			continue
+		}
+
+		err := c.Cache.Set(keys[i], missedResults[j])
+		if err != nil {
+			log.Debugf("Error writing to pricing cache: %s", err)
+		}
+	}
+
+	return results, nil
+}
+
+// doSnapshotQueries answers the queries from the price snapshot. The results
+// have the same structure as the GraphQL responses from the Cloud Pricing API.
+func (c *PricingAPIClient) doSnapshotQueries(queries []GraphQLQuery) ([]gjson.Result, error) {
+	results := make([]gjson.Result, 0, len(queries))
+
+	for _, q := range queries {
+		productFilter, _ := q.Variables["productFilter"].(*schema.ProductFilter)
+		priceFilter, _ := q.Variables["priceFilter"].(*schema.PriceFilter)
+
+		products := make([]interface{}, 0)
+		for _, p := range c.Snapshot.Query(productFilter, priceFilter) {
+			prices := make([]interface{}, 0, len(p.Prices))
+			for _, price := range p.Prices {
+				m := map[string]interface{}{
+					"priceHash": price.PriceHash,
+				}
+				if amount, ok := price.Amounts[c.Currency]; ok {
+					m[c.Currency] = amount
+				}
+				prices = append(prices, m)
+			}
+
+			products = append(products, map[string]interface{}{"prices": prices})
+		}
+
+		b, err := json.Marshal(map[string]interface{}{
+			"data": map[string]interface{}{
+				"products": products,
+			},
+		})
+		if err != nil {
+			return []gjson.Result{}, err
+		}
+
+		results = append(results, gjson.ParseBytes(b))
+	}
+
+	return results, nil
+}
+
+func (c *PricingAPIClient) source() string {
+	if c.Snapshot != nil {
+		return "price snapshot"
+	}
+
+	return c.endpoint
+}
+
+// priceFilter adds the effective date to the price filter. The price filter
+// is copied since cost components can share price filters.
+func (c *PricingAPIClient) priceFilter(price *schema.PriceFilter) *schema.PriceFilter {
+	if c.EffectiveDate == "" {
+		return price
+	}
+
+	f := &schema.PriceFilter{}
+	if price != nil {
+		*f = *price
+	}
+	f.EffectiveDate = &c.EffectiveDate
+
+	return f
+}
+
 func (c *PricingAPIClient) buildQuery(product *schema.ProductFilter, price *schema.PriceFilter) GraphQLQuery {
 	v := map[string]interface{}{}
 	v["productFilter"] = product
-	v["priceFilter"] = price
+	v["priceFilter"] = c.priceFilter(price)
 
 	query := fmt.Sprintf(`
 		query($productFilter: ProductFilter!, $priceFilter: PriceFilter) {
@@ -92,6 +263,50 @@ func (c *PricingAPIClient) buildQuery(product *schema.ProductFilter, price *sche
 	return GraphQLQuery{query, v}
 }
 
+func (c *PricingAPIClient) buildExportQuery(product *schema.ProductFilter, price *schema.PriceFilter) GraphQLQuery {
+	v := map[string]interface{}{}
+	v["productFilter"] = product
+	v["priceFilter"] = c.priceFilter(price)
+
+	// Only pricing APIs with price history have the effective dates
+	effectiveDateFields := ""
+	if c.EffectiveDate != "" {
+		effectiveDateFields = "effectiveDateStart effectiveDateEnd"
+	}
+
+	query := fmt.Sprintf(`
+		query($productFilter: ProductFilter!, $priceFilter: PriceFilter) {
+			products(filter: $productFilter) {
+				productHash
+				vendorName
+				service
+				productFamily
+				region
+				sku
+				attributes {
+					key
+					value
+				}
+				prices(filter: $priceFilter) {
+					priceHash
+					purchaseOption
+					unit
+					description
+					startUsageAmount
+					endUsageAmount
+					termLength
+					termPurchaseOption
+					termOfferingClass
+					%s
+					%s
+				}
+			}
+		}
+	`, effectiveDateFields, c.Currency)
+
+	return GraphQLQuery{query, v}
+}
+
 // Batch all the queries for this resource so we can use one GraphQL call.
 // Use PriceQueryKeys to keep track of which query maps to which sub-resource and price component.
 func (c *PricingAPIClient) batchQueries(r *schema.Resource) ([]PriceQueryKey, []GraphQLQuery) {
@@ -113,6 +328,69 @@ func (c *PricingAPIClient) batchQueries(r *schema.Resource) ([]PriceQueryKey, []
 	return keys, queries
 }
 
+// PriceQueryPlan holds the unique queries needed to price a set of resources.
+// Cost components with identical product and price filters share a query, so
+// Keys[i] lists every cost component that uses the result of Queries[i].
+type PriceQueryPlan struct {
+	Keys    [][]PriceQueryKey
+	Queries []GraphQLQuery
+}
+
+// PlanQueries builds the queries for all the cost components of the resources,
+// deduplicating any queries that have the same product and price filters.
+func (c *PricingAPIClient) PlanQueries(resources []*schema.Resource) (*PriceQueryPlan, error) {
+	plan := &PriceQueryPlan{
+		Keys:    make([][]PriceQueryKey, 0),
+		Queries: make([]GraphQLQuery, 0),
+	}
+
+	indexes := make(map[string]int)
+
+	for _, r := range resources {
+		if r.IsSkipped {
+			continue
+		}
+
+		keys, queries := c.batchQueries(r)
+
+		for i, q := range queries {
+			b, err := json.Marshal(q.Variables)
+			if err != nil {
+				return plan, errors.Wrap(err, "Error generating query key")
+			}
+
+			idx, ok := indexes[string(b)]
+			if !ok {
+				idx = len(plan.Queries)
+				indexes[string(b)] = idx
+				plan.Queries = append(plan.Queries, q)
+				plan.Keys = append(plan.Keys, []PriceQueryKey{})
+			}
+
+			plan.Keys[idx] = append(plan.Keys[idx], keys[i])
+		}
+	}
+
+	return plan, nil
+}
+
+// RunQueryBatch runs a batch of queries in a single request and returns the
+// results in the same order as the queries.
+func (c *PricingAPIClient) RunQueryBatch(queries []GraphQLQuery) ([]gjson.Result, error) {
+	log.Debugf("Getting pricing details for %d queries from %s", len(queries), c.source())
+
+	results, err := c.performQueries(queries)
+	if err != nil {
+		return []gjson.Result{}, err
+	}
+
+	if len(results) != len(queries) {
+		return []gjson.Result{}, &APIError{fmt.Errorf("expected %d results, got %d", len(queries), len(results)), "Invalid API response"}
+	}
+
+	return results, nil
+}
+
 func (c *PricingAPIClient) zipQueryResults(k []PriceQueryKey, r []gjson.Result) []PriceQueryResult {
 	res := make([]PriceQueryResult, 0, len(k))
 
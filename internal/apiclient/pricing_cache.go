@@ -0,0 +1,174 @@
+package apiclient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+// PricingCache is an on-disk cache of Cloud Pricing API query results.
+// Entries are content-addressed by a hash of the query filters, so identical
+// queries from different resources, projects or runs share the same entry.
+type PricingCache struct {
+	Dir string
+	TTL time.Duration
+}
+
+type PricingCacheStats struct {
+	Entries        int
+	ExpiredEntries int
+	SizeBytes      int64
+	OldestEntry    *time.Time
+	NewestEntry    *time.Time
+}
+
+func NewPricingCache(dir string, ttl time.Duration) *PricingCache {
+	return &PricingCache{
+		Dir: dir,
+		TTL: ttl,
+	}
+}
+
+// Key returns the cache key for a query. The endpoint is included so results
+// from a self-hosted Cloud Pricing API are not mixed with the hosted one.
+func (c *PricingCache) Key(endpoint string, currency string, q GraphQLQuery) (string, error) {
+	vars, err := json.Marshal(map[string]interface{}{
+		"endpoint":      endpoint,
+		"currency":      currency,
+		"productFilter": q.Variables["productFilter"],
+		"priceFilter":   q.Variables["priceFilter"],
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(vars)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (c *PricingCache) Get(key string) (gjson.Result, bool) {
+	p := c.entryPath(key)
+
+	info, err := os.Stat(p)
+	if err != nil {
+		return gjson.Result{}, false
+	}
+
+	if c.isExpired(info) {
+		return gjson.Result{}, false
+	}
+
+	data, err := ioutil.ReadFile(p)
+	if err != nil || !gjson.ValidBytes(data) {
+		log.Debugf("Ignoring invalid pricing cache entry %s", p)
+		return gjson.Result{}, false
+	}
+
+	return gjson.ParseBytes(data), true
+}
+
+func (c *PricingCache) Set(key string, result gjson.Result) error {
+	p := c.entryPath(key)
+
+	err := os.MkdirAll(filepath.Dir(p), 0700)
+	if err != nil {
+		return errors.Wrap(err, "Error creating pricing cache directory")
+	}
+
+	// Write to a temp file first so concurrent runs never read a partial entry
+	tmp, err := ioutil.TempFile(filepath.Dir(p), ".tmp-")
+	if err != nil {
+		return errors.Wrap(err, "Error writing pricing cache entry")
+	}
+
+	_, err = tmp.WriteString(result.Raw)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrap(err, "Error writing pricing cache entry")
+	}
+
+	return os.Rename(tmp.Name(), p)
+}
+
+// Clear removes all the entries from the cache and returns how many were removed.
+func (c *PricingCache) Clear() (int, error) {
+	count := 0
+
+	err := c.walkEntries(func(path string, info os.FileInfo) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	err = os.RemoveAll(c.Dir)
+	if err != nil {
+		return 0, errors.Wrap(err, "Error removing pricing cache directory")
+	}
+
+	return count, nil
+}
+
+func (c *PricingCache) Stats() (PricingCacheStats, error) {
+	stats := PricingCacheStats{}
+
+	err := c.walkEntries(func(path string, info os.FileInfo) error {
+		stats.Entries++
+		stats.SizeBytes += info.Size()
+
+		if c.isExpired(info) {
+			stats.ExpiredEntries++
+		}
+
+		t := info.ModTime()
+		if stats.OldestEntry == nil || t.Before(*stats.OldestEntry) {
+			stats.OldestEntry = &t
+		}
+		if stats.NewestEntry == nil || t.After(*stats.NewestEntry) {
+			stats.NewestEntry = &t
+		}
+
+		return nil
+	})
+
+	return stats, err
+}
+
+func (c *PricingCache) walkEntries(fn func(path string, info os.FileInfo) error) error {
+	if _, err := os.Stat(c.Dir); os.IsNotExist(err) {
+		return nil
+	}
+
+	return filepath.Walk(c.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+
+		return fn(path, info)
+	})
+}
+
+func (c *PricingCache) isExpired(info os.FileInfo) bool {
+	return c.TTL > 0 && time.Since(info.ModTime()) > c.TTL
+}
+
+func (c *PricingCache) entryPath(key string) string {
+	return filepath.Join(c.Dir, key[:2], key+".json")
+}
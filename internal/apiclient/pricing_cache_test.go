@@ -0,0 +1,88 @@
+package apiclient
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func testQuery(instanceType string) GraphQLQuery {
+	return GraphQLQuery{
+		Variables: map[string]interface{}{
+			"productFilter": &schema.ProductFilter{
+				VendorName: util.StrPtr("aws"),
+				Service:    util.StrPtr("AmazonEC2"),
+				AttributeFilters: []*schema.AttributeFilter{
+					{Key: "instanceType", Value: util.StrPtr(instanceType)},
+				},
+			},
+			"priceFilter": &schema.PriceFilter{PurchaseOption: util.StrPtr("on_demand")},
+		},
+	}
+}
+
+func TestPricingCacheKey(t *testing.T) {
+	c := NewPricingCache(t.TempDir(), time.Hour)
+
+	k1, err := c.Key("https://pricing.api.infracost.io", "USD", testQuery("t3.micro"))
+	require.NoError(t, err)
+
+	k2, _ := c.Key("https://pricing.api.infracost.io", "USD", testQuery("t3.micro"))
+	k3, _ := c.Key("https://pricing.api.infracost.io", "EUR", testQuery("t3.micro"))
+	k4, _ := c.Key("https://pricing.api.infracost.io", "USD", testQuery("t3.large"))
+
+	assert.Equal(t, k1, k2)
+	assert.NotEqual(t, k1, k3)
+	assert.NotEqual(t, k1, k4)
+}
+
+func TestPricingCacheGetSet(t *testing.T) {
+	c := NewPricingCache(t.TempDir(), time.Hour)
+
+	key, _ := c.Key("", "USD", testQuery("t3.micro"))
+
+	_, ok := c.Get(key)
+	assert.False(t, ok)
+
+	res := gjson.Parse(`{"data":{"products":[{"prices":[{"priceHash":"abc","USD":"0.0104"}]}]}}`)
+	require.NoError(t, c.Set(key, res))
+
+	cached, ok := c.Get(key)
+	assert.True(t, ok)
+	assert.Equal(t, "0.0104", cached.Get("data.products.0.prices.0.USD").String())
+
+	stats, err := c.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Entries)
+	assert.Equal(t, 0, stats.ExpiredEntries)
+
+	count, err := c.Clear()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	_, ok = c.Get(key)
+	assert.False(t, ok)
+}
+
+func TestPricingCacheExpiry(t *testing.T) {
+	c := NewPricingCache(t.TempDir(), time.Hour)
+
+	key, _ := c.Key("", "USD", testQuery("t3.micro"))
+	require.NoError(t, c.Set(key, gjson.Parse(`{"data":{"products":[]}}`)))
+
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(c.entryPath(key), old, old))
+
+	_, ok := c.Get(key)
+	assert.False(t, ok)
+
+	stats, err := c.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.ExpiredEntries)
+}
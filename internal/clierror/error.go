@@ -20,3 +20,32 @@ func NewSanitizedError(err error, sanitizedMsg string) *SanitizedError {
 		err:          err,
 	}
 }
+
+// PolicyViolationExitCode is the exit code used when the costs violate the
+// budget policies so CI can tell it apart from other errors.
+const PolicyViolationExitCode = 3
+
+// ExitCodeError is an error that sets the exit code of the CLI.
+type ExitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *ExitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ExitCodeError) Unwrap() error {
+	return e.err
+}
+
+func (e *ExitCodeError) ExitCode() int {
+	return e.code
+}
+
+func NewExitCodeError(err error, code int) *ExitCodeError {
+	return &ExitCodeError{
+		code: code,
+		err:  err,
+	}
+}
@@ -0,0 +1,394 @@
+package commitments
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/util"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	kindEC2     = "ec2"
+	kindFargate = "fargate"
+	kindLambda  = "lambda"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// normalizationFactors are the AWS size normalization factors used to apply
+// size-flexible Reserved Instances to other sizes in the same instance family.
+var normalizationFactors = map[string]decimal.Decimal{
+	"nano":   decimal.NewFromFloat(0.25),
+	"micro":  decimal.NewFromFloat(0.5),
+	"small":  decimal.NewFromInt(1),
+	"medium": decimal.NewFromInt(2),
+	"large":  decimal.NewFromInt(4),
+	"xlarge": decimal.NewFromInt(8),
+}
+
+var multipleXLargeRegex = regexp.MustCompile(`^(\d+)xlarge$`)
+
+// eligibleComponent is a cost component whose usage can be covered by a
+// commitment, along with how much of it is still charged at on-demand rates.
+type eligibleComponent struct {
+	resourceName string
+	component    *schema.CostComponent
+	kind         string
+	region       string
+	instanceType string
+	os           string
+	tenancy      string
+
+	remainingQuantity decimal.Decimal
+	remainingCost     decimal.Decimal
+}
+
+// Apply allocates the commitments across the resources of all the projects,
+// since commitments apply to the whole account. The past resources are
+// allocated separately so diffs compare like with like. The report is for the
+// current resources.
+func (p *Portfolio) Apply(projects []*schema.Project) *schema.CommitmentReport {
+	past := make([]*schema.Resource, 0)
+	current := make([]*schema.Resource, 0)
+
+	for _, project := range projects {
+		past = append(past, project.PastResources...)
+		current = append(current, project.Resources...)
+	}
+
+	p.Allocate(past)
+
+	return p.Allocate(current)
+}
+
+// Allocate allocates the commitments across the resources using the order AWS
+// applies them in: Reserved Instances first, with exact instance type matches
+// before other sizes in the same family, then EC2 Instance Savings Plans and
+// then Compute Savings Plans. Savings Plans are applied to the usage with the
+// highest discount first. Costs must have been calculated before this is
+// called.
+func (p *Portfolio) Allocate(resources []*schema.Resource) *schema.CommitmentReport {
+	eligible := eligibleComponents(resources)
+
+	report := &schema.CommitmentReport{
+		Commitments: make([]*schema.Commitment, 0, len(p.ReservedInstances)+len(p.SavingsPlans)),
+	}
+
+	for i, ri := range p.ReservedInstances {
+		report.Commitments = append(report.Commitments, allocateReservedInstance(commitmentName(ri.Name, "reserved_instances", i), ri, eligible))
+	}
+
+	for _, t := range []string{savingsPlanTypeEC2Instance, savingsPlanTypeCompute} {
+		for i, sp := range p.SavingsPlans {
+			if sp.Type == t {
+				report.Commitments = append(report.Commitments, allocateSavingsPlan(commitmentName(sp.Name, "savings_plans", i), sp, eligible))
+			}
+		}
+	}
+
+	for _, e := range eligible {
+		report.HourlyOnDemandCost = report.HourlyOnDemandCost.Add(e.remainingCost)
+
+		for _, cov := range e.component.CommitmentCoverage {
+			report.HourlyCoveredOnDemandCost = report.HourlyCoveredOnDemandCost.Add(cov.HourlyOnDemandCost)
+			report.HourlyCoveredCost = report.HourlyCoveredCost.Add(cov.HourlyCost)
+		}
+	}
+
+	return report
+}
+
+func commitmentName(name string, key string, i int) string {
+	if name != "" {
+		return name
+	}
+	return fmt.Sprintf("%s[%d]", key, i)
+}
+
+func allocateReservedInstance(name string, ri *ReservedInstance, eligible []*eligibleComponent) *schema.Commitment {
+	hourlyCost := decimal.NewFromFloat(ri.HourlyCost)
+	count := decimal.NewFromInt(ri.Count)
+
+	commitment := &schema.Commitment{
+		Name:             name,
+		Type:             schema.CommitmentReservedInstance,
+		HourlyCommitment: hourlyCost.Mul(count),
+	}
+
+	os := operatingSystems[ri.operatingSystem()]
+	tenancy := tenancies[ri.tenancy()]
+	family, riFactor, hasFactor := instanceTypeSize(ri.InstanceType)
+
+	// Regional Linux Reserved Instances with shared tenancy are size-flexible
+	// so they can cover other sizes in the same family, using the
+	// normalization factors to work out how much of the reservation is used.
+	flexible := hasFactor && os == "Linux" && tenancy == "Shared"
+
+	exact := make([]*eligibleComponent, 0)
+	others := make([]*eligibleComponent, 0)
+
+	for _, e := range eligible {
+		if e.kind != kindEC2 || e.region != ri.Region || e.os != os || e.tenancy != tenancy {
+			continue
+		}
+
+		if e.instanceType == ri.InstanceType {
+			exact = append(exact, e)
+		} else if flexible {
+			if f, _, ok := instanceTypeSize(e.instanceType); ok && f == family {
+				others = append(others, e)
+			}
+		}
+	}
+
+	sort.SliceStable(others, func(i, j int) bool {
+		_, a, _ := instanceTypeSize(others[i].instanceType)
+		_, b, _ := instanceTypeSize(others[j].instanceType)
+		return a.LessThan(b)
+	})
+
+	// Work in normalized units so size-flexible reservations can be split
+	// across instance sizes. Reservations that aren't size-flexible use one
+	// unit per instance.
+	unitFactor := decimal.NewFromInt(1)
+	if flexible {
+		unitFactor = riFactor
+	}
+	remainingUnits := count.Mul(unitFactor)
+	unitCost := hourlyCost.Div(unitFactor)
+
+	for _, e := range append(exact, others...) {
+		if !remainingUnits.IsPositive() {
+			break
+		}
+
+		if !e.remainingQuantity.IsPositive() {
+			continue
+		}
+
+		factor := unitFactor
+		if e.instanceType != ri.InstanceType {
+			_, factor, _ = instanceTypeSize(e.instanceType)
+		}
+
+		quantity := decimal.Min(e.remainingQuantity, remainingUnits.Div(factor))
+		units := quantity.Mul(factor)
+		onDemandCost := e.remainingCost.Mul(quantity).Div(e.remainingQuantity)
+		cost := units.Mul(unitCost)
+
+		e.cover(name, schema.CommitmentReservedInstance, quantity, onDemandCost, cost)
+
+		remainingUnits = remainingUnits.Sub(units)
+		commitment.HourlyUsed = commitment.HourlyUsed.Add(cost)
+	}
+
+	return commitment
+}
+
+func allocateSavingsPlan(name string, sp *SavingsPlan, eligible []*eligibleComponent) *schema.Commitment {
+	commitment := &schema.Commitment{
+		Name:             name,
+		Type:             schema.CommitmentComputeSavingsPlan,
+		HourlyCommitment: decimal.NewFromFloat(sp.HourlyCommitment),
+	}
+
+	if sp.Type == savingsPlanTypeEC2Instance {
+		commitment.Type = schema.CommitmentEC2InstanceSavingsPlan
+	}
+
+	matching := make([]*eligibleComponent, 0)
+
+	for _, e := range eligible {
+		if sp.Type == savingsPlanTypeEC2Instance {
+			family, _, _ := instanceTypeSize(e.instanceType)
+			if e.kind != kindEC2 || e.region != sp.Region || family != sp.InstanceFamily {
+				continue
+			}
+		}
+
+		matching = append(matching, e)
+	}
+
+	sort.SliceStable(matching, func(i, j int) bool {
+		return sp.discount(matching[i].kind) > sp.discount(matching[j].kind)
+	})
+
+	remaining := commitment.HourlyCommitment
+
+	for _, e := range matching {
+		if !remaining.IsPositive() {
+			break
+		}
+
+		if !e.remainingCost.IsPositive() {
+			continue
+		}
+
+		rate := decimal.NewFromInt(1).Sub(decimal.NewFromFloat(sp.discount(e.kind)).Div(hundred))
+
+		cost := e.remainingCost.Mul(rate)
+		onDemandCost := e.remainingCost
+		if cost.GreaterThan(remaining) {
+			cost = remaining
+			onDemandCost = cost.Div(rate)
+		}
+		quantity := e.remainingQuantity.Mul(onDemandCost).Div(e.remainingCost)
+
+		e.cover(name, commitment.Type, quantity, onDemandCost, cost)
+
+		remaining = remaining.Sub(cost)
+		commitment.HourlyUsed = commitment.HourlyUsed.Add(cost)
+	}
+
+	return commitment
+}
+
+func (e *eligibleComponent) cover(name string, t schema.CommitmentType, quantity decimal.Decimal, onDemandCost decimal.Decimal, cost decimal.Decimal) {
+	log.Debugf("Allocating %s to %s %s, covering %s of on-demand cost for %s", name, e.resourceName, e.component.Name, onDemandCost, cost)
+
+	e.component.AddCommitmentCoverage(&schema.CommitmentCoverage{
+		Commitment:         name,
+		Type:               t,
+		HourlyOnDemandCost: onDemandCost,
+		HourlyCost:         cost,
+	})
+
+	e.remainingQuantity = e.remainingQuantity.Sub(quantity)
+	e.remainingCost = e.remainingCost.Sub(onDemandCost)
+}
+
+// eligibleComponents returns the on-demand EC2 instance, Fargate and Lambda
+// duration cost components of the resources, sorted by resource name so the
+// commitments are always allocated in the same order.
+func eligibleComponents(resources []*schema.Resource) []*eligibleComponent {
+	sorted := append([]*schema.Resource{}, resources...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	eligible := make([]*eligibleComponent, 0)
+
+	var add func(name string, r *schema.Resource)
+	add = func(name string, r *schema.Resource) {
+		for _, c := range r.CostComponents {
+			e := newEligibleComponent(name, c)
+			if e != nil {
+				eligible = append(eligible, e)
+			}
+		}
+
+		for _, s := range r.SubResources {
+			add(name, s)
+		}
+	}
+
+	for _, r := range sorted {
+		if r.IsSkipped {
+			continue
+		}
+		add(r.Name, r)
+	}
+
+	return eligible
+}
+
+func newEligibleComponent(resourceName string, c *schema.CostComponent) *eligibleComponent {
+	f := c.ProductFilter
+	if f == nil || util.StringValue(f.VendorName) != "aws" {
+		return nil
+	}
+
+	e := &eligibleComponent{
+		resourceName: resourceName,
+		component:    c,
+		region:       util.StringValue(f.Region),
+	}
+
+	service := util.StringValue(f.Service)
+	usageType := attributeValue(f, "usagetype")
+
+	switch {
+	case service == "AmazonEC2" && util.StringValue(f.ProductFamily) == "Compute Instance" && attributeValue(f, "capacitystatus") != "":
+		if c.PriceFilter == nil || util.StringValue(c.PriceFilter.PurchaseOption) != "on_demand" {
+			return nil
+		}
+		e.kind = kindEC2
+		e.instanceType = attributeValue(f, "instanceType")
+		e.os = attributeValue(f, "operatingSystem")
+		e.tenancy = attributeValue(f, "tenancy")
+	case (service == "AmazonECS" || service == "AmazonEKS") && strings.Contains(usageType, "Fargate"):
+		e.kind = kindFargate
+	case service == "AWSLambda" && strings.Contains(usageType, "GB-Second"):
+		e.kind = kindLambda
+	default:
+		return nil
+	}
+
+	// Costs are compared per hour, so components that are priced monthly are
+	// converted using their monthly cost. Credits aren't applied yet since
+	// they're taken off the cost that isn't covered by the commitments.
+	c.CalculateCosts()
+
+	var cost decimal.Decimal
+	if c.MonthlyCost != nil {
+		cost = c.MonthlyCost.Div(schema.HourToMonthUnitMultiplier)
+	} else if c.HourlyCost != nil {
+		cost = *c.HourlyCost
+	}
+
+	if !cost.IsPositive() {
+		return nil
+	}
+
+	e.remainingCost = cost
+	e.remainingQuantity = decimal.Zero
+	if c.HourlyQuantity != nil {
+		e.remainingQuantity = *c.HourlyQuantity
+	}
+
+	return e
+}
+
+// instanceTypeSize returns the family and normalization factor of an EC2
+// instance type, e.g. m5 and 4 for m5.large.
+func instanceTypeSize(instanceType string) (string, decimal.Decimal, bool) {
+	parts := strings.SplitN(instanceType, ".", 2)
+	if len(parts) != 2 {
+		return "", decimal.Zero, false
+	}
+
+	family, size := parts[0], parts[1]
+
+	if f, ok := normalizationFactors[size]; ok {
+		return family, f, true
+	}
+
+	if m := multipleXLargeRegex.FindStringSubmatch(size); m != nil {
+		n, _ := strconv.ParseInt(m[1], 10, 64)
+		return family, normalizationFactors["xlarge"].Mul(decimal.NewFromInt(n)), true
+	}
+
+	return family, decimal.Zero, false
+}
+
+func attributeValue(f *schema.ProductFilter, key string) string {
+	for _, a := range f.AttributeFilters {
+		if a.Key != key {
+			continue
+		}
+
+		if a.Value != nil {
+			return *a.Value
+		}
+
+		return util.StringValue(a.ValueRegex)
+	}
+
+	return ""
+}
@@ -0,0 +1,164 @@
+package commitments
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/util"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func instance(name string, instanceType string, count int64, price string) *schema.Resource {
+	c := &schema.CostComponent{
+		Name:           "Instance usage (Linux/UNIX, on-demand, " + instanceType + ")",
+		UnitMultiplier: decimal.NewFromInt(1),
+		HourlyQuantity: util.DecimalPtr(decimal.NewFromInt(count)),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    util.StrPtr("aws"),
+			Region:        util.StrPtr("us-east-1"),
+			Service:       util.StrPtr("AmazonEC2"),
+			ProductFamily: util.StrPtr("Compute Instance"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "instanceType", Value: util.StrPtr(instanceType)},
+				{Key: "tenancy", Value: util.StrPtr("Shared")},
+				{Key: "operatingSystem", Value: util.StrPtr("Linux")},
+				{Key: "capacitystatus", Value: util.StrPtr("Used")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{PurchaseOption: util.StrPtr("on_demand")},
+	}
+	c.SetPrice(decimal.RequireFromString(price))
+
+	return &schema.Resource{Name: name, CostComponents: []*schema.CostComponent{c}}
+}
+
+func lambda(name string, monthlyCost string) *schema.Resource {
+	c := &schema.CostComponent{
+		Name:            "Duration",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: util.DecimalPtr(decimal.NewFromInt(1)),
+		ProductFilter: &schema.ProductFilter{
+			VendorName: util.StrPtr("aws"),
+			Region:     util.StrPtr("us-east-1"),
+			Service:    util.StrPtr("AWSLambda"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: util.StrPtr("/GB-Second/")},
+			},
+		},
+	}
+	c.SetPrice(decimal.RequireFromString(monthlyCost))
+
+	return &schema.Resource{Name: name, CostComponents: []*schema.CostComponent{c}}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := map[string]struct {
+		portfolio *Portfolio
+		resources []*schema.Resource
+		// expected hourly cost of each resource after the commitments
+		hourlyCosts map[string]string
+		used        []string
+		onDemand    string
+	}{
+		"reserved instances cover exact matches before other sizes": {
+			portfolio: &Portfolio{
+				ReservedInstances: []*ReservedInstance{
+					{InstanceType: "m5.large", Region: "us-east-1", Count: 3, HourlyCost: 0.06},
+				},
+			},
+			resources: []*schema.Resource{
+				instance("aws_instance.a_xlarge", "m5.xlarge", 1, "0.192"),
+				instance("aws_instance.b_large", "m5.large", 1, "0.096"),
+				instance("aws_instance.c_c5", "c5.large", 1, "0.085"),
+			},
+			hourlyCosts: map[string]string{
+				"aws_instance.a_xlarge": "0.12",
+				"aws_instance.b_large":  "0.06",
+				"aws_instance.c_c5":     "0.085",
+			},
+			used:     []string{"0.18"},
+			onDemand: "0.085",
+		},
+		"savings plans are applied after reserved instances": {
+			portfolio: &Portfolio{
+				ReservedInstances: []*ReservedInstance{
+					{InstanceType: "m5.large", Region: "us-east-1", Count: 1, HourlyCost: 0.06},
+				},
+				SavingsPlans: []*SavingsPlan{
+					{Type: "compute", HourlyCommitment: 0.05, DiscountPercent: 50},
+					{Type: "ec2_instance", HourlyCommitment: 0.02, DiscountPercent: 60, InstanceFamily: "m5", Region: "us-east-1"},
+				},
+			},
+			resources: []*schema.Resource{
+				instance("aws_instance.web", "m5.large", 2, "0.1"),
+			},
+			// RI: 0.1 → 0.06, EC2 SP: 0.05 of on-demand for 0.02, compute
+			// SP: remaining 0.05 of on-demand for 0.025
+			hourlyCosts: map[string]string{
+				"aws_instance.web": "0.105",
+			},
+			used:     []string{"0.06", "0.02", "0.025"},
+			onDemand: "0",
+		},
+		"savings plans cover usage with the highest discount first": {
+			portfolio: &Portfolio{
+				SavingsPlans: []*SavingsPlan{
+					{Type: "compute", HourlyCommitment: 0.08, DiscountPercent: 20, Discounts: map[string]float64{"ec2": 60}},
+				},
+			},
+			resources: []*schema.Resource{
+				lambda("aws_lambda_function.a", "73"),
+				instance("aws_instance.b", "t3.micro", 1, "0.2"),
+			},
+			hourlyCosts: map[string]string{
+				"aws_lambda_function.a": "0.1",
+				"aws_instance.b":        "0.08",
+			},
+			used:     []string{"0.08"},
+			onDemand: "0.1",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			report := tc.portfolio.Allocate(tc.resources)
+
+			for _, r := range tc.resources {
+				r.CalculateCosts()
+				require.NotNil(t, r.HourlyCost, r.Name)
+				assert.Equal(t, tc.hourlyCosts[r.Name], r.HourlyCost.Round(6).String(), r.Name)
+			}
+
+			used := make([]string, 0, len(report.Commitments))
+			for _, c := range report.Commitments {
+				used = append(used, c.HourlyUsed.String())
+			}
+			assert.Equal(t, tc.used, used)
+			assert.Equal(t, tc.onDemand, report.HourlyOnDemandCost.String())
+		})
+	}
+}
+
+func TestInstanceTypeSize(t *testing.T) {
+	tests := []struct {
+		instanceType string
+		family       string
+		factor       string
+		ok           bool
+	}{
+		{"t3.nano", "t3", "0.25", true},
+		{"m5.xlarge", "m5", "8", true},
+		{"m5.12xlarge", "m5", "96", true},
+		{"m5.metal", "m5", "0", false},
+		{"invalid", "", "0", false},
+	}
+
+	for _, tc := range tests {
+		family, factor, ok := instanceTypeSize(tc.instanceType)
+		assert.Equal(t, tc.family, family, tc.instanceType)
+		assert.Equal(t, tc.factor, factor.String(), tc.instanceType)
+		assert.Equal(t, tc.ok, ok, tc.instanceType)
+	}
+}
@@ -0,0 +1,166 @@
+package commitments
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/infracost/infracost/internal/version"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const minPortfolioFileVersion = "0.1"
+const maxPortfolioFileVersion = "0.1"
+
+// Portfolio is an account-level set of AWS Reserved Instances and Savings
+// Plans that are allocated across the resources of all the projects.
+type Portfolio struct {
+	Version           string              `yaml:"version"`
+	ReservedInstances []*ReservedInstance `yaml:"reserved_instances"`
+	SavingsPlans      []*SavingsPlan      `yaml:"savings_plans"`
+}
+
+// ReservedInstance is a number of regional EC2 Reserved Instances. The hourly
+// cost is the effective hourly cost of each instance, including any amortized
+// upfront payment.
+type ReservedInstance struct {
+	Name            string  `yaml:"name,omitempty"`
+	InstanceType    string  `yaml:"instance_type"`
+	Region          string  `yaml:"region"`
+	Count           int64   `yaml:"count"`
+	HourlyCost      float64 `yaml:"hourly_cost"`
+	OperatingSystem string  `yaml:"operating_system,omitempty"`
+	Tenancy         string  `yaml:"tenancy,omitempty"`
+}
+
+// SavingsPlan is a Compute or EC2 Instance Savings Plan. The hourly commitment
+// is spent at the Savings Plan rates, which are the on-demand rates less the
+// discount percentage. Discounts can be set per service (ec2, fargate or
+// lambda) since AWS applies Savings Plans to the usage with the highest
+// discount first.
+type SavingsPlan struct {
+	Name             string             `yaml:"name,omitempty"`
+	Type             string             `yaml:"type"`
+	HourlyCommitment float64            `yaml:"hourly_commitment"`
+	DiscountPercent  float64            `yaml:"discount_percent"`
+	Discounts        map[string]float64 `yaml:"discounts,omitempty"`
+	InstanceFamily   string             `yaml:"instance_family,omitempty"`
+	Region           string             `yaml:"region,omitempty"`
+}
+
+const (
+	savingsPlanTypeCompute     = "compute"
+	savingsPlanTypeEC2Instance = "ec2_instance"
+)
+
+var operatingSystems = map[string]string{
+	"linux":   "Linux",
+	"windows": "Windows",
+	"rhel":    "RHEL",
+	"suse":    "SUSE",
+}
+
+var tenancies = map[string]string{
+	"shared":    "Shared",
+	"dedicated": "Dedicated",
+}
+
+func LoadPortfolio(path string) (*Portfolio, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading commitments file")
+	}
+
+	var p Portfolio
+	err = yaml.Unmarshal(data, &p)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing commitments file")
+	}
+
+	if !version.IsSupportedFileVersion(p.Version, minPortfolioFileVersion, maxPortfolioFileVersion) {
+		return nil, fmt.Errorf("Invalid commitments file version. Supported versions are %s ≤ x ≤ %s", minPortfolioFileVersion, maxPortfolioFileVersion)
+	}
+
+	err = p.validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid commitments file")
+	}
+
+	return &p, nil
+}
+
+func (p *Portfolio) validate() error {
+	for i, ri := range p.ReservedInstances {
+		if ri.InstanceType == "" || ri.Region == "" {
+			return fmt.Errorf("reserved_instances[%d] must have an instance_type and region", i)
+		}
+		if ri.Count <= 0 {
+			return fmt.Errorf("reserved_instances[%d] count must be greater than 0", i)
+		}
+		if ri.HourlyCost < 0 {
+			return fmt.Errorf("reserved_instances[%d] hourly_cost can't be negative", i)
+		}
+		if _, ok := operatingSystems[ri.operatingSystem()]; !ok {
+			return fmt.Errorf("reserved_instances[%d] operating_system must be one of linux, windows, rhel, suse", i)
+		}
+		if _, ok := tenancies[ri.tenancy()]; !ok {
+			return fmt.Errorf("reserved_instances[%d] tenancy must be one of shared, dedicated", i)
+		}
+	}
+
+	for i, sp := range p.SavingsPlans {
+		switch sp.Type {
+		case savingsPlanTypeCompute:
+		case savingsPlanTypeEC2Instance:
+			if sp.InstanceFamily == "" || sp.Region == "" {
+				return fmt.Errorf("savings_plans[%d] must have an instance_family and region for ec2_instance Savings Plans", i)
+			}
+		default:
+			return fmt.Errorf("savings_plans[%d] type must be one of compute, ec2_instance", i)
+		}
+
+		if sp.HourlyCommitment <= 0 {
+			return fmt.Errorf("savings_plans[%d] hourly_commitment must be greater than 0", i)
+		}
+
+		discounts := []float64{sp.DiscountPercent}
+		for k, d := range sp.Discounts {
+			if k != kindEC2 && k != kindFargate && k != kindLambda {
+				return fmt.Errorf("savings_plans[%d] discounts must be for ec2, fargate or lambda, got %s", i, k)
+			}
+			discounts = append(discounts, d)
+		}
+
+		for _, d := range discounts {
+			if d < 0 || d >= 100 {
+				return fmt.Errorf("savings_plans[%d] discount percentages must be between 0 and 100", i)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (ri *ReservedInstance) operatingSystem() string {
+	if ri.OperatingSystem == "" {
+		return "linux"
+	}
+	return strings.ToLower(ri.OperatingSystem)
+}
+
+func (ri *ReservedInstance) tenancy() string {
+	if ri.Tenancy == "" {
+		return "shared"
+	}
+	return strings.ToLower(ri.Tenancy)
+}
+
+// discount returns the discount percentage of the Savings Plan for the kind
+// of usage.
+func (sp *SavingsPlan) discount(kind string) float64 {
+	if d, ok := sp.Discounts[kind]; ok {
+		return d
+	}
+	return sp.DiscountPercent
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
@@ -22,6 +23,8 @@ type Project struct {
 	TerraformCloudToken string `yaml:"terraform_cloud_token,omitempty" envconfig:"INFRACOST_TERRAFORM_CLOUD_TOKEN"`
 	UsageFile           string `yaml:"usage_file,omitempty" ignored:"true"`
 	TerraformUseState   bool   `yaml:"terraform_use_state,omitempty" ignored:"true"`
+	TerraformSourceDir  string `yaml:"terraform_source_dir,omitempty" ignored:"true"`
+	CompareTo           string `yaml:"compare_to,omitempty" ignored:"true"`
 }
 
 type Config struct {
@@ -38,14 +41,31 @@ type Config struct {
 	DefaultPricingAPIEndpoint string `yaml:"default_pricing_api_endpoint,omitempty" envconfig:"INFRACOST_DEFAULT_PRICING_API_ENDPOINT"`
 	DashboardAPIEndpoint      string `yaml:"dashboard_api_endpoint,omitempty" envconfig:"INFRACOST_DASHBOARD_API_ENDPOINT"`
 	EnableDashboard           bool   `yaml:"enable_dashboard,omitempty" envconfig:"INFRACOST_ENABLE_DASHBOARD"`
+	PricingSnapshot           string `yaml:"pricing_snapshot,omitempty" envconfig:"INFRACOST_PRICING_SNAPSHOT"`
 
-	Currency string `envconfig:"INFRACOST_CURRENCY"`
+	StrictPricing      bool   `yaml:"strict_pricing,omitempty" envconfig:"INFRACOST_STRICT_PRICING"`
+	PriceOverridesFile string `yaml:"price_overrides_file,omitempty" envconfig:"INFRACOST_PRICE_OVERRIDES_FILE"`
+	CommitmentsFile    string `yaml:"commitments_file,omitempty" envconfig:"INFRACOST_COMMITMENTS_FILE"`
 
-	Projects      []*Project `yaml:"projects" ignored:"true"`
-	Format        string     `yaml:"format,omitempty" ignored:"true"`
-	ShowSkipped   bool       `yaml:"show_skipped,omitempty" ignored:"true"`
-	SyncUsageFile bool       `yaml:"sync_usage_file,omitempty" ignored:"true"`
-	Fields        []string   `yaml:"fields,omitempty" ignored:"true"`
+	SpotPriceHistoryFile string `yaml:"spot_price_history_file,omitempty" envconfig:"INFRACOST_SPOT_PRICE_HISTORY_FILE"`
+	SpotPriceStatistic   string `yaml:"spot_price_statistic,omitempty" envconfig:"INFRACOST_SPOT_PRICE_STATISTIC"`
+
+	AsOf string `yaml:"as_of,omitempty" envconfig:"INFRACOST_AS_OF"`
+
+	PricingCacheDisabled bool          `yaml:"pricing_cache_disabled,omitempty" envconfig:"INFRACOST_PRICING_CACHE_DISABLED"`
+	PricingCacheTTL      time.Duration `yaml:"pricing_cache_ttl,omitempty" envconfig:"INFRACOST_PRICING_CACHE_TTL"`
+
+	Currency      string `envconfig:"INFRACOST_CURRENCY"`
+	ExchangeRates string `yaml:"exchange_rates,omitempty" envconfig:"INFRACOST_EXCHANGE_RATES"`
+
+	Projects            []*Project `yaml:"projects" ignored:"true"`
+	Format              string     `yaml:"format,omitempty" ignored:"true"`
+	ShowSkipped         bool       `yaml:"show_skipped,omitempty" ignored:"true"`
+	SyncUsageFile       bool       `yaml:"sync_usage_file,omitempty" ignored:"true"`
+	SyncUsageFileDryRun bool       `yaml:"sync_usage_file_dry_run,omitempty" ignored:"true"`
+	Fields              []string   `yaml:"fields,omitempty" ignored:"true"`
+	GroupBy             []string   `yaml:"group_by,omitempty" ignored:"true"`
+	Policies            PolicySpec `yaml:"policies,omitempty" ignored:"true"`
 
 	// for testing
 	EventsDisabled       bool
@@ -74,7 +94,8 @@ func DefaultConfig() *Config {
 		Format: "table",
 		Fields: []string{"monthlyQuantity", "unit", "monthlyCost"},
 
-		EventsDisabled: IsTest(),
+		EventsDisabled:       IsTest(),
+		PricingCacheDisabled: IsTest(),
 	}
 }
 
@@ -85,6 +106,7 @@ func (c *Config) LoadFromConfigFile(path string) error {
 	}
 
 	c.Projects = cfgFile.Projects
+	c.Policies = cfgFile.Policies
 
 	// Reload the environment to overwrite any of the config file configs
 	err = c.LoadFromEnv()
@@ -172,6 +194,16 @@ func (c *Config) ConfigureLogger() error {
 	return nil
 }
 
+// PricingCurrency is the currency prices are fetched in. When exchange rates
+// are set, prices are fetched in USD and the output is converted to the
+// configured currency.
+func (c *Config) PricingCurrency() string {
+	if c.ExchangeRates != "" || c.Currency == "" {
+		return "USD"
+	}
+	return c.Currency
+}
+
 func (c *Config) IsLogging() bool {
 	return c.LogLevel != ""
 }
@@ -5,8 +5,8 @@ import (
 	"io/ioutil"
 	"strings"
 
+	"github.com/infracost/infracost/internal/version"
 	"github.com/pkg/errors"
-	"golang.org/x/mod/semver"
 	"gopkg.in/yaml.v2"
 )
 
@@ -16,6 +16,7 @@ const maxConfigFileVersion = "0.1"
 type ConfigFileSpec struct { // nolint:revive
 	Version  string     `yaml:"version"`
 	Projects []*Project `yaml:"projects" ignored:"true"`
+	Policies PolicySpec `yaml:"policies,omitempty" ignored:"true"`
 }
 
 func LoadConfigFile(path string) (ConfigFileSpec, error) {
@@ -35,16 +36,14 @@ func LoadConfigFile(path string) (ConfigFileSpec, error) {
 		return cfgFile, errors.New("Error parsing config YAML: " + strings.TrimPrefix(err.Error(), "yaml: "))
 	}
 
-	if !checkVersion(cfgFile.Version) {
+	if !version.IsSupportedFileVersion(cfgFile.Version, minConfigFileVersion, maxConfigFileVersion) {
 		return cfgFile, fmt.Errorf("Invalid config file version. Supported versions are %s ≤ x ≤ %s", minConfigFileVersion, maxConfigFileVersion)
 	}
 
-	return cfgFile, nil
-}
-
-func checkVersion(v string) bool {
-	if !strings.HasPrefix(v, "v") {
-		v = "v" + v
+	err = cfgFile.Policies.Validate()
+	if err != nil {
+		return cfgFile, err
 	}
-	return semver.Compare(v, "v"+minConfigFileVersion) >= 0 && semver.Compare(v, "v"+maxConfigFileVersion) <= 0
+
+	return cfgFile, nil
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -13,9 +14,12 @@ import (
 
 var configurationVersion = "0.1"
 
+var defaultPricingCacheTTL = 24 * time.Hour
+
 type Configuration struct {
-	Version  string `yaml:"version"`
-	Currency string `yaml:"currency,omitempty"`
+	Version         string `yaml:"version"`
+	Currency        string `yaml:"currency,omitempty"`
+	PricingCacheTTL string `yaml:"pricing_cache_ttl,omitempty"`
 }
 
 func loadConfiguration(cfg *Config) error {
@@ -39,6 +43,16 @@ func loadConfiguration(cfg *Config) error {
 		cfg.Currency = "USD"
 	}
 
+	if cfg.PricingCacheTTL == 0 && cfg.Configuration.PricingCacheTTL != "" {
+		cfg.PricingCacheTTL, err = time.ParseDuration(cfg.Configuration.PricingCacheTTL)
+		if err != nil {
+			return errors.New("Error parsing pricing_cache_ttl in configuration: " + err.Error())
+		}
+	}
+	if cfg.PricingCacheTTL == 0 {
+		cfg.PricingCacheTTL = defaultPricingCacheTTL
+	}
+
 	return nil
 }
 
@@ -83,3 +97,7 @@ func writeConfigurationFile(c Configuration) error {
 func ConfigurationFilePath() string {
 	return path.Join(userConfigDir(), "configuration.yml")
 }
+
+func PricingCacheDir() string {
+	return path.Join(userConfigDir(), "cache", "pricing")
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PolicySpec is the policies section of the config file.
+type PolicySpec struct {
+	Budgets []*BudgetRule `yaml:"budgets,omitempty"`
+}
+
+// BudgetRule is a limit on the monthly cost. Rules with a project pattern,
+// where * matches any characters, are checked against each matching project,
+// otherwise they are checked against the total of all the projects. The
+// resource type and tags narrow the rule down to the matching resources.
+// Increase limits are only checked when there's a previous cost to compare
+// with, e.g. when running diff.
+type BudgetRule struct {
+	Name                      string            `yaml:"name,omitempty"`
+	Project                   string            `yaml:"project,omitempty"`
+	ResourceType              string            `yaml:"resource_type,omitempty"`
+	Tags                      map[string]string `yaml:"tags,omitempty"`
+	MaxMonthlyCost            *float64          `yaml:"max_monthly_cost,omitempty"`
+	MaxMonthlyIncrease        *float64          `yaml:"max_monthly_increase,omitempty"`
+	MaxMonthlyIncreasePercent *float64          `yaml:"max_monthly_increase_percent,omitempty"`
+}
+
+// Validate checks that each budget rule has a limit.
+func (p PolicySpec) Validate() error {
+	for i, b := range p.Budgets {
+		if b.MaxMonthlyCost == nil && b.MaxMonthlyIncrease == nil && b.MaxMonthlyIncreasePercent == nil {
+			return fmt.Errorf("Invalid budget %s: expected at least one of max_monthly_cost, max_monthly_increase or max_monthly_increase_percent", b.Label(i))
+		}
+	}
+
+	return nil
+}
+
+// Label returns the name of the rule, or its position in the config file if
+// it doesn't have a name.
+func (b *BudgetRule) Label(i int) string {
+	if b.Name != "" {
+		return b.Name
+	}
+
+	return fmt.Sprintf("#%d", i+1)
+}
+
+// MatchesProject returns true if the project name matches the rule's project
+// pattern.
+func (b *BudgetRule) MatchesProject(name string) bool {
+	parts := strings.Split(b.Project, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$").MatchString(name)
+}
@@ -0,0 +1,435 @@
+package explore
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/mattn/go-runewidth"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultWidth  = 100
+	defaultHeight = 30
+
+	// headerHeight and footerHeight are the number of lines around the rows
+	headerHeight = 2
+	footerHeight = 2
+)
+
+type inputMode int
+
+const (
+	noInput inputMode = iota
+	searchInput
+	filterInput
+)
+
+// Model is the bubbletea model of the explore UI. It shows the projects as a
+// tree of resources, sub-resources and cost components that can be expanded
+// and collapsed.
+type Model struct {
+	out     output.Root
+	opts    output.Options
+	hasPast bool
+
+	view   View
+	order  SortOrder
+	filter Filter
+
+	projects []*Node
+	rows     []Row
+
+	cursor int
+	offset int
+	width  int
+	height int
+
+	mode  inputMode
+	input string
+	err   string
+}
+
+// New returns the model for exploring the output.
+func New(out output.Root, opts output.Options) *Model {
+	m := &Model{
+		out:     out,
+		opts:    opts,
+		hasPast: HasPast(out),
+		width:   defaultWidth,
+		height:  defaultHeight,
+	}
+
+	m.projects = BuildTree(out, m.view, opts)
+	m.refresh()
+
+	return m
+}
+
+// Init implements tea.Model.
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scroll()
+	case tea.KeyMsg:
+		if m.mode != noInput {
+			m.updateInput(msg)
+			return m, nil
+		}
+
+		return m, m.updateKey(msg)
+	}
+
+	return m, nil
+}
+
+func (m *Model) updateKey(msg tea.KeyMsg) tea.Cmd {
+	m.err = ""
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "up", "k":
+		m.moveCursor(-1)
+	case "down", "j":
+		m.moveCursor(1)
+	case "pgup":
+		m.moveCursor(-m.pageSize())
+	case "pgdown":
+		m.moveCursor(m.pageSize())
+	case "home", "g":
+		m.moveCursor(-len(m.rows))
+	case "end", "G":
+		m.moveCursor(len(m.rows))
+	case "enter", " ":
+		if n := m.selected(); n != nil && len(n.Children) > 0 {
+			n.Expanded = !n.Expanded
+			m.refresh()
+		}
+	case "right", "l":
+		if n := m.selected(); n != nil && len(n.Children) > 0 && !n.Expanded {
+			n.Expanded = true
+			m.refresh()
+		}
+	case "left", "h":
+		m.collapse()
+	case "e":
+		SetExpanded(m.projects, true)
+		m.refresh()
+	case "c":
+		SetExpanded(m.projects, false)
+		m.refresh()
+	case "s":
+		m.order = (m.order + 1) % 3
+		m.refresh()
+	case "v":
+		if !m.hasPast {
+			m.err = "There's no past breakdown to compare with"
+			return nil
+		}
+		m.setView((m.view + 1) % 3)
+	case "/":
+		m.mode = searchInput
+		m.input = m.filter.Search
+	case "f":
+		m.mode = filterInput
+		m.input = m.filter.String()
+	case "esc":
+		m.filter = Filter{}
+		m.refresh()
+	}
+
+	return nil
+}
+
+// updateInput handles the keys when typing a search or filter. The search is
+// applied as it's typed, and the filter when enter is pressed since it's not
+// valid until it's complete.
+func (m *Model) updateInput(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		if m.mode == filterInput {
+			f, err := ParseFilter(m.input)
+			if err != nil {
+				m.err = err.Error()
+				return
+			}
+			f.Search = m.filter.Search
+			m.filter = f
+		}
+		m.mode = noInput
+	case tea.KeyEsc:
+		if m.mode == searchInput {
+			m.filter.Search = ""
+		}
+		m.mode = noInput
+		m.err = ""
+	case tea.KeyBackspace:
+		if len(m.input) > 0 {
+			r := []rune(m.input)
+			m.input = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.input += " "
+	case tea.KeyRunes:
+		m.input += string(msg.Runes)
+	default:
+		return
+	}
+
+	if m.mode == searchInput {
+		m.filter.Search = m.input
+	}
+
+	m.refresh()
+}
+
+func (m *Model) setView(view View) {
+	expanded := expandedKeys(m.projects)
+
+	m.view = view
+	m.projects = BuildTree(m.out, view, m.opts)
+	applyExpanded(m.projects, expanded)
+
+	m.refresh()
+}
+
+// collapse collapses the selected node, or moves to its parent if it's
+// already collapsed.
+func (m *Model) collapse() {
+	n := m.selected()
+	if n == nil {
+		return
+	}
+
+	if n.Expanded && len(n.Children) > 0 {
+		n.Expanded = false
+		m.refresh()
+		return
+	}
+
+	depth := m.rows[m.cursor].Depth
+	for i := m.cursor - 1; i >= 0; i-- {
+		if m.rows[i].Depth < depth {
+			m.cursor = i
+			m.scroll()
+			return
+		}
+	}
+}
+
+func (m *Model) selected() *Node {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil
+	}
+
+	return m.rows[m.cursor].Node
+}
+
+func (m *Model) refresh() {
+	m.rows = Rows(m.projects, m.filter, m.order)
+	m.moveCursor(0)
+}
+
+func (m *Model) moveCursor(delta int) {
+	m.cursor += delta
+
+	if m.cursor >= len(m.rows) {
+		m.cursor = len(m.rows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+
+	m.scroll()
+}
+
+// scroll keeps the cursor on the screen.
+func (m *Model) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+
+	if m.cursor >= m.offset+m.pageSize() {
+		m.offset = m.cursor - m.pageSize() + 1
+	}
+}
+
+func (m *Model) pageSize() int {
+	size := m.height - headerHeight - footerHeight
+	if size < 1 {
+		return 1
+	}
+
+	return size
+}
+
+// View implements tea.Model.
+func (m *Model) View() string {
+	var b strings.Builder
+
+	b.WriteString(m.header())
+	b.WriteString("\n\n")
+
+	end := m.offset + m.pageSize()
+	if end > len(m.rows) {
+		end = len(m.rows)
+	}
+
+	lines := 0
+	for i := m.offset; i < end; i++ {
+		b.WriteString(m.row(m.rows[i], i == m.cursor))
+		b.WriteString("\n")
+		lines++
+	}
+
+	for ; lines < m.pageSize(); lines++ {
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.footer())
+
+	return b.String()
+}
+
+func (m *Model) header() string {
+	parts := []string{
+		ui.BoldString("Infracost explore"),
+		"view: " + m.view.String(),
+		"sort: " + m.order.String(),
+	}
+
+	if f := m.filter.String(); f != "" {
+		parts = append(parts, "filter: "+f)
+	}
+
+	if m.filter.Search != "" {
+		parts = append(parts, "search: "+m.filter.Search)
+	}
+
+	return strings.Join(parts, "  │  ")
+}
+
+func (m *Model) footer() string {
+	switch {
+	case m.err != "":
+		return ui.ErrorString(m.err)
+	case m.mode == searchInput:
+		return "Search: " + m.input + "█"
+	case m.mode == filterInput:
+		return "Filter (type:<type> tag:<key>=<value>): " + m.input + "█"
+	}
+
+	help := "↑/↓ move  enter expand/collapse  e/c expand/collapse all  s sort  / search  f filter  esc clear"
+	if m.hasPast {
+		help += "  v view"
+	}
+
+	return ui.FaintString(help + "  q quit")
+}
+
+// row renders the node name on the left and its cost on the right. Cost
+// components also show their quantity and unit.
+func (m *Model) row(r Row, selected bool) string {
+	n := r.Node
+
+	marker := "  "
+	if len(n.Children) > 0 {
+		if n.Expanded {
+			marker = "▾ "
+		} else {
+			marker = "▸ "
+		}
+	}
+
+	cursor := "  "
+	if selected {
+		cursor = "› "
+	}
+
+	left := cursor + strings.Repeat("  ", r.Depth) + marker + n.Name
+
+	right := m.formatCost(n.MonthlyCost)
+	if n.Kind == CostComponentNode {
+		right = fmt.Sprintf("%s %s  %s", output.FormatQuantity(n.Quantity), n.Unit, right)
+	}
+
+	// Truncate the name so the cost always fits
+	maxLeft := m.width - runewidth.StringWidth(right) - 2
+	if maxLeft > 1 && runewidth.StringWidth(left) > maxLeft {
+		left = runewidth.Truncate(left, maxLeft, "…")
+	}
+
+	padding := m.width - runewidth.StringWidth(left) - runewidth.StringWidth(right)
+	if padding < 1 {
+		padding = 1
+	}
+
+	line := left + strings.Repeat(" ", padding) + right
+
+	switch {
+	case selected:
+		return ui.PrimaryString(line)
+	case n.Kind == ProjectNode:
+		return ui.BoldString(line)
+	case n.Kind == CostComponentNode:
+		return ui.FaintString(line)
+	}
+
+	return line
+}
+
+func (m *Model) formatCost(d *decimal.Decimal) string {
+	s := output.FormatCost2DP(m.out.Currency, d)
+
+	if m.view == ViewDiff && d != nil && d.IsPositive() {
+		return "+" + s
+	}
+
+	return s
+}
+
+// expandedKeys returns the paths of the expanded nodes so they can be kept
+// expanded when the tree is rebuilt for another view.
+func expandedKeys(projects []*Node) map[string]bool {
+	keys := make(map[string]bool)
+
+	var walk func(prefix string, nodes []*Node)
+	walk = func(prefix string, nodes []*Node) {
+		for _, n := range nodes {
+			key := prefix + "/" + n.Name
+			keys[key] = n.Expanded
+			walk(key, n.Children)
+		}
+	}
+
+	walk("", projects)
+
+	return keys
+}
+
+func applyExpanded(projects []*Node, keys map[string]bool) {
+	var walk func(prefix string, nodes []*Node)
+	walk = func(prefix string, nodes []*Node) {
+		for _, n := range nodes {
+			key := prefix + "/" + n.Name
+			if expanded, ok := keys[key]; ok {
+				n.Expanded = expanded
+			}
+			walk(key, n.Children)
+		}
+	}
+
+	walk("", projects)
+}
@@ -0,0 +1,110 @@
+package explore
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func keyPress(m *Model, keys ...string) {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "left":
+			msg = tea.KeyMsg{Type: tea.KeyLeft}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		m.Update(msg)
+	}
+}
+
+func TestModelExpandAndCollapse(t *testing.T) {
+	m := New(testOutput(), output.Options{})
+
+	// Move to aws_instance.web and expand it
+	keyPress(m, "down", "down", "enter")
+	assert.Equal(t, []string{"org/prod", "aws_db_instance.db", "aws_instance.web", "Instance usage", "root_block_device", "aws_lambda_function.api"}, rowNames(m.rows))
+
+	// Collapsing from a child moves to the parent, then collapses it
+	keyPress(m, "down", "left")
+	assert.Equal(t, "aws_instance.web", m.selected().Name)
+	keyPress(m, "left")
+	assert.Equal(t, []string{"org/prod", "aws_db_instance.db", "aws_instance.web", "aws_lambda_function.api"}, rowNames(m.rows))
+
+	keyPress(m, "e")
+	assert.Len(t, m.rows, 7)
+	keyPress(m, "c")
+	assert.Len(t, m.rows, 4)
+}
+
+func TestModelSearchAndFilter(t *testing.T) {
+	m := New(testOutput(), output.Options{})
+
+	keyPress(m, "/", "lambda", "enter")
+	assert.Equal(t, []string{"org/prod", "aws_lambda_function.api"}, rowNames(m.rows))
+
+	keyPress(m, "esc", "f", "tag:team=data", "enter")
+	assert.Equal(t, []string{"org/prod", "aws_db_instance.db"}, rowNames(m.rows))
+	assert.Contains(t, m.View(), "filter: tag:team=data")
+
+	keyPress(m, "esc", "f", "team", "enter")
+	assert.Equal(t, "Invalid filter team. Expected type:<type> or tag:<key>=<value>", m.err)
+
+	keyPress(m, "esc", "esc")
+	assert.Len(t, m.rows, 4)
+}
+
+func TestModelSortAndViews(t *testing.T) {
+	m := New(testOutput(), output.Options{})
+
+	keyPress(m, "s", "s")
+	assert.Equal(t, SortByCostAsc, m.order)
+	assert.Equal(t, "aws_instance.web", m.rows[1].Node.Name)
+
+	keyPress(m, "v")
+	assert.Equal(t, ViewPast, m.view)
+	assert.Equal(t, []string{"org/prod", "aws_instance.web"}, rowNames(m.rows))
+
+	keyPress(m, "v")
+	assert.Equal(t, ViewDiff, m.view)
+	assert.Contains(t, m.View(), "+$20.00")
+
+	keyPress(m, "v")
+	assert.Equal(t, ViewCurrent, m.view)
+}
+
+func TestModelViewWithoutPast(t *testing.T) {
+	out := testOutput()
+	out.Projects[0].PastBreakdown = nil
+	out.Projects[0].Diff = nil
+
+	m := New(out, output.Options{})
+	keyPress(m, "v")
+
+	assert.Equal(t, ViewCurrent, m.view)
+	assert.Equal(t, "There's no past breakdown to compare with", m.err)
+}
+
+func TestModelScroll(t *testing.T) {
+	m := New(testOutput(), output.Options{})
+	m.Update(tea.WindowSizeMsg{Width: 60, Height: 6})
+
+	keyPress(m, "e", "down", "down", "down", "down")
+
+	assert.Equal(t, 4, m.cursor)
+	assert.Equal(t, 3, m.offset)
+
+	lines := strings.Split(m.View(), "\n")
+	assert.Len(t, lines, 6)
+	assert.Contains(t, lines[3], "› ")
+}
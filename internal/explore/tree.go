@@ -0,0 +1,320 @@
+package explore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost/internal/output"
+	"github.com/shopspring/decimal"
+)
+
+// View is which breakdown of the projects is shown.
+type View int
+
+const (
+	ViewCurrent View = iota
+	ViewPast
+	ViewDiff
+)
+
+func (v View) String() string {
+	switch v {
+	case ViewPast:
+		return "past"
+	case ViewDiff:
+		return "diff"
+	}
+
+	return "current"
+}
+
+// SortOrder is how the resources are sorted within a project.
+type SortOrder int
+
+const (
+	SortByName SortOrder = iota
+	SortByCostDesc
+	SortByCostAsc
+)
+
+func (s SortOrder) String() string {
+	switch s {
+	case SortByCostDesc:
+		return "cost (highest first)"
+	case SortByCostAsc:
+		return "cost (lowest first)"
+	}
+
+	return "name"
+}
+
+// NodeKind is the kind of row in the tree.
+type NodeKind int
+
+const (
+	ProjectNode NodeKind = iota
+	ResourceNode
+	CostComponentNode
+)
+
+// Node is a project, resource, sub-resource or cost component in the tree.
+// Projects, resources and sub-resources can be expanded to show their
+// children.
+type Node struct {
+	Kind        NodeKind
+	Name        string
+	Address     string
+	Tags        map[string]string
+	Unit        string
+	Quantity    *decimal.Decimal
+	MonthlyCost *decimal.Decimal
+	Children    []*Node
+	Expanded    bool
+}
+
+// Row is a node in the list of visible rows, with its depth in the tree.
+type Row struct {
+	Node  *Node
+	Depth int
+}
+
+// Filter limits the resources that are shown. Search matches part of the
+// resource address, and the type and tags must match exactly.
+type Filter struct {
+	Search       string
+	ResourceType string
+	Tags         map[string]string
+}
+
+// ParseFilter parses a filter in the form type:<type> tag:<key>=<value>.
+// Several tags can be given and they must all match.
+func ParseFilter(s string) (Filter, error) {
+	f := Filter{Tags: make(map[string]string)}
+
+	for _, part := range strings.Fields(s) {
+		switch {
+		case strings.HasPrefix(part, "type:"):
+			f.ResourceType = strings.TrimPrefix(part, "type:")
+		case strings.HasPrefix(part, "tag:"):
+			kv := strings.SplitN(strings.TrimPrefix(part, "tag:"), "=", 2)
+			if len(kv) != 2 || kv[0] == "" {
+				return f, fmt.Errorf("Invalid tag filter %s. Expected tag:<key>=<value>", part)
+			}
+			f.Tags[kv[0]] = kv[1]
+		default:
+			return f, fmt.Errorf("Invalid filter %s. Expected type:<type> or tag:<key>=<value>", part)
+		}
+	}
+
+	return f, nil
+}
+
+// IsEmpty returns true if the filter matches all the resources.
+func (f Filter) IsEmpty() bool {
+	return f.Search == "" && f.ResourceType == "" && len(f.Tags) == 0
+}
+
+func (f Filter) String() string {
+	parts := make([]string, 0)
+
+	if f.ResourceType != "" {
+		parts = append(parts, "type:"+f.ResourceType)
+	}
+
+	keys := make([]string, 0, len(f.Tags))
+	for k := range f.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("tag:%s=%s", k, f.Tags[k]))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (f Filter) matches(n *Node) bool {
+	if f.Search != "" && !strings.Contains(strings.ToLower(n.Address), strings.ToLower(f.Search)) {
+		return false
+	}
+
+	if f.ResourceType != "" && output.ResourceType(n.Address) != f.ResourceType {
+		return false
+	}
+
+	for k, v := range f.Tags {
+		if n.Tags[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HasPast returns true if any of the projects have a past breakdown, so the
+// past and diff views can be shown.
+func HasPast(out output.Root) bool {
+	for _, p := range out.Projects {
+		if p.PastBreakdown != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BuildTree returns a project node for each project with the resources from
+// the breakdown for the view.
+func BuildTree(out output.Root, view View, opts output.Options) []*Node {
+	projects := make([]*Node, 0, len(out.Projects))
+
+	for _, p := range out.Projects {
+		var breakdown *output.Breakdown
+		switch view {
+		case ViewPast:
+			breakdown = p.PastBreakdown
+		case ViewDiff:
+			breakdown = p.Diff
+		default:
+			breakdown = p.Breakdown
+		}
+
+		project := &Node{
+			Kind:     ProjectNode,
+			Name:     p.Label(opts.DashboardEnabled),
+			Expanded: true,
+		}
+
+		if breakdown != nil {
+			project.MonthlyCost = breakdown.TotalMonthlyCost
+			for _, r := range breakdown.Resources {
+				project.Children = append(project.Children, resourceNode(r, r.Name))
+			}
+		}
+
+		projects = append(projects, project)
+	}
+
+	return projects
+}
+
+func resourceNode(r output.Resource, address string) *Node {
+	n := &Node{
+		Kind:        ResourceNode,
+		Name:        r.Name,
+		Address:     address,
+		Tags:        r.Tags,
+		MonthlyCost: r.MonthlyCost,
+	}
+
+	for _, c := range r.CostComponents {
+		n.Children = append(n.Children, &Node{
+			Kind:        CostComponentNode,
+			Name:        c.Name,
+			Address:     address,
+			Tags:        r.Tags,
+			Unit:        c.Unit,
+			Quantity:    c.MonthlyQuantity,
+			MonthlyCost: c.MonthlyCost,
+		})
+	}
+
+	for _, s := range r.SubResources {
+		sub := resourceNode(s, address)
+		sub.Tags = r.Tags
+		n.Children = append(n.Children, sub)
+	}
+
+	return n
+}
+
+// Rows returns the visible rows of the tree. The resources of each project
+// are filtered and sorted, and the children of collapsed nodes are hidden.
+// Projects are always shown so it's clear when a filter hides all of their
+// resources.
+func Rows(projects []*Node, filter Filter, order SortOrder) []Row {
+	rows := make([]Row, 0)
+
+	for _, p := range projects {
+		rows = append(rows, Row{Node: p})
+
+		if !p.Expanded {
+			continue
+		}
+
+		resources := make([]*Node, 0, len(p.Children))
+		for _, r := range p.Children {
+			if filter.matches(r) {
+				resources = append(resources, r)
+			}
+		}
+
+		sortNodes(resources, order)
+
+		for _, r := range resources {
+			rows = append(rows, nodeRows(r, 1)...)
+		}
+	}
+
+	return rows
+}
+
+func nodeRows(n *Node, depth int) []Row {
+	rows := []Row{{Node: n, Depth: depth}}
+
+	if !n.Expanded {
+		return rows
+	}
+
+	for _, c := range n.Children {
+		rows = append(rows, nodeRows(c, depth+1)...)
+	}
+
+	return rows
+}
+
+// sortNodes sorts the nodes by the order, using the name to break ties so
+// the order is stable. Nodes without a cost are sorted last.
+func sortNodes(nodes []*Node, order SortOrder) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+
+		if order != SortByName {
+			if (a.MonthlyCost == nil) != (b.MonthlyCost == nil) {
+				return b.MonthlyCost == nil
+			}
+
+			if a.MonthlyCost != nil && !a.MonthlyCost.Equal(*b.MonthlyCost) {
+				if order == SortByCostAsc {
+					return a.MonthlyCost.LessThan(*b.MonthlyCost)
+				}
+				return a.MonthlyCost.GreaterThan(*b.MonthlyCost)
+			}
+		}
+
+		return a.Name < b.Name
+	})
+}
+
+// SetExpanded expands or collapses all the resources and sub-resources.
+func SetExpanded(projects []*Node, expanded bool) {
+	for _, p := range projects {
+		for _, r := range p.Children {
+			setExpanded(r, expanded)
+		}
+	}
+}
+
+func setExpanded(n *Node, expanded bool) {
+	if n.Kind == CostComponentNode {
+		return
+	}
+
+	n.Expanded = expanded
+
+	for _, c := range n.Children {
+		setExpanded(c, expanded)
+	}
+}
@@ -0,0 +1,171 @@
+package explore
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/output"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func decimalPtr(i int64) *decimal.Decimal {
+	d := decimal.NewFromInt(i)
+	return &d
+}
+
+func testOutput() output.Root {
+	return output.Root{
+		Currency: "USD",
+		Projects: []output.Project{
+			{
+				Name: "org/prod",
+				PastBreakdown: &output.Breakdown{
+					Resources: []output.Resource{
+						{Name: "aws_instance.web", MonthlyCost: decimalPtr(50)},
+					},
+					TotalMonthlyCost: decimalPtr(50),
+				},
+				Breakdown: &output.Breakdown{
+					Resources: []output.Resource{
+						{
+							Name:        "aws_instance.web",
+							Tags:        map[string]string{"team": "web"},
+							MonthlyCost: decimalPtr(70),
+							CostComponents: []output.CostComponent{
+								{Name: "Instance usage", Unit: "hours", MonthlyQuantity: decimalPtr(730), MonthlyCost: decimalPtr(60)},
+							},
+							SubResources: []output.Resource{
+								{
+									Name:        "root_block_device",
+									MonthlyCost: decimalPtr(10),
+									CostComponents: []output.CostComponent{
+										{Name: "Storage", Unit: "GB", MonthlyQuantity: decimalPtr(100), MonthlyCost: decimalPtr(10)},
+									},
+								},
+							},
+						},
+						{Name: "aws_db_instance.db", Tags: map[string]string{"team": "data"}, MonthlyCost: decimalPtr(200)},
+						{Name: "aws_lambda_function.api", Tags: map[string]string{"team": "web"}},
+					},
+					TotalMonthlyCost: decimalPtr(270),
+				},
+				Diff: &output.Breakdown{
+					Resources: []output.Resource{
+						{Name: "aws_instance.web", MonthlyCost: decimalPtr(20)},
+						{Name: "aws_db_instance.db", MonthlyCost: decimalPtr(200)},
+					},
+					TotalMonthlyCost: decimalPtr(220),
+				},
+			},
+		},
+	}
+}
+
+func rowNames(rows []Row) []string {
+	names := make([]string, 0, len(rows))
+	for _, r := range rows {
+		names = append(names, r.Node.Name)
+	}
+	return names
+}
+
+func TestRows(t *testing.T) {
+	tests := map[string]struct {
+		view     View
+		filter   Filter
+		order    SortOrder
+		expand   bool
+		expected []string
+	}{
+		"sorted by name": {
+			expected: []string{"org/prod", "aws_db_instance.db", "aws_instance.web", "aws_lambda_function.api"},
+		},
+		"sorted by cost": {
+			order:    SortByCostDesc,
+			expected: []string{"org/prod", "aws_db_instance.db", "aws_instance.web", "aws_lambda_function.api"},
+		},
+		"sorted by lowest cost with no cost last": {
+			order:    SortByCostAsc,
+			expected: []string{"org/prod", "aws_instance.web", "aws_db_instance.db", "aws_lambda_function.api"},
+		},
+		"expanded": {
+			filter:   Filter{Search: "web"},
+			expand:   true,
+			expected: []string{"org/prod", "aws_instance.web", "Instance usage", "root_block_device", "Storage"},
+		},
+		"filtered by type": {
+			filter:   Filter{ResourceType: "aws_lambda_function"},
+			expected: []string{"org/prod", "aws_lambda_function.api"},
+		},
+		"filtered by tag": {
+			filter:   Filter{Tags: map[string]string{"team": "web"}},
+			expected: []string{"org/prod", "aws_instance.web", "aws_lambda_function.api"},
+		},
+		"searched by address": {
+			filter:   Filter{Search: "DB_"},
+			expected: []string{"org/prod", "aws_db_instance.db"},
+		},
+		"no matches": {
+			filter:   Filter{Search: "missing"},
+			expected: []string{"org/prod"},
+		},
+		"past view": {
+			view:     ViewPast,
+			expected: []string{"org/prod", "aws_instance.web"},
+		},
+		"diff view": {
+			view:     ViewDiff,
+			order:    SortByCostAsc,
+			expected: []string{"org/prod", "aws_instance.web", "aws_db_instance.db"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			projects := BuildTree(testOutput(), tc.view, output.Options{})
+			if tc.expand {
+				SetExpanded(projects, true)
+			}
+
+			assert.Equal(t, tc.expected, rowNames(Rows(projects, tc.filter, tc.order)))
+		})
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := map[string]struct {
+		input    string
+		expected Filter
+		err      string
+	}{
+		"empty": {
+			input:    "",
+			expected: Filter{Tags: map[string]string{}},
+		},
+		"type and tags": {
+			input:    "type:aws_instance tag:team=web tag:env=prod",
+			expected: Filter{ResourceType: "aws_instance", Tags: map[string]string{"team": "web", "env": "prod"}},
+		},
+		"invalid tag": {
+			input: "tag:team",
+			err:   "Invalid tag filter tag:team. Expected tag:<key>=<value>",
+		},
+		"invalid filter": {
+			input: "aws_instance",
+			err:   "Invalid filter aws_instance. Expected type:<type> or tag:<key>=<value>",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := ParseFilter(tc.input)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, f)
+		})
+	}
+}
@@ -26,6 +26,8 @@ func Combine(currency string, inputs []ReportInput, opts Options) Root {
 
 	projects := make([]Project, 0)
 	summaries := make([]*Summary, 0, len(inputs))
+	var pricingIssues []PricingIssue
+	commitments := make([]*CommitmentSummary, 0, len(inputs))
 
 	for _, input := range inputs {
 
@@ -33,6 +35,10 @@ func Combine(currency string, inputs []ReportInput, opts Options) Root {
 
 		summaries = append(summaries, input.Root.Summary)
 
+		pricingIssues = append(pricingIssues, input.Root.PricingIssues...)
+
+		commitments = append(commitments, input.Root.Commitments)
+
 		if input.Root.TotalHourlyCost != nil {
 			if totalHourlyCost == nil {
 				totalHourlyCost = decimalPtr(decimal.Zero)
@@ -56,6 +62,25 @@ func Combine(currency string, inputs []ReportInput, opts Options) Root {
 	combined.TotalMonthlyCost = totalMonthlyCost
 	combined.TimeGenerated = time.Now()
 	combined.Summary = MergeSummaries(summaries)
+	combined.PricingIssues = pricingIssues
+	combined.Commitments = mergeCommitmentSummaries(commitments)
+	combined.ExchangeRates = mergeExchangeRates(inputs)
+	combined.PriceEffectiveDate = mergePriceEffectiveDates(inputs)
 
 	return combined
 }
+
+// mergePriceEffectiveDates returns the price effective date if all the inputs
+// used the same date, otherwise the combined report has no single date.
+func mergePriceEffectiveDates(inputs []ReportInput) string {
+	date := ""
+
+	for i, input := range inputs {
+		if i > 0 && input.Root.PriceEffectiveDate != date {
+			return ""
+		}
+		date = input.Root.PriceEffectiveDate
+	}
+
+	return date
+}
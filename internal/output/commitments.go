@@ -0,0 +1,146 @@
+package output
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+// CommitmentSummary shows how much of the spend that can be covered by
+// Reserved Instances and Savings Plans is covered, and how much is still
+// charged at on-demand rates.
+type CommitmentSummary struct {
+	OnDemandMonthlyCost        decimal.Decimal `json:"onDemandMonthlyCost"`
+	CoveredOnDemandMonthlyCost decimal.Decimal `json:"coveredOnDemandMonthlyCost"`
+	CoveredMonthlyCost         decimal.Decimal `json:"coveredMonthlyCost"`
+	MonthlySavings             decimal.Decimal `json:"monthlySavings"`
+	CoveragePercent            decimal.Decimal `json:"coveragePercent"`
+	Commitments                []Commitment    `json:"commitments"`
+}
+
+// Commitment shows how much of a Reserved Instance or Savings Plan was used.
+// Any unused commitment is still paid for but isn't included in the project
+// costs since it isn't attributable to a resource.
+type Commitment struct {
+	Name                    string          `json:"name"`
+	Type                    string          `json:"type"`
+	MonthlyCommitment       decimal.Decimal `json:"monthlyCommitment"`
+	MonthlyUsed             decimal.Decimal `json:"monthlyUsed"`
+	UtilizationPercent      decimal.Decimal `json:"utilizationPercent"`
+	UnusedMonthlyCommitment decimal.Decimal `json:"unusedMonthlyCommitment"`
+}
+
+type CommitmentCoverage struct {
+	Commitment          string          `json:"commitment"`
+	Type                string          `json:"type"`
+	MonthlyOnDemandCost decimal.Decimal `json:"monthlyOnDemandCost"`
+	MonthlyCost         decimal.Decimal `json:"monthlyCost"`
+}
+
+func BuildCommitmentSummary(report *schema.CommitmentReport) *CommitmentSummary {
+	if report == nil {
+		return nil
+	}
+
+	s := &CommitmentSummary{
+		OnDemandMonthlyCost:        toMonthly(report.HourlyOnDemandCost),
+		CoveredOnDemandMonthlyCost: toMonthly(report.HourlyCoveredOnDemandCost),
+		CoveredMonthlyCost:         toMonthly(report.HourlyCoveredCost),
+		Commitments:                make([]Commitment, 0, len(report.Commitments)),
+	}
+
+	for _, c := range report.Commitments {
+		s.Commitments = append(s.Commitments, Commitment{
+			Name:                    c.Name,
+			Type:                    string(c.Type),
+			MonthlyCommitment:       toMonthly(c.HourlyCommitment),
+			MonthlyUsed:             toMonthly(c.HourlyUsed),
+			UnusedMonthlyCommitment: toMonthly(c.HourlyCommitment.Sub(c.HourlyUsed)),
+		})
+	}
+
+	s.calculateTotals()
+
+	return s
+}
+
+// calculateTotals calculates the savings and percentages from the costs.
+func (s *CommitmentSummary) calculateTotals() {
+	s.MonthlySavings = s.CoveredOnDemandMonthlyCost.Sub(s.CoveredMonthlyCost)
+	s.CoveragePercent = percent(s.CoveredOnDemandMonthlyCost, s.CoveredOnDemandMonthlyCost.Add(s.OnDemandMonthlyCost))
+
+	for i := range s.Commitments {
+		c := &s.Commitments[i]
+		c.UtilizationPercent = percent(c.MonthlyUsed, c.MonthlyCommitment)
+	}
+}
+
+// mergeCommitmentSummaries combines the commitment summaries of outputs that
+// used the same commitments portfolio. Commitments with the same name are the
+// same commitment, so their usage is added up instead of listing them again.
+func mergeCommitmentSummaries(summaries []*CommitmentSummary) *CommitmentSummary {
+	var merged *CommitmentSummary
+	indexes := make(map[string]int)
+
+	for _, s := range summaries {
+		if s == nil {
+			continue
+		}
+
+		if merged == nil {
+			merged = &CommitmentSummary{Commitments: make([]Commitment, 0)}
+		}
+
+		merged.OnDemandMonthlyCost = merged.OnDemandMonthlyCost.Add(s.OnDemandMonthlyCost)
+		merged.CoveredOnDemandMonthlyCost = merged.CoveredOnDemandMonthlyCost.Add(s.CoveredOnDemandMonthlyCost)
+		merged.CoveredMonthlyCost = merged.CoveredMonthlyCost.Add(s.CoveredMonthlyCost)
+
+		for _, c := range s.Commitments {
+			i, ok := indexes[c.Name]
+			if !ok {
+				indexes[c.Name] = len(merged.Commitments)
+				merged.Commitments = append(merged.Commitments, c)
+				continue
+			}
+
+			m := &merged.Commitments[i]
+			m.MonthlyUsed = m.MonthlyUsed.Add(c.MonthlyUsed)
+			m.UnusedMonthlyCommitment = decimal.Max(m.MonthlyCommitment.Sub(m.MonthlyUsed), decimal.Zero)
+		}
+	}
+
+	if merged != nil {
+		merged.calculateTotals()
+	}
+
+	return merged
+}
+
+func outputCommitmentCoverage(c *schema.CostComponent) []CommitmentCoverage {
+	if len(c.CommitmentCoverage) == 0 {
+		return nil
+	}
+
+	coverage := make([]CommitmentCoverage, 0, len(c.CommitmentCoverage))
+	for _, cov := range c.CommitmentCoverage {
+		coverage = append(coverage, CommitmentCoverage{
+			Commitment:          cov.Commitment,
+			Type:                string(cov.Type),
+			MonthlyOnDemandCost: toMonthly(cov.HourlyOnDemandCost),
+			MonthlyCost:         toMonthly(cov.HourlyCost),
+		})
+	}
+
+	return coverage
+}
+
+func toMonthly(hourly decimal.Decimal) decimal.Decimal {
+	return hourly.Mul(schema.HourToMonthUnitMultiplier).Round(6)
+}
+
+func percent(part decimal.Decimal, total decimal.Decimal) decimal.Decimal {
+	if total.IsZero() {
+		return decimal.Zero
+	}
+
+	return part.Div(total).Mul(decimal.NewFromInt(100)).Round(2)
+}
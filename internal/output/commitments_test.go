@@ -0,0 +1,62 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeCommitmentSummaries(t *testing.T) {
+	commitment := func(name string, monthlyCommitment int64, monthlyUsed int64) Commitment {
+		return Commitment{
+			Name:                    name,
+			Type:                    "savings_plan",
+			MonthlyCommitment:       decimal.NewFromInt(monthlyCommitment),
+			MonthlyUsed:             decimal.NewFromInt(monthlyUsed),
+			UnusedMonthlyCommitment: decimal.NewFromInt(monthlyCommitment - monthlyUsed),
+		}
+	}
+
+	merged := mergeCommitmentSummaries([]*CommitmentSummary{
+		{
+			OnDemandMonthlyCost:        decimal.NewFromInt(10),
+			CoveredOnDemandMonthlyCost: decimal.NewFromInt(60),
+			CoveredMonthlyCost:         decimal.NewFromInt(40),
+			Commitments:                []Commitment{commitment("sp-1", 100, 40), commitment("ri-1", 50, 50)},
+		},
+		nil,
+		{
+			OnDemandMonthlyCost:        decimal.NewFromInt(30),
+			CoveredOnDemandMonthlyCost: decimal.NewFromInt(90),
+			CoveredMonthlyCost:         decimal.NewFromInt(60),
+			Commitments:                []Commitment{commitment("sp-1", 100, 60), commitment("ri-1", 50, 10), commitment("sp-2", 20, 5)},
+		},
+	})
+
+	assert.Equal(t, "40", merged.OnDemandMonthlyCost.String())
+	assert.Equal(t, "50", merged.MonthlySavings.String())
+	assert.Len(t, merged.Commitments, 3)
+
+	tests := []struct {
+		name        string
+		used        string
+		unused      string
+		utilization string
+	}{
+		{"sp-1", "100", "0", "100"},
+		{"ri-1", "60", "0", "120"},
+		{"sp-2", "5", "15", "25"},
+	}
+
+	for i, tt := range tests {
+		c := merged.Commitments[i]
+		assert.Equal(t, tt.name, c.Name)
+		assert.Equal(t, "savings_plan", c.Type)
+		assert.Equal(t, tt.used, c.MonthlyUsed.String(), tt.name)
+		assert.Equal(t, tt.unused, c.UnusedMonthlyCommitment.String(), tt.name)
+		assert.Equal(t, tt.utilization, c.UtilizationPercent.String(), tt.name)
+	}
+
+	assert.Nil(t, mergeCommitmentSummaries([]*CommitmentSummary{nil}))
+}
@@ -0,0 +1,147 @@
+package output
+
+import (
+	"time"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+// Compare returns the current report with the breakdown of each project
+// compared to the matching project of the past report, so it can be output
+// like a diff. Projects are matched by name, or if both reports have a single
+// project, with each other. Resources are matched by address.
+func Compare(past Root, current Root) Root {
+	compared := current
+	compared.TimeGenerated = time.Now()
+	compared.Rollups = nil
+	compared.PolicyViolations = nil
+	compared.Projects = make([]Project, 0, len(current.Projects))
+
+	matched := make(map[int]bool)
+
+	for _, p := range current.Projects {
+		i := matchingProjectIndex(past.Projects, p, len(past.Projects) == 1 && len(current.Projects) == 1)
+
+		pastBreakdown := emptyBreakdown()
+		if i >= 0 {
+			matched[i] = true
+			if past.Projects[i].Breakdown != nil {
+				pastBreakdown = past.Projects[i].Breakdown
+			}
+		}
+
+		breakdown := p.Breakdown
+		if breakdown == nil {
+			breakdown = emptyBreakdown()
+		}
+
+		p.PastBreakdown = pastBreakdown
+		p.Breakdown = breakdown
+		p.Diff = compareBreakdowns(pastBreakdown, breakdown)
+
+		compared.Projects = append(compared.Projects, p)
+	}
+
+	// Projects that have been removed are compared to an empty breakdown
+	for i, p := range past.Projects {
+		if matched[i] {
+			continue
+		}
+
+		pastBreakdown := p.Breakdown
+		if pastBreakdown == nil {
+			pastBreakdown = emptyBreakdown()
+		}
+
+		p.PastBreakdown = pastBreakdown
+		p.Breakdown = emptyBreakdown()
+		p.Diff = compareBreakdowns(pastBreakdown, p.Breakdown)
+		p.Summary = &Summary{}
+		p.UnsupportedResources = nil
+
+		compared.Projects = append(compared.Projects, p)
+	}
+
+	return compared
+}
+
+func matchingProjectIndex(projects []Project, project Project, matchSingle bool) int {
+	if matchSingle {
+		return 0
+	}
+
+	for i, p := range projects {
+		if p.Name == project.Name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func emptyBreakdown() *Breakdown {
+	return &Breakdown{
+		Resources:        []Resource{},
+		TotalHourlyCost:  decimalPtr(decimal.Zero),
+		TotalMonthlyCost: decimalPtr(decimal.Zero),
+	}
+}
+
+// compareBreakdowns calculates the diff of two breakdowns using the same
+// logic as the diff of a plan's prior and planned state.
+func compareBreakdowns(past *Breakdown, current *Breakdown) *Breakdown {
+	diff := outputBreakdown(schema.CalculateDiff(schemaResources(past.Resources), schemaResources(current.Resources)))
+
+	// The metadata isn't part of the schema resources, so it's copied over
+	for i, r := range diff.Resources {
+		if c := findResourceByName(current.Resources, r.Name); c != nil {
+			diff.Resources[i].Metadata = c.Metadata
+		} else if p := findResourceByName(past.Resources, pastResourceName(r)); p != nil {
+			diff.Resources[i].Metadata = p.Metadata
+		}
+	}
+
+	return diff
+}
+
+func schemaResources(resources []Resource) []*schema.Resource {
+	arr := make([]*schema.Resource, 0, len(resources))
+
+	for _, r := range resources {
+		arr = append(arr, schemaResource(r))
+	}
+
+	return arr
+}
+
+// schemaResource converts the resource back to a schema resource. The prices
+// and quantities in the output already include the unit multiplier.
+func schemaResource(r Resource) *schema.Resource {
+	comps := make([]*schema.CostComponent, 0, len(r.CostComponents))
+	for _, c := range r.CostComponents {
+		comp := &schema.CostComponent{
+			Name:            c.Name,
+			Unit:            c.Unit,
+			UnitMultiplier:  decimal.NewFromInt(1),
+			HourlyQuantity:  c.HourlyQuantity,
+			MonthlyQuantity: c.MonthlyQuantity,
+			HourlyCost:      c.HourlyCost,
+			MonthlyCost:     c.MonthlyCost,
+		}
+		comp.SetPrice(c.Price)
+
+		comps = append(comps, comp)
+	}
+
+	return &schema.Resource{
+		Name:           r.Name,
+		Tags:           r.Tags,
+		SourceLocation: r.SourceLocation,
+		MovedFrom:      r.MovedFrom,
+		HourlyCost:     r.HourlyCost,
+		MonthlyCost:    r.MonthlyCost,
+		CostComponents: comps,
+		SubResources:   schemaResources(r.SubResources),
+	}
+}
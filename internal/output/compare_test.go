@@ -0,0 +1,69 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	past := Root{
+		Projects: []Project{
+			{Name: "org/prod", Breakdown: &Breakdown{Resources: []Resource{compareTestResource("aws_instance.a", 100), compareTestResource("aws_instance.b", 50)}}},
+			{Name: "org/removed", Breakdown: &Breakdown{Resources: []Resource{compareTestResource("aws_instance.c", 10)}}},
+		},
+	}
+	current := Root{
+		Projects: []Project{
+			{Name: "org/prod", Breakdown: &Breakdown{Resources: []Resource{compareTestResource("aws_instance.a", 200), compareTestResource("aws_instance.b", 50)}}},
+			{Name: "org/new", Breakdown: &Breakdown{Resources: []Resource{compareTestResource("aws_instance.d", 20)}}},
+		},
+	}
+
+	compared := Compare(past, current)
+
+	assert.Len(t, compared.Projects, 3)
+
+	prod := compared.Projects[0]
+	assert.Equal(t, "org/prod", prod.Name)
+	assert.Equal(t, past.Projects[0].Breakdown, prod.PastBreakdown)
+	assert.Len(t, prod.Diff.Resources, 1)
+	assert.Equal(t, "aws_instance.a", prod.Diff.Resources[0].Name)
+	assert.Equal(t, "100", prod.Diff.Resources[0].MonthlyCost.String())
+	assert.Equal(t, "100", prod.Diff.TotalMonthlyCost.String())
+	assert.Equal(t, "us-east-1", prod.Diff.Resources[0].Metadata["region"])
+
+	added := compared.Projects[1]
+	assert.Equal(t, "org/new", added.Name)
+	assert.Empty(t, added.PastBreakdown.Resources)
+	assert.Equal(t, "20", added.Diff.TotalMonthlyCost.String())
+
+	removed := compared.Projects[2]
+	assert.Equal(t, "org/removed", removed.Name)
+	assert.Empty(t, removed.Breakdown.Resources)
+	assert.Equal(t, "-10", removed.Diff.TotalMonthlyCost.String())
+}
+
+func TestCompareSingleProjects(t *testing.T) {
+	past := Root{Projects: []Project{{Name: "us-east-1", Breakdown: &Breakdown{Resources: []Resource{compareTestResource("aws_instance.a", 100)}}}}}
+	current := Root{Projects: []Project{{Name: "eu-west-1", Breakdown: &Breakdown{Resources: []Resource{compareTestResource("aws_instance.a", 110)}}}}}
+
+	compared := Compare(past, current)
+
+	assert.Len(t, compared.Projects, 1)
+	assert.Equal(t, "eu-west-1", compared.Projects[0].Name)
+	assert.Equal(t, "10", compared.Projects[0].Diff.TotalMonthlyCost.String())
+}
+
+func compareTestResource(name string, cost int64) Resource {
+	return Resource{
+		Name:        name,
+		Metadata:    map[string]string{"region": "us-east-1"},
+		HourlyCost:  decimalPtr(decimal.NewFromInt(cost).Div(decimal.NewFromInt(730))),
+		MonthlyCost: decimalPtr(decimal.NewFromInt(cost)),
+		CostComponents: []CostComponent{
+			{Name: "Instance usage", Unit: "hours", Price: decimal.NewFromInt(cost).Div(decimal.NewFromInt(730)), MonthlyQuantity: decimalPtr(decimal.NewFromInt(730)), MonthlyCost: decimalPtr(decimal.NewFromInt(cost))},
+		},
+	}
+}
@@ -0,0 +1,329 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// flatColumn is a column of the flat export. Numeric columns are written as
+// numbers in spreadsheets so they can be summed and charted.
+type flatColumn struct {
+	Name    string
+	Numeric bool
+}
+
+var flatColumns = []flatColumn{
+	{Name: "project"},
+	{Name: "resource"},
+	{Name: "sub_resource"},
+	{Name: "resource_type"},
+	{Name: "tags"},
+	{Name: "cost_component"},
+	{Name: "unit"},
+	{Name: "currency"},
+	{Name: "price", Numeric: true},
+	{Name: "hourly_quantity", Numeric: true},
+	{Name: "monthly_quantity", Numeric: true},
+	{Name: "hourly_cost", Numeric: true},
+	{Name: "monthly_cost", Numeric: true},
+}
+
+var flatDiffColumns = []flatColumn{
+	{Name: "past_monthly_quantity", Numeric: true},
+	{Name: "past_monthly_cost", Numeric: true},
+	{Name: "diff_monthly_cost", Numeric: true},
+}
+
+var flatViolationColumns = []flatColumn{
+	{Name: "budget_rule"},
+	{Name: "project"},
+	{Name: "check"},
+	{Name: "limit", Numeric: true},
+	{Name: "actual", Numeric: true},
+	{Name: "message"},
+	{Name: "resources"},
+}
+
+// flatViolationRowColumns are the columns added to the CSV for the budget
+// policy violations, which share the project column with the cost components.
+var flatViolationRowColumns = []flatColumn{
+	{Name: "budget_rule"},
+	{Name: "check"},
+	{Name: "limit", Numeric: true},
+	{Name: "actual", Numeric: true},
+	{Name: "message"},
+	{Name: "resources"},
+}
+
+var addressIndexRegex = regexp.MustCompile(`\[[^\]]*\]`)
+
+// ToCSV renders the output with one row per cost component so it can be
+// loaded into a spreadsheet. The past and diff columns are only included
+// when a project has a past breakdown. If there are budget policy
+// violations, a row_type column is added so they can be written as rows of
+// the same table, along with the violation columns.
+func ToCSV(out Root, opts Options) ([]byte, error) {
+	columns, rows := flatRows(out, opts)
+
+	if len(out.PolicyViolations) > 0 {
+		columns, rows = withViolationRows(columns, rows, out.PolicyViolations)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(flatHeader(columns)); err != nil {
+		return nil, err
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// withViolationRows returns the columns and rows with a row_type column
+// before them and the violation columns after them, and a row for each
+// violation. The violations share the project column with the cost
+// components.
+func withViolationRows(columns []flatColumn, rows [][]string, violations []PolicyViolation) ([]flatColumn, [][]string) {
+	allColumns := append([]flatColumn{{Name: "row_type"}}, columns...)
+	allColumns = append(allColumns, flatViolationRowColumns...)
+
+	allRows := make([][]string, 0, len(rows)+len(violations))
+
+	for _, row := range rows {
+		r := append([]string{"cost_component"}, row...)
+		allRows = append(allRows, append(r, make([]string, len(flatViolationRowColumns))...))
+	}
+
+	for _, v := range violations {
+		r := []string{"policy_violation", v.Project}
+		r = append(r, make([]string, len(columns)-1)...)
+		allRows = append(allRows, append(r,
+			v.Rule,
+			v.Check,
+			v.Limit.String(),
+			v.Actual.String(),
+			v.Message,
+			strings.Join(v.Resources, ";"),
+		))
+	}
+
+	return allColumns, allRows
+}
+
+func flatHeader(columns []flatColumn) []string {
+	header := make([]string, 0, len(columns))
+	for _, c := range columns {
+		header = append(header, c.Name)
+	}
+
+	return header
+}
+
+// flatViolationRows returns a row for each budget policy violation. The
+// resources that count towards the budget are separated by semicolons.
+func flatViolationRows(violations []PolicyViolation) [][]string {
+	rows := make([][]string, 0, len(violations))
+
+	for _, v := range violations {
+		rows = append(rows, []string{
+			v.Rule,
+			v.Project,
+			v.Check,
+			v.Limit.String(),
+			v.Actual.String(),
+			v.Message,
+			strings.Join(v.Resources, ";"),
+		})
+	}
+
+	return rows
+}
+
+// flatRows returns the columns and a row for each cost component of the
+// resources and their sub-resources. Resources that were removed are
+// included so their cost shows up in the diff columns.
+func flatRows(out Root, opts Options) ([]flatColumn, [][]string) {
+	hasPast := false
+	for _, p := range out.Projects {
+		if p.PastBreakdown != nil {
+			hasPast = true
+			break
+		}
+	}
+
+	columns := append([]flatColumn{}, flatColumns...)
+	if hasPast {
+		columns = append(columns, flatDiffColumns...)
+	}
+
+	rows := make([][]string, 0)
+
+	for _, p := range out.Projects {
+		var pastResources, newResources []Resource
+		if p.PastBreakdown != nil {
+			pastResources = p.PastBreakdown.Resources
+		}
+		if p.Breakdown != nil {
+			newResources = p.Breakdown.Resources
+		}
+
+		for _, name := range mergeNames(resourceNames(pastResources), resourceNames(newResources)) {
+			f := flattener{
+				currency:     out.Currency,
+				project:      p.Label(opts.DashboardEnabled),
+				resource:     name,
+				resourceType: ResourceType(name),
+				hasPast:      hasPast,
+			}
+
+			rows = append(rows, f.rows("", findResourceByName(pastResources, name), findResourceByName(newResources, name))...)
+		}
+	}
+
+	return columns, rows
+}
+
+type flattener struct {
+	currency     string
+	project      string
+	resource     string
+	resourceType string
+	tags         string
+	hasPast      bool
+}
+
+func (f flattener) rows(path string, pastResource *Resource, newResource *Resource) [][]string {
+	rows := make([][]string, 0)
+
+	var pastComponents, newComponents []CostComponent
+	var pastSubResources, newSubResources []Resource
+
+	if pastResource != nil {
+		pastComponents = pastResource.CostComponents
+		pastSubResources = pastResource.SubResources
+		if f.tags == "" {
+			f.tags = formatTags(pastResource.Tags)
+		}
+	}
+	if newResource != nil {
+		newComponents = newResource.CostComponents
+		newSubResources = newResource.SubResources
+		if t := formatTags(newResource.Tags); t != "" {
+			f.tags = t
+		}
+	}
+
+	for _, name := range mergeNames(costComponentNames(pastComponents), costComponentNames(newComponents)) {
+		pastComponent := findCostComponentByName(pastComponents, name)
+		newComponent := findCostComponentByName(newComponents, name)
+
+		row := []string{f.project, f.resource, path, f.resourceType, f.tags, name}
+
+		if newComponent != nil {
+			row = append(row,
+				newComponent.Unit,
+				f.currency,
+				newComponent.Price.String(),
+				formatDecimal(newComponent.HourlyQuantity),
+				formatDecimal(newComponent.MonthlyQuantity),
+				formatDecimal(newComponent.HourlyCost),
+				formatDecimal(newComponent.MonthlyCost),
+			)
+		} else {
+			row = append(row, pastComponent.Unit, f.currency, "", "", "", "", "")
+		}
+
+		if f.hasPast {
+			var pastQuantity, pastCost, newCost *decimal.Decimal
+			if pastComponent != nil {
+				pastQuantity = pastComponent.MonthlyQuantity
+				pastCost = pastComponent.MonthlyCost
+			}
+			if newComponent != nil {
+				newCost = newComponent.MonthlyCost
+			}
+
+			row = append(row,
+				formatDecimal(pastQuantity),
+				formatDecimal(pastCost),
+				formatDecimal(diffDecimalPtrs(pastCost, newCost)),
+			)
+		}
+
+		rows = append(rows, row)
+	}
+
+	for _, name := range mergeNames(resourceNames(pastSubResources), resourceNames(newSubResources)) {
+		subPath := name
+		if path != "" {
+			subPath = path + "." + name
+		}
+
+		rows = append(rows, f.rows(subPath, findResourceByName(pastSubResources, name), findResourceByName(newSubResources, name))...)
+	}
+
+	return rows
+}
+
+// ResourceType returns the type from a resource address, e.g. aws_instance
+// for module.app.aws_instance.web["a.b"].
+func ResourceType(address string) string {
+	parts := strings.Split(addressIndexRegex.ReplaceAllString(address, ""), ".")
+	if len(parts) < 2 {
+		return ""
+	}
+
+	return parts[len(parts)-2]
+}
+
+// formatTags formats the tags as key=value pairs sorted by key.
+func formatTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, tags[k]))
+	}
+
+	return strings.Join(pairs, ";")
+}
+
+func formatDecimal(d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+
+	return d.String()
+}
+
+// diffDecimalPtrs returns the difference between the costs, treating a
+// missing cost as zero unless both are missing.
+func diffDecimalPtrs(oldValue *decimal.Decimal, newValue *decimal.Decimal) *decimal.Decimal {
+	if oldValue == nil && newValue == nil {
+		return nil
+	}
+
+	diff := decimal.Zero
+	if newValue != nil {
+		diff = *newValue
+	}
+	if oldValue != nil {
+		diff = diff.Sub(*oldValue)
+	}
+
+	return &diff
+}
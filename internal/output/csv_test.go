@@ -0,0 +1,158 @@
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFlatRoot() Root {
+	component := func(name string, monthlyCost int64) CostComponent {
+		return CostComponent{
+			Name:            name,
+			Unit:            "hours",
+			MonthlyQuantity: decimalPtr(decimal.NewFromInt(730)),
+			Price:           decimal.RequireFromString("0.1"),
+			MonthlyCost:     decimalPtr(decimal.NewFromInt(monthlyCost)),
+		}
+	}
+
+	return Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name: "proj",
+				PastBreakdown: &Breakdown{Resources: []Resource{
+					{Name: "module.app.aws_instance.web[\"a.b\"]", CostComponents: []CostComponent{component("Instance usage", 50)}},
+					{Name: "aws_eip.old", CostComponents: []CostComponent{component("IP address", 4)}},
+				}},
+				Breakdown: &Breakdown{Resources: []Resource{
+					{
+						Name:           "module.app.aws_instance.web[\"a.b\"]",
+						Tags:           map[string]string{"team": "a", "env": "prod"},
+						CostComponents: []CostComponent{component("Instance usage", 73)},
+						SubResources: []Resource{
+							{Name: "root_block_device", CostComponents: []CostComponent{component("Storage", 5)}},
+						},
+					},
+				}},
+			},
+		},
+	}
+}
+
+func TestToCSV(t *testing.T) {
+	b, err := ToCSV(testFlatRoot(), Options{})
+	require.NoError(t, err)
+
+	expected := `project,resource,sub_resource,resource_type,tags,cost_component,unit,currency,price,hourly_quantity,monthly_quantity,hourly_cost,monthly_cost,past_monthly_quantity,past_monthly_cost,diff_monthly_cost
+proj,"module.app.aws_instance.web[""a.b""]",,aws_instance,env=prod;team=a,Instance usage,hours,USD,0.1,,730,,73,730,50,23
+proj,"module.app.aws_instance.web[""a.b""]",root_block_device,aws_instance,env=prod;team=a,Storage,hours,USD,0.1,,730,,5,,,5
+proj,aws_eip.old,,aws_eip,,IP address,hours,USD,,,,,,730,4,-4
+`
+	assert.Equal(t, expected, string(b))
+}
+
+func TestToCSVPolicyViolations(t *testing.T) {
+	root := testFlatRoot()
+	root.PolicyViolations = []PolicyViolation{
+		{
+			Rule:      "budget[0]",
+			Project:   "proj",
+			Check:     "max_monthly_cost",
+			Limit:     decimal.NewFromInt(50),
+			Actual:    decimal.NewFromInt(78),
+			Message:   "Monthly cost of proj is $78.00, over the budget of $50.00",
+			Resources: []string{"aws_instance.web", "aws_eip.ip"},
+		},
+	}
+
+	b, err := ToCSV(root, Options{})
+	require.NoError(t, err)
+
+	expected := `row_type,project,resource,sub_resource,resource_type,tags,cost_component,unit,currency,price,hourly_quantity,monthly_quantity,hourly_cost,monthly_cost,past_monthly_quantity,past_monthly_cost,diff_monthly_cost,budget_rule,check,limit,actual,message,resources
+cost_component,proj,"module.app.aws_instance.web[""a.b""]",,aws_instance,env=prod;team=a,Instance usage,hours,USD,0.1,,730,,73,730,50,23,,,,,,
+cost_component,proj,"module.app.aws_instance.web[""a.b""]",root_block_device,aws_instance,env=prod;team=a,Storage,hours,USD,0.1,,730,,5,,,5,,,,,,
+cost_component,proj,aws_eip.old,,aws_eip,,IP address,hours,USD,,,,,,730,4,-4,,,,,,
+policy_violation,proj,,,,,,,,,,,,,,,,budget[0],max_monthly_cost,50,78,"Monthly cost of proj is $78.00, over the budget of $50.00",aws_instance.web;aws_eip.ip
+`
+	assert.Equal(t, expected, string(b))
+}
+
+func TestToCSVWithoutPastBreakdown(t *testing.T) {
+	root := testFlatRoot()
+	root.Projects[0].PastBreakdown = nil
+
+	b, err := ToCSV(root, Options{})
+	require.NoError(t, err)
+
+	header := strings.SplitN(string(b), "\n", 2)[0]
+	assert.True(t, strings.HasSuffix(header, ",monthly_cost"))
+}
+
+func readXLSXFile(t *testing.T, b []byte, name string) []byte {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	require.NoError(t, err)
+
+	for _, f := range zr.File {
+		if f.Name == name {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			defer rc.Close()
+
+			content, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			return content
+		}
+	}
+
+	return nil
+}
+
+func TestToXLSX(t *testing.T) {
+	b, err := ToXLSX(testFlatRoot(), Options{})
+	require.NoError(t, err)
+
+	sheet := readXLSXFile(t, b, "xl/worksheets/sheet1.xml")
+	require.NotNil(t, sheet)
+	assert.Contains(t, string(sheet), `<c r="B2" t="inlineStr"><is><t xml:space="preserve">module.app.aws_instance.web[&#34;a.b&#34;]</t></is></c>`)
+	assert.Contains(t, string(sheet), `<c r="M2"><v>73</v></c>`)
+	assert.Contains(t, string(sheet), `<c r="P4"><v>-4</v></c>`)
+
+	assert.Nil(t, readXLSXFile(t, b, "xl/worksheets/sheet2.xml"))
+	assert.NotContains(t, string(readXLSXFile(t, b, "xl/workbook.xml")), "Policy violations")
+}
+
+func TestToXLSXPolicyViolations(t *testing.T) {
+	root := testFlatRoot()
+	root.PolicyViolations = []PolicyViolation{
+		{Rule: "budget[0]", Project: "proj", Check: "max_monthly_cost", Limit: decimal.NewFromInt(50), Actual: decimal.NewFromInt(78), Message: "Over budget"},
+	}
+
+	b, err := ToXLSX(root, Options{})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(readXLSXFile(t, b, "xl/workbook.xml")), `<sheet name="Policy violations" sheetId="2" r:id="rId2"/>`)
+	assert.Contains(t, string(readXLSXFile(t, b, "xl/_rels/workbook.xml.rels")), `Target="worksheets/sheet2.xml"`)
+	assert.Contains(t, string(readXLSXFile(t, b, "[Content_Types].xml")), `PartName="/xl/worksheets/sheet2.xml"`)
+
+	sheet := readXLSXFile(t, b, "xl/worksheets/sheet2.xml")
+	require.NotNil(t, sheet)
+	assert.Contains(t, string(sheet), `<c r="A2" t="inlineStr"><is><t xml:space="preserve">budget[0]</t></is></c>`)
+	assert.Contains(t, string(sheet), `<c r="D2"><v>50</v></c>`)
+	assert.Contains(t, string(sheet), `<c r="E2"><v>78</v></c>`)
+}
+
+func TestXLSXColumnName(t *testing.T) {
+	assert.Equal(t, "A", xlsxColumnName(0))
+	assert.Equal(t, "Z", xlsxColumnName(25))
+	assert.Equal(t, "AA", xlsxColumnName(26))
+	assert.Equal(t, "AZ", xlsxColumnName(51))
+	assert.Equal(t, "BA", xlsxColumnName(52))
+}
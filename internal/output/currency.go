@@ -0,0 +1,327 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v2"
+)
+
+// ExchangeRate records the rate that was used to convert a report from one
+// currency to another.
+type ExchangeRate struct {
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Rate      decimal.Decimal `json:"rate"`
+	Timestamp time.Time       `json:"timestamp"`
+	Source    string          `json:"source,omitempty"`
+}
+
+// ExchangeRates is a set of exchange rates from a base currency, e.g.
+// {"base": "USD", "rates": {"EUR": 0.86}} means 1 USD is 0.86 EUR. Rates
+// between two other currencies are calculated using the base currency.
+type ExchangeRates struct {
+	Base      string                     `json:"base" yaml:"base"`
+	Timestamp time.Time                  `json:"timestamp" yaml:"timestamp"`
+	Source    string                     `json:"source,omitempty" yaml:"source,omitempty"`
+	Rates     map[string]decimal.Decimal `json:"rates" yaml:"-"`
+}
+
+// RateSource provides the exchange rates used to convert reports.
+type RateSource interface {
+	ExchangeRates() (*ExchangeRates, error)
+}
+
+// FileRateSource reads the exchange rates from a JSON or YAML file.
+type FileRateSource struct {
+	Path string
+}
+
+// HTTPRateSource fetches the exchange rates as JSON from a URL, such as a
+// rates provider running locally.
+type HTTPRateSource struct {
+	URL     string
+	Timeout time.Duration
+}
+
+var defaultRateSourceTimeout = 30 * time.Second
+
+// NewRateSource returns a source for the exchange rates. Sources starting
+// with http:// or https:// are fetched, anything else is read as a file.
+func NewRateSource(source string) RateSource {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return &HTTPRateSource{URL: source, Timeout: defaultRateSourceTimeout}
+	}
+
+	return &FileRateSource{Path: source}
+}
+
+func (s *FileRateSource) ExchangeRates() (*ExchangeRates, error) {
+	data, err := ioutil.ReadFile(s.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading exchange rates file")
+	}
+
+	rates, err := parseExchangeRates(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing exchange rates file")
+	}
+
+	if rates.Source == "" {
+		rates.Source = s.Path
+	}
+
+	return rates, nil
+}
+
+func (s *HTTPRateSource) ExchangeRates() (*ExchangeRates, error) {
+	client := &http.Client{Timeout: s.Timeout}
+
+	resp, err := client.Get(s.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching exchange rates")
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching exchange rates")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Error fetching exchange rates: %s returned %s", s.URL, resp.Status)
+	}
+
+	rates, err := parseExchangeRates(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing exchange rates")
+	}
+
+	if rates.Source == "" {
+		rates.Source = s.URL
+	}
+
+	return rates, nil
+}
+
+// parseExchangeRates parses the exchange rates from JSON or YAML. The rates
+// are parsed as strings so they keep their exact decimal value.
+func parseExchangeRates(data []byte) (*ExchangeRates, error) {
+	var doc struct {
+		ExchangeRates `yaml:",inline"`
+		Rates         map[string]string `yaml:"rates"`
+	}
+
+	// JSON documents use the JSON decoder so the errors refer to JSON syntax
+	var jsonDoc struct {
+		ExchangeRates
+		Rates map[string]json.Number `json:"rates"`
+	}
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		if err := json.Unmarshal(data, &jsonDoc); err != nil {
+			return nil, err
+		}
+
+		doc.ExchangeRates = jsonDoc.ExchangeRates
+		doc.Rates = make(map[string]string, len(jsonDoc.Rates))
+		for k, v := range jsonDoc.Rates {
+			doc.Rates[k] = v.String()
+		}
+	} else if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.New(strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+
+	rates := doc.ExchangeRates
+	rates.Base = strings.ToUpper(rates.Base)
+	if rates.Base == "" {
+		return nil, errors.New("missing base currency")
+	}
+
+	rates.Rates = make(map[string]decimal.Decimal, len(doc.Rates))
+	for k, v := range doc.Rates {
+		d, err := decimal.NewFromString(v)
+		if err != nil || !d.IsPositive() {
+			return nil, fmt.Errorf("invalid rate %q for %s", v, k)
+		}
+		rates.Rates[strings.ToUpper(k)] = d
+	}
+
+	return &rates, nil
+}
+
+// Rate returns the rate to convert an amount in one currency to another.
+func (e *ExchangeRates) Rate(from string, to string) (decimal.Decimal, error) {
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+
+	if from == to {
+		return decimal.NewFromInt(1), nil
+	}
+
+	fromRate, ok := e.baseRate(from)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("No exchange rate found for %s", from)
+	}
+
+	toRate, ok := e.baseRate(to)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("No exchange rate found for %s", to)
+	}
+
+	if fromRate.Equal(decimal.NewFromInt(1)) {
+		return toRate, nil
+	}
+
+	return toRate.Div(fromRate), nil
+}
+
+func (e *ExchangeRates) baseRate(currency string) (decimal.Decimal, bool) {
+	if currency == e.Base {
+		return decimal.NewFromInt(1), true
+	}
+
+	r, ok := e.Rates[currency]
+	return r, ok
+}
+
+// ConvertCurrency converts all the prices and costs of the report to another
+// currency and records the rate that was used. Reports without a currency are
+// assumed to be in USD.
+func ConvertCurrency(r *Root, to string, rates *ExchangeRates) error {
+	from := r.Currency
+	if from == "" {
+		from = "USD"
+	}
+	to = strings.ToUpper(to)
+
+	if strings.EqualFold(from, to) {
+		return nil
+	}
+
+	rate, err := rates.Rate(from, to)
+	if err != nil {
+		return err
+	}
+
+	c := converter{rate: rate}
+
+	for i := range r.Projects {
+		p := &r.Projects[i]
+		c.breakdown(p.PastBreakdown)
+		c.breakdown(p.Breakdown)
+		c.breakdown(p.Diff)
+	}
+
+	r.TotalHourlyCost = c.ptr(r.TotalHourlyCost)
+	r.TotalMonthlyCost = c.ptr(r.TotalMonthlyCost)
+	c.commitments(r.Commitments)
+
+	r.Currency = to
+	r.ExchangeRates = append(r.ExchangeRates, ExchangeRate{
+		From:      strings.ToUpper(from),
+		To:        to,
+		Rate:      rate,
+		Timestamp: rates.Timestamp,
+		Source:    rates.Source,
+	})
+
+	return nil
+}
+
+type converter struct {
+	rate decimal.Decimal
+}
+
+func (c converter) value(d decimal.Decimal) decimal.Decimal {
+	return d.Mul(c.rate)
+}
+
+func (c converter) ptr(d *decimal.Decimal) *decimal.Decimal {
+	if d == nil {
+		return nil
+	}
+	return decimalPtr(c.value(*d))
+}
+
+func (c converter) breakdown(b *Breakdown) {
+	if b == nil {
+		return
+	}
+
+	c.resources(b.Resources)
+	b.TotalHourlyCost = c.ptr(b.TotalHourlyCost)
+	b.TotalMonthlyCost = c.ptr(b.TotalMonthlyCost)
+}
+
+func (c converter) resources(resources []Resource) {
+	for i := range resources {
+		r := &resources[i]
+		r.HourlyCost = c.ptr(r.HourlyCost)
+		r.MonthlyCost = c.ptr(r.MonthlyCost)
+
+		for j := range r.CostComponents {
+			cc := &r.CostComponents[j]
+			cc.Price = c.value(cc.Price)
+			cc.ListPrice = c.ptr(cc.ListPrice)
+			cc.HourlyCost = c.ptr(cc.HourlyCost)
+			cc.MonthlyCost = c.ptr(cc.MonthlyCost)
+			cc.MonthlyCredit = c.ptr(cc.MonthlyCredit)
+
+			for k := range cc.Coverage {
+				cov := &cc.Coverage[k]
+				cov.MonthlyOnDemandCost = c.value(cov.MonthlyOnDemandCost)
+				cov.MonthlyCost = c.value(cov.MonthlyCost)
+			}
+		}
+
+		c.resources(r.SubResources)
+	}
+}
+
+func (c converter) commitments(s *CommitmentSummary) {
+	if s == nil {
+		return
+	}
+
+	s.OnDemandMonthlyCost = c.value(s.OnDemandMonthlyCost)
+	s.CoveredOnDemandMonthlyCost = c.value(s.CoveredOnDemandMonthlyCost)
+	s.CoveredMonthlyCost = c.value(s.CoveredMonthlyCost)
+
+	for i := range s.Commitments {
+		cm := &s.Commitments[i]
+		cm.MonthlyCommitment = c.value(cm.MonthlyCommitment)
+		cm.MonthlyUsed = c.value(cm.MonthlyUsed)
+		cm.UnusedMonthlyCommitment = c.value(cm.UnusedMonthlyCommitment)
+	}
+
+	s.calculateTotals()
+}
+
+func mergeExchangeRates(inputs []ReportInput) []ExchangeRate {
+	var merged []ExchangeRate
+
+	for _, input := range inputs {
+		for _, e := range input.Root.ExchangeRates {
+			found := false
+			for _, m := range merged {
+				if m.From == e.From && m.To == e.To && m.Rate.Equal(e.Rate) && m.Timestamp.Equal(e.Timestamp) && m.Source == e.Source {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				merged = append(merged, e)
+			}
+		}
+	}
+
+	return merged
+}
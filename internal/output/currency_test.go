@@ -0,0 +1,173 @@
+package output
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testExchangeRates() *ExchangeRates {
+	return &ExchangeRates{
+		Base:      "USD",
+		Timestamp: time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+		Source:    "test",
+		Rates: map[string]decimal.Decimal{
+			"EUR": decimal.RequireFromString("0.8"),
+			"GBP": decimal.RequireFromString("0.7"),
+		},
+	}
+}
+
+func TestExchangeRatesRate(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		rate string
+		err  string
+	}{
+		{"USD", "USD", "1", ""},
+		{"USD", "EUR", "0.8", ""},
+		{"eur", "USD", "1.25", ""},
+		{"EUR", "GBP", "0.875", ""},
+		{"USD", "JPY", "0", "No exchange rate found for JPY"},
+	}
+
+	for _, tc := range tests {
+		rate, err := testExchangeRates().Rate(tc.from, tc.to)
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err)
+			continue
+		}
+
+		require.NoError(t, err)
+		assert.Equal(t, tc.rate, rate.String(), "%s to %s", tc.from, tc.to)
+	}
+}
+
+func TestParseExchangeRates(t *testing.T) {
+	tests := map[string]struct {
+		data  string
+		rates map[string]string
+		err   string
+	}{
+		"json": {
+			data:  `{"base": "usd", "timestamp": "2021-10-01T00:00:00Z", "rates": {"EUR": 0.86, "gbp": "0.74"}}`,
+			rates: map[string]string{"EUR": "0.86", "GBP": "0.74"},
+		},
+		"yaml": {
+			data:  "base: USD\ntimestamp: 2021-10-01T00:00:00Z\nrates:\n  EUR: 0.86\n",
+			rates: map[string]string{"EUR": "0.86"},
+		},
+		"missing base": {
+			data: `{"rates": {"EUR": 0.86}}`,
+			err:  "missing base currency",
+		},
+		"negative rate": {
+			data: `{"base": "USD", "rates": {"EUR": -1}}`,
+			err:  `invalid rate "-1" for EUR`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rates, err := parseExchangeRates([]byte(tc.data))
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "USD", rates.Base)
+			assert.Equal(t, "2021-10-01T00:00:00Z", rates.Timestamp.Format(time.RFC3339))
+
+			actual := make(map[string]string)
+			for k, v := range rates.Rates {
+				actual[k] = v.String()
+			}
+			assert.Equal(t, tc.rates, actual)
+		})
+	}
+}
+
+func TestHTTPRateSource(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"base": "USD", "rates": {"EUR": 0.86}}`))
+	}))
+	defer ts.Close()
+
+	rates, err := NewRateSource(ts.URL).ExchangeRates()
+	require.NoError(t, err)
+	assert.Equal(t, ts.URL, rates.Source)
+	assert.Equal(t, "0.86", rates.Rates["EUR"].String())
+}
+
+func TestConvertCurrency(t *testing.T) {
+	resource := func(monthlyCost int64) Resource {
+		return Resource{
+			Name:        "aws_instance.web",
+			HourlyCost:  decimalPtr(decimal.NewFromInt(1)),
+			MonthlyCost: decimalPtr(decimal.NewFromInt(monthlyCost)),
+			CostComponents: []CostComponent{
+				{
+					Name:        "Instance usage",
+					Price:       decimal.NewFromInt(1),
+					ListPrice:   decimalPtr(decimal.NewFromInt(2)),
+					HourlyCost:  decimalPtr(decimal.NewFromInt(1)),
+					MonthlyCost: decimalPtr(decimal.NewFromInt(monthlyCost)),
+				},
+			},
+		}
+	}
+
+	root := Root{
+		Projects: []Project{
+			{
+				PastBreakdown: &Breakdown{Resources: []Resource{resource(100)}, TotalMonthlyCost: decimalPtr(decimal.NewFromInt(100))},
+				Breakdown:     &Breakdown{Resources: []Resource{resource(150)}, TotalMonthlyCost: decimalPtr(decimal.NewFromInt(150))},
+				Diff:          &Breakdown{Resources: []Resource{resource(50)}, TotalMonthlyCost: decimalPtr(decimal.NewFromInt(50))},
+			},
+		},
+		TotalMonthlyCost: decimalPtr(decimal.NewFromInt(150)),
+		Commitments: &CommitmentSummary{
+			OnDemandMonthlyCost:        decimal.NewFromInt(50),
+			CoveredOnDemandMonthlyCost: decimal.NewFromInt(100),
+			CoveredMonthlyCost:         decimal.NewFromInt(60),
+		},
+	}
+
+	err := ConvertCurrency(&root, "EUR", testExchangeRates())
+	require.NoError(t, err)
+
+	assert.Equal(t, "EUR", root.Currency)
+	assert.Nil(t, root.TotalHourlyCost)
+	assert.Equal(t, "120", root.TotalMonthlyCost.String())
+
+	p := root.Projects[0]
+	assert.Equal(t, "80", p.PastBreakdown.TotalMonthlyCost.String())
+	assert.Equal(t, "120", p.Breakdown.TotalMonthlyCost.String())
+	assert.Equal(t, "40", p.Diff.TotalMonthlyCost.String())
+
+	c := p.Breakdown.Resources[0].CostComponents[0]
+	assert.Equal(t, "0.8", c.Price.String())
+	assert.Equal(t, "1.6", c.ListPrice.String())
+	assert.Equal(t, "0.8", c.HourlyCost.String())
+	assert.Equal(t, "120", c.MonthlyCost.String())
+
+	assert.Equal(t, "32", root.Commitments.MonthlySavings.String())
+	assert.Equal(t, "66.67", root.Commitments.CoveragePercent.String())
+
+	assert.Equal(t, []ExchangeRate{
+		{
+			From:      "USD",
+			To:        "EUR",
+			Rate:      decimal.RequireFromString("0.8"),
+			Timestamp: time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+			Source:    "test",
+		},
+	}, root.ExchangeRates)
+}
@@ -38,7 +38,7 @@ func ToDiff(out Root, opts Options) ([]byte, error) {
 		for _, diffResource := range project.Diff.Resources {
 			hasEmptyDiff = false
 
-			oldResource := findResourceByName(project.PastBreakdown.Resources, diffResource.Name)
+			oldResource := findResourceByName(project.PastBreakdown.Resources, pastResourceName(diffResource))
 			newResource := findResourceByName(project.Breakdown.Resources, diffResource.Name)
 
 			if (newResource == nil || resourceHasNilCosts(*newResource)) &&
@@ -79,6 +79,11 @@ func ToDiff(out Root, opts Options) ([]byte, error) {
 		}
 	}
 
+	if len(out.PolicyViolations) > 0 {
+		s += "\n\n----------------------------------\n"
+		s += policyViolationsSummary(out.PolicyViolations)
+	}
+
 	s += "\n\n----------------------------------\n"
 	s += fmt.Sprintf("Key: %s changed, %s added, %s removed",
 		opChar(UPDATED),
@@ -130,6 +135,10 @@ func resourceToDiff(currency string, diffResource Resource, oldResource *Resourc
 		nameLabel = ui.BoldString(nameLabel)
 	}
 
+	if diffResource.MovedFrom != "" {
+		nameLabel += ui.FaintStringf(" (moved from %s)", diffResource.MovedFrom)
+	}
+
 	s += fmt.Sprintf("%s %s\n", opChar(op), nameLabel)
 
 	if isTopLevel {
@@ -214,6 +223,10 @@ func costComponentToDiff(currency string, diffComponent CostComponent, oldCompon
 		)
 	}
 
+	for _, c := range diffComponent.ChangedAttributes {
+		s += ui.FaintStringf("  %s: %s -> %s\n", c.Name, c.Before, c.After)
+	}
+
 	return s
 }
 
@@ -228,6 +241,16 @@ func opChar(op int) string {
 	}
 }
 
+// pastResourceName returns the name of the diff resource in the past
+// breakdown, which is different if the resource has been moved.
+func pastResourceName(diffResource Resource) string {
+	if diffResource.MovedFrom != "" {
+		return diffResource.MovedFrom
+	}
+
+	return diffResource.Name
+}
+
 func findResourceByName(resources []Resource, name string) *Resource {
 	for _, r := range resources {
 		if r.Name == name {
@@ -0,0 +1,58 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToDiffChangedAttributes(t *testing.T) {
+	color.NoColor = true
+
+	instanceType := &schema.AttributeChange{Name: "instance_type", Before: "t3.medium", After: "m5.xlarge"}
+
+	oldComponent := CostComponent{Name: "Instance usage (t3.medium)", Unit: "hours", Price: decimal.NewFromFloat(0.0416), MonthlyCost: decimalPtr(decimal.NewFromFloat(30.37))}
+	newComponent := CostComponent{Name: "Instance usage (m5.xlarge)", Unit: "hours", Price: decimal.NewFromFloat(0.192), MonthlyCost: decimalPtr(decimal.NewFromFloat(140.16))}
+
+	root := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name: "org/prod",
+				PastBreakdown: &Breakdown{
+					TotalMonthlyCost: decimalPtr(decimal.NewFromFloat(30.37)),
+					Resources:        []Resource{{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromFloat(30.37)), CostComponents: []CostComponent{oldComponent}}},
+				},
+				Breakdown: &Breakdown{
+					TotalMonthlyCost: decimalPtr(decimal.NewFromFloat(140.16)),
+					Resources:        []Resource{{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromFloat(140.16)), CostComponents: []CostComponent{newComponent}}},
+				},
+				Diff: &Breakdown{
+					TotalMonthlyCost: decimalPtr(decimal.NewFromFloat(109.79)),
+					Resources: []Resource{
+						{
+							Name:        "aws_instance.web",
+							MonthlyCost: decimalPtr(decimal.NewFromFloat(109.79)),
+							CostComponents: []CostComponent{
+								{Name: "Instance usage (t3.medium)", MonthlyCost: decimalPtr(decimal.NewFromFloat(-30.37)), ChangedAttributes: []*schema.AttributeChange{instanceType}},
+								{Name: "Instance usage (m5.xlarge)", MonthlyCost: decimalPtr(decimal.NewFromFloat(140.16)), ChangedAttributes: []*schema.AttributeChange{instanceType}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := ToDiff(root, Options{})
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "- Instance usage (t3.medium)\n      -$30.37\n      instance_type: t3.medium -> m5.xlarge\n")
+	assert.Contains(t, string(b), "+ Instance usage (m5.xlarge)\n      +$140\n      instance_type: t3.medium -> m5.xlarge\n")
+
+	b, err = ToMarkdown(root, Options{})
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "Changed attributes:\n- `instance_type`: t3.medium → m5.xlarge\n")
+}
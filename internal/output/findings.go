@@ -0,0 +1,223 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// Finding levels, these match the SARIF result levels.
+const (
+	FindingLevelError   = "error"
+	FindingLevelWarning = "warning"
+	FindingLevelNote    = "note"
+)
+
+// FindingRule is a kind of finding that's reported by the SARIF and JUnit
+// formats.
+type FindingRule struct {
+	ID          string
+	Name        string
+	Description string
+	Level       string
+}
+
+var (
+	unsupportedResourceRule = FindingRule{
+		ID:          "IC001",
+		Name:        "UnsupportedResource",
+		Description: "The resource type is not supported yet so its cost is not included.",
+		Level:       FindingLevelWarning,
+	}
+	missingCostRule = FindingRule{
+		ID:          "IC002",
+		Name:        "MissingCost",
+		Description: "The cost component is usage-based and has no usage, so its cost is not included.",
+		Level:       FindingLevelNote,
+	}
+	zeroPriceRule = FindingRule{
+		ID:          "IC003",
+		Name:        "ZeroPrice",
+		Description: "The price lookup for the cost component returned a price of zero.",
+		Level:       FindingLevelWarning,
+	}
+	pricingIssueRule = FindingRule{
+		ID:          "IC004",
+		Name:        "PricingIssue",
+		Description: "The price lookup for the cost component didn't match exactly one price.",
+		Level:       FindingLevelWarning,
+	}
+	budgetViolationRule = FindingRule{
+		ID:          "IC005",
+		Name:        "BudgetViolation",
+		Description: "The costs exceed a budget policy from the config file.",
+		Level:       FindingLevelError,
+	}
+)
+
+// FindingRules are all the rules, in the order of their IDs.
+var FindingRules = []FindingRule{
+	unsupportedResourceRule,
+	missingCostRule,
+	zeroPriceRule,
+	pricingIssueRule,
+	budgetViolationRule,
+}
+
+// Finding is a resource or cost component that needs attention, or a budget
+// violation. Budget violations can be for many resources, so they can have
+// many locations.
+type Finding struct {
+	Rule      FindingRule
+	Project   string
+	Resource  string
+	Message   string
+	Locations []FindingLocation
+}
+
+// FindingLocation is a resource the finding is for, and where it's defined if
+// that's known.
+type FindingLocation struct {
+	Project        string
+	Resource       string
+	SourceLocation *schema.SourceLocation
+}
+
+// BuildFindings returns the findings for the output, sorted by project and
+// resource. Budget violations are last.
+func BuildFindings(out Root, opts Options) []Finding {
+	findings := make([]Finding, 0)
+
+	// Look up the resource locations for the pricing issues and budget
+	// violations, which only have the resource names.
+	locations := make(map[string]*schema.SourceLocation)
+
+	// The pricing issues only have the project names, so they're shown with
+	// the label of the first project with that name.
+	labels := make(map[string]string)
+
+	for _, p := range out.Projects {
+		project := p.Label(opts.DashboardEnabled)
+		if _, ok := labels[p.Name]; !ok {
+			labels[p.Name] = project
+		}
+
+		for _, r := range p.UnsupportedResources {
+			findings = append(findings, Finding{
+				Rule:      unsupportedResourceRule,
+				Project:   project,
+				Resource:  r.Name,
+				Message:   fmt.Sprintf("%s is not supported yet", r.ResourceType),
+				Locations: []FindingLocation{{Project: project, Resource: r.Name, SourceLocation: r.SourceLocation}},
+			})
+		}
+
+		if p.Breakdown == nil {
+			continue
+		}
+
+		for _, r := range p.Breakdown.Resources {
+			locations[p.Name+"\x00"+r.Name] = r.SourceLocation
+			findings = append(findings, costComponentFindings(project, r.Name, r.SourceLocation, nil, r)...)
+		}
+	}
+
+	for _, issue := range out.PricingIssues {
+		name := issue.CostComponent
+		if issue.SubResource != "" {
+			name = issue.SubResource + " > " + name
+		}
+
+		project, ok := labels[issue.ProjectName]
+		if !ok {
+			project = issue.ProjectName
+		}
+
+		findings = append(findings, Finding{
+			Rule:     pricingIssueRule,
+			Project:  project,
+			Resource: issue.ResourceName,
+			Message:  fmt.Sprintf("%s: %s", name, issue.Message),
+			Locations: []FindingLocation{{
+				Project:        project,
+				Resource:       issue.ResourceName,
+				SourceLocation: locations[issue.ProjectName+"\x00"+issue.ResourceName],
+			}},
+		})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Project != findings[j].Project {
+			return findings[i].Project < findings[j].Project
+		}
+		return findings[i].Resource < findings[j].Resource
+	})
+
+	for _, v := range out.PolicyViolations {
+		f := Finding{
+			Rule:    budgetViolationRule,
+			Project: v.Project,
+			Message: fmt.Sprintf("Budget %s: %s", v.Rule, v.Message),
+		}
+
+		for _, name := range v.Resources {
+			for _, p := range out.Projects {
+				if v.Project != "" && p.Label(opts.DashboardEnabled) != v.Project {
+					continue
+				}
+
+				if loc, ok := locations[p.Name+"\x00"+name]; ok {
+					f.Locations = append(f.Locations, FindingLocation{
+						Project:        p.Label(opts.DashboardEnabled),
+						Resource:       name,
+						SourceLocation: loc,
+					})
+				}
+			}
+		}
+
+		findings = append(findings, f)
+	}
+
+	return findings
+}
+
+// costComponentFindings returns the findings for the cost components of the
+// resource and its sub-resources. The sub-resources use the location of the
+// top-level resource since they're defined in the same block.
+func costComponentFindings(project string, resource string, loc *schema.SourceLocation, path []string, r Resource) []Finding {
+	findings := make([]Finding, 0)
+
+	for _, c := range r.CostComponents {
+		name := strings.Join(append(append([]string{}, path...), c.Name), " > ")
+
+		if c.MonthlyCost == nil {
+			findings = append(findings, Finding{
+				Rule:      missingCostRule,
+				Project:   project,
+				Resource:  resource,
+				Message:   fmt.Sprintf("%s has no cost since it depends on usage", name),
+				Locations: []FindingLocation{{Project: project, Resource: resource, SourceLocation: loc}},
+			})
+			continue
+		}
+
+		if c.Price.IsZero() && c.MonthlyQuantity != nil && !c.MonthlyQuantity.IsZero() {
+			findings = append(findings, Finding{
+				Rule:      zeroPriceRule,
+				Project:   project,
+				Resource:  resource,
+				Message:   fmt.Sprintf("%s has a price of zero", name),
+				Locations: []FindingLocation{{Project: project, Resource: resource, SourceLocation: loc}},
+			})
+		}
+	}
+
+	for _, s := range r.SubResources {
+		findings = append(findings, costComponentFindings(project, resource, loc, append(append([]string{}, path...), s.Name), s)...)
+	}
+
+	return findings
+}
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildFindings(t *testing.T) {
+	loc := &schema.SourceLocation{Filename: "main.tf", Line: 3}
+
+	root := Root{
+		Projects: []Project{
+			{
+				Name: "org/prod",
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							Name:           "aws_instance.web",
+							SourceLocation: loc,
+							MonthlyCost:    decimalPtr(decimal.NewFromInt(100)),
+							CostComponents: []CostComponent{
+								{Name: "Instance usage", Price: decimal.NewFromInt(1), MonthlyQuantity: decimalPtr(decimal.NewFromInt(100)), MonthlyCost: decimalPtr(decimal.NewFromInt(100))},
+								{Name: "Data transfer", MonthlyQuantity: decimalPtr(decimal.NewFromInt(10)), MonthlyCost: decimalPtr(decimal.Zero)},
+							},
+							SubResources: []Resource{
+								{
+									Name: "root_block_device",
+									CostComponents: []CostComponent{
+										{Name: "IOPS"},
+									},
+								},
+							},
+						},
+					},
+				},
+				UnsupportedResources: []UnsupportedResource{
+					{Name: "aws_ses_domain_identity.example", ResourceType: "aws_ses_domain_identity"},
+				},
+			},
+		},
+		PolicyViolations: []PolicyViolation{
+			{Rule: "instances", Message: "Total monthly cost is $100.00, over the budget of $50.00", Resources: []string{"aws_instance.web"}},
+		},
+	}
+
+	findings := BuildFindings(root, Options{})
+
+	type result struct {
+		RuleID   string
+		Resource string
+		Message  string
+	}
+
+	actual := make([]result, 0, len(findings))
+	for _, f := range findings {
+		actual = append(actual, result{f.Rule.ID, f.Resource, f.Message})
+	}
+
+	assert.Equal(t, []result{
+		{"IC003", "aws_instance.web", "Data transfer has a price of zero"},
+		{"IC002", "aws_instance.web", "root_block_device > IOPS has no cost since it depends on usage"},
+		{"IC001", "aws_ses_domain_identity.example", "aws_ses_domain_identity is not supported yet"},
+		{"IC005", "", "Budget instances: Total monthly cost is $100.00, over the budget of $50.00"},
+	}, actual)
+
+	assert.Equal(t, []FindingLocation{{Project: "org/prod", Resource: "aws_instance.web", SourceLocation: loc}}, findings[3].Locations)
+}
+
+func TestBuildFindingsPricingIssuesDashboardEnabled(t *testing.T) {
+	loc := &schema.SourceLocation{Filename: "main.tf", Line: 3}
+
+	root := Root{
+		Projects: []Project{
+			{
+				Name:     "org/prod",
+				Metadata: &schema.ProjectMetadata{Path: "prod"},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_instance.web", SourceLocation: loc},
+					},
+				},
+			},
+		},
+		PricingIssues: []PricingIssue{
+			{ProjectName: "org/prod", ResourceName: "aws_instance.web", CostComponent: "Instance usage", Message: "no prices found"},
+		},
+	}
+
+	findings := BuildFindings(root, Options{DashboardEnabled: true})
+
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "IC004", findings[0].Rule.ID)
+	assert.Equal(t, "org/prod (prod)", findings[0].Project)
+	assert.Equal(t, []FindingLocation{{Project: "org/prod (prod)", Resource: "aws_instance.web", SourceLocation: loc}}, findings[0].Locations)
+
+	b, err := ToJUnit(root, Options{DashboardEnabled: true})
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "Instance usage: no prices found")
+}
@@ -110,3 +110,15 @@ func formatTitleWithCurrency(title, currency string) string {
 	}
 	return fmt.Sprintf("%s (%s)", title, currency)
 }
+
+// FormatCost2DP formats the cost in the currency to 2 decimal places, or -
+// if it's nil, for use outside the output formats.
+func FormatCost2DP(currency string, d *decimal.Decimal) string {
+	return formatCost2DP(currency, d)
+}
+
+// FormatQuantity formats the quantity with thousands separators, or - if
+// it's nil.
+func FormatQuantity(q *decimal.Decimal) string {
+	return formatQuantity(q)
+}
@@ -17,24 +17,7 @@ func ToHTML(out Root, opts Options) ([]byte, error) {
 
 	tmpl := template.New("base")
 	tmpl.Funcs(sprig.FuncMap())
-	tmpl.Funcs(template.FuncMap{
-		"safeHTML": func(s interface{}) template.HTML {
-			return template.HTML(fmt.Sprint(s)) // nolint:gosec
-		},
-		"replaceNewLines": func(s string) template.HTML {
-			safe := template.HTMLEscapeString(s)
-			safe = strings.ReplaceAll(safe, "\n", "<br />")
-			return template.HTML(safe) // nolint:gosec
-		},
-		"contains":                contains,
-		"formatCost2DP":           func(d *decimal.Decimal) string { return formatCost2DP(out.Currency, d) },
-		"formatPrice":             func(d decimal.Decimal) string { return formatPrice(out.Currency, d) },
-		"formatTitleWithCurrency": func(title string) string { return formatTitleWithCurrency(title, out.Currency) },
-		"formatQuantity":          formatQuantity,
-		"projectLabel": func(p Project) string {
-			return p.Label(opts.DashboardEnabled)
-		},
-	})
+	tmpl.Funcs(htmlFuncMap(out, opts))
 	tmpl, err := tmpl.Parse(HTMLTemplate)
 	if err != nil {
 		return []byte{}, err
@@ -54,3 +37,25 @@ func ToHTML(out Root, opts Options) ([]byte, error) {
 	bufw.Flush()
 	return buf.Bytes(), nil
 }
+
+// htmlFuncMap returns the template functions used by the HTML templates.
+func htmlFuncMap(out Root, opts Options) template.FuncMap {
+	return template.FuncMap{
+		"safeHTML": func(s interface{}) template.HTML {
+			return template.HTML(fmt.Sprint(s)) // nolint:gosec
+		},
+		"replaceNewLines": func(s string) template.HTML {
+			safe := template.HTMLEscapeString(s)
+			safe = strings.ReplaceAll(safe, "\n", "<br />")
+			return template.HTML(safe) // nolint:gosec
+		},
+		"contains":                contains,
+		"formatCost2DP":           func(d *decimal.Decimal) string { return formatCost2DP(out.Currency, d) },
+		"formatPrice":             func(d decimal.Decimal) string { return formatPrice(out.Currency, d) },
+		"formatTitleWithCurrency": func(title string) string { return formatTitleWithCurrency(title, out.Currency) },
+		"formatQuantity":          formatQuantity,
+		"projectLabel": func(p Project) string {
+			return p.Label(opts.DashboardEnabled)
+		},
+	}
+}
@@ -0,0 +1,356 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"html/template"
+	"sort"
+
+	"github.com/Masterminds/sprig"
+	"github.com/shopspring/decimal"
+)
+
+// maxWaterfallSteps is the number of resource changes shown in the diff
+// waterfall. The rest are combined into one step so the chart stays readable.
+const maxWaterfallSteps = 10
+
+// htmlReportData is the data the HTML report's scripts render the charts and
+// tables from. Costs are numbers rather than decimal strings so they can be
+// used in the charts and sorted.
+type htmlReportData struct {
+	Currency             string                   `json:"currency"`
+	HasPast              bool                     `json:"hasPast"`
+	TotalPastMonthlyCost *float64                 `json:"totalPastMonthlyCost"`
+	TotalMonthlyCost     *float64                 `json:"totalMonthlyCost"`
+	Projects             []htmlReportProject      `json:"projects"`
+	Services             []htmlReportService      `json:"services"`
+	Waterfall            []htmlReportWaterfallBar `json:"waterfall"`
+	Resources            []htmlReportResource     `json:"resources"`
+}
+
+type htmlReportProject struct {
+	Name            string   `json:"name"`
+	PastMonthlyCost *float64 `json:"pastMonthlyCost"`
+	MonthlyCost     *float64 `json:"monthlyCost"`
+}
+
+type htmlReportService struct {
+	Name          string   `json:"name"`
+	ResourceCount int      `json:"resourceCount"`
+	MonthlyCost   *float64 `json:"monthlyCost"`
+}
+
+type htmlReportWaterfallBar struct {
+	Name   string  `json:"name"`
+	Change float64 `json:"change"`
+}
+
+type htmlReportResource struct {
+	Project         string                `json:"project"`
+	Name            string                `json:"name"`
+	ResourceType    string                `json:"resourceType"`
+	Tags            map[string]string     `json:"tags,omitempty"`
+	SourceLocation  string                `json:"sourceLocation,omitempty"`
+	PastMonthlyCost *float64              `json:"pastMonthlyCost"`
+	MonthlyCost     *float64              `json:"monthlyCost"`
+	CostComponents  []htmlReportComponent `json:"costComponents"`
+}
+
+type htmlReportComponent struct {
+	Name            string   `json:"name"`
+	Unit            string   `json:"unit"`
+	MonthlyQuantity *float64 `json:"monthlyQuantity"`
+	Price           float64  `json:"price"`
+	PastMonthlyCost *float64 `json:"pastMonthlyCost"`
+	MonthlyCost     *float64 `json:"monthlyCost"`
+}
+
+// ToHTMLReport renders a self-contained HTML report with charts of the costs
+// by service and project, a waterfall of the changes and tables of the
+// resources that can be sorted, filtered and expanded into their cost
+// components. The scripts and styles are inline so it works offline.
+func ToHTMLReport(out Root, opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	bufw := bufio.NewWriter(&buf)
+
+	tmpl := template.New("html")
+	tmpl.Funcs(sprig.FuncMap())
+	tmpl.Funcs(htmlFuncMap(out, opts))
+
+	// The report uses the favicon from the HTML template
+	tmpl, err := tmpl.Parse(HTMLTemplate)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	tmpl, err = tmpl.New("report").Parse(HTMLReportTemplate)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = tmpl.ExecuteTemplate(bufw, "report", struct {
+		Root                        Root
+		Data                        htmlReportData
+		UnsupportedResourcesMessage string
+	}{out, buildHTMLReportData(out, opts), out.unsupportedResourcesMessage(opts.ShowSkipped)})
+	if err != nil {
+		return []byte{}, err
+	}
+
+	bufw.Flush()
+	return buf.Bytes(), nil
+}
+
+func buildHTMLReportData(out Root, opts Options) htmlReportData {
+	data := htmlReportData{
+		Currency:         currencyOrUSD(out.Currency),
+		TotalMonthlyCost: decimalFloatPtr(out.TotalMonthlyCost),
+		Projects:         make([]htmlReportProject, 0, len(out.Projects)),
+		Services:         make([]htmlReportService, 0),
+		Waterfall:        make([]htmlReportWaterfallBar, 0),
+		Resources:        make([]htmlReportResource, 0),
+	}
+
+	var totalPast *decimal.Decimal
+
+	for _, p := range out.Projects {
+		pastCost, newCost := projectCosts(p)
+		if p.PastBreakdown != nil {
+			data.HasPast = true
+			totalPast = addDecimalPtrs(totalPast, pastCost)
+		}
+
+		name := p.Label(opts.DashboardEnabled)
+
+		data.Projects = append(data.Projects, htmlReportProject{
+			Name:            name,
+			PastMonthlyCost: decimalFloatPtr(pastCost),
+			MonthlyCost:     decimalFloatPtr(newCost),
+		})
+
+		var pastResources, newResources []Resource
+		if p.PastBreakdown != nil {
+			pastResources = p.PastBreakdown.Resources
+		}
+		if p.Breakdown != nil {
+			newResources = p.Breakdown.Resources
+		}
+
+		for _, n := range mergeNames(resourceNames(pastResources), resourceNames(newResources)) {
+			data.Resources = append(data.Resources, htmlReportResourceFor(name, findResourceByName(pastResources, n), findResourceByName(newResources, n)))
+		}
+	}
+
+	data.TotalPastMonthlyCost = decimalFloatPtr(totalPast)
+
+	data.Services = htmlReportServices(out.Projects)
+
+	if data.HasPast {
+		data.Waterfall = htmlReportWaterfall(data.Resources)
+	}
+
+	return data
+}
+
+// htmlReportServices returns the monthly cost of each service, e.g.
+// AmazonEC2, from the cost components of the resources and their
+// sub-resources, and the number of resources using it. Cost components from
+// files that don't have the service are grouped under Other.
+func htmlReportServices(projects []Project) []htmlReportService {
+	costs := make(map[string]*decimal.Decimal)
+	counts := make(map[string]int)
+
+	for _, p := range projects {
+		if p.Breakdown == nil {
+			continue
+		}
+
+		for _, r := range p.Breakdown.Resources {
+			resourceServices := make(map[string]bool)
+			addServiceCosts(r, costs, resourceServices)
+
+			for service := range resourceServices {
+				counts[service]++
+			}
+		}
+	}
+
+	services := make([]htmlReportService, 0, len(counts))
+	for name, count := range counts {
+		services = append(services, htmlReportService{
+			Name:          name,
+			ResourceCount: count,
+			MonthlyCost:   decimalFloatPtr(costs[name]),
+		})
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		a := decimalOrZero(costs[services[i].Name])
+		b := decimalOrZero(costs[services[j].Name])
+		if a.Equal(b) {
+			return services[i].Name < services[j].Name
+		}
+		return a.GreaterThan(b)
+	})
+
+	return services
+}
+
+func addServiceCosts(r Resource, costs map[string]*decimal.Decimal, services map[string]bool) {
+	for _, c := range r.CostComponents {
+		service := c.Service
+		if service == "" {
+			service = "Other"
+		}
+
+		services[service] = true
+		costs[service] = addDecimalPtrs(costs[service], c.MonthlyCost)
+	}
+
+	for _, s := range r.SubResources {
+		addServiceCosts(s, costs, services)
+	}
+}
+
+func htmlReportResourceFor(project string, pastResource *Resource, newResource *Resource) htmlReportResource {
+	r := newResource
+	if r == nil {
+		r = pastResource
+	}
+
+	res := htmlReportResource{
+		Project:        project,
+		Name:           r.Name,
+		ResourceType:   ResourceType(r.Name),
+		Tags:           r.Tags,
+		SourceLocation: r.SourceLocation.String(),
+		CostComponents: htmlReportComponents("", pastResource, newResource),
+	}
+
+	if pastResource != nil {
+		res.PastMonthlyCost = decimalFloatPtr(pastResource.MonthlyCost)
+	}
+	if newResource != nil {
+		res.MonthlyCost = decimalFloatPtr(newResource.MonthlyCost)
+	}
+
+	return res
+}
+
+// htmlReportComponents returns the cost components of the resource and its
+// sub-resources, with the sub-resource names as a prefix.
+func htmlReportComponents(prefix string, pastResource *Resource, newResource *Resource) []htmlReportComponent {
+	components := make([]htmlReportComponent, 0)
+
+	var pastComponents, newComponents []CostComponent
+	var pastSubResources, newSubResources []Resource
+
+	if pastResource != nil {
+		pastComponents = pastResource.CostComponents
+		pastSubResources = pastResource.SubResources
+	}
+	if newResource != nil {
+		newComponents = newResource.CostComponents
+		newSubResources = newResource.SubResources
+	}
+
+	for _, name := range mergeNames(costComponentNames(pastComponents), costComponentNames(newComponents)) {
+		pastComponent := findCostComponentByName(pastComponents, name)
+		newComponent := findCostComponentByName(newComponents, name)
+
+		c := newComponent
+		if c == nil {
+			c = pastComponent
+		}
+
+		price, _ := c.Price.Float64()
+		component := htmlReportComponent{
+			Name:            prefix + name,
+			Unit:            c.Unit,
+			MonthlyQuantity: decimalFloatPtr(c.MonthlyQuantity),
+			Price:           price,
+		}
+
+		if pastComponent != nil {
+			component.PastMonthlyCost = decimalFloatPtr(pastComponent.MonthlyCost)
+		}
+		if newComponent != nil {
+			component.MonthlyCost = decimalFloatPtr(newComponent.MonthlyCost)
+		}
+
+		components = append(components, component)
+	}
+
+	for _, name := range mergeNames(resourceNames(pastSubResources), resourceNames(newSubResources)) {
+		components = append(components, htmlReportComponents(
+			prefix+name+" / ",
+			findResourceByName(pastSubResources, name),
+			findResourceByName(newSubResources, name),
+		)...)
+	}
+
+	return components
+}
+
+// htmlReportWaterfall returns the changes in cost of the resources with the
+// biggest changes first, combining the rest into one bar.
+func htmlReportWaterfall(resources []htmlReportResource) []htmlReportWaterfallBar {
+	bars := make([]htmlReportWaterfallBar, 0)
+
+	for _, r := range resources {
+		change := floatOrZero(r.MonthlyCost) - floatOrZero(r.PastMonthlyCost)
+		if change == 0 {
+			continue
+		}
+
+		bars = append(bars, htmlReportWaterfallBar{Name: r.Name, Change: change})
+	}
+
+	sort.SliceStable(bars, func(i, j int) bool {
+		return absFloat(bars[i].Change) > absFloat(bars[j].Change)
+	})
+
+	if len(bars) <= maxWaterfallSteps {
+		return bars
+	}
+
+	other := htmlReportWaterfallBar{Name: "Other changes"}
+	for _, b := range bars[maxWaterfallSteps:] {
+		other.Change += b.Change
+	}
+
+	return append(bars[:maxWaterfallSteps], other)
+}
+
+func currencyOrUSD(currency string) string {
+	if currency == "" {
+		return "USD"
+	}
+
+	return currency
+}
+
+func decimalFloatPtr(d *decimal.Decimal) *float64 {
+	if d == nil {
+		return nil
+	}
+
+	f, _ := d.Float64()
+	return &f
+}
+
+func floatOrZero(f *float64) float64 {
+	if f == nil {
+		return 0
+	}
+
+	return *f
+}
+
+func absFloat(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+
+	return f
+}
@@ -0,0 +1,509 @@
+package output
+
+// HTMLReportTemplate is the template of the self-contained HTML report. The
+// report data is embedded as JSON and rendered by the inline script, so the
+// file has no external dependencies.
+var HTMLReportTemplate = `<!DOCTYPE html>
+<html>
+  <head>
+    <meta charset="utf-8">
+    <title>Infracost cost report</title>
+    <link id="favicon" rel="shortcut icon" type="image/png" href="data:image/png;base64,{{template "faviconBase64"}}">
+    <style>
+body {
+  margin: 0;
+  padding: 0.5rem 1rem 2rem;
+  font-family: sans-serif;
+  color: #111827;
+}
+
+h1 {
+  font-size: 1.5rem;
+}
+
+h2 {
+  font-size: 1.125rem;
+  margin-top: 2rem;
+}
+
+a {
+  color: #3b82f6;
+}
+
+.metadata {
+  color: #6b7280;
+  font-size: 0.875rem;
+}
+
+.totals {
+  display: flex;
+  gap: 1rem;
+  margin: 1.5rem 0;
+}
+
+.totals .card {
+  border: 1px solid #e5e7eb;
+  border-radius: 0.5rem;
+  padding: 0.75rem 1rem;
+  min-width: 10rem;
+}
+
+.totals .label {
+  color: #6b7280;
+  font-size: 0.875rem;
+}
+
+.totals .value {
+  font-size: 1.5rem;
+  font-weight: bold;
+}
+
+.charts {
+  display: flex;
+  flex-wrap: wrap;
+  gap: 2rem;
+}
+
+.chart {
+  flex: 1 1 28rem;
+  max-width: 48rem;
+}
+
+.chart svg {
+  width: 100%;
+}
+
+.chart text {
+  font-size: 12px;
+  fill: #111827;
+}
+
+.increase {
+  color: #dc2626;
+  fill: #dc2626;
+}
+
+.decrease {
+  color: #16a34a;
+  fill: #16a34a;
+}
+
+.toolbar {
+  margin: 1rem 0;
+}
+
+.toolbar input {
+  padding: 0.375rem 0.5rem;
+  width: 24rem;
+  max-width: 100%;
+}
+
+table {
+  border: 1px solid #6b7280;
+  border-collapse: collapse;
+  width: 100%;
+}
+
+th, td {
+  padding: 0.25rem 0.5rem;
+  text-align: left;
+}
+
+th {
+  background-color: #6b7280;
+  color: #ffffff;
+  cursor: pointer;
+  user-select: none;
+  white-space: nowrap;
+}
+
+th.sorted-asc::after {
+  content: " \25B2";
+}
+
+th.sorted-desc::after {
+  content: " \25BC";
+}
+
+td.cost, th.cost {
+  text-align: right;
+  white-space: nowrap;
+}
+
+tr.resource {
+  border-top: 1px solid #e5e7eb;
+  cursor: pointer;
+}
+
+tr.resource:hover {
+  background-color: #f3f4f6;
+}
+
+tr.resource td.name::before {
+  content: "\25B8  ";
+  color: #6b7280;
+}
+
+tr.resource.expanded td.name::before {
+  content: "\25BE  ";
+}
+
+tr.component {
+  background-color: #f9fafb;
+  color: #4b5563;
+  font-size: 0.875rem;
+}
+
+tr.component td.name {
+  padding-left: 2rem;
+}
+
+.tags, .location {
+  color: #6b7280;
+  font-size: 0.75rem;
+}
+
+.warnings {
+  margin-top: 1.5rem;
+}
+    </style>
+  </head>
+
+  <body>
+    <h1>Infracost cost report</h1>
+    <div class="metadata">
+      Generated by <a href="https://infracost.io" target="_blank">Infracost</a> at {{.Root.TimeGenerated | date "2006-01-02 15:04:05 MST"}}
+    </div>
+
+    <noscript>
+      <p>This report needs JavaScript to show the charts and tables.</p>
+    </noscript>
+
+    <div class="totals" id="totals"></div>
+
+    {{- if .Root.PolicyViolations}}
+    <div class="warnings policy-violations">
+      <p><strong>Budget policy violations</strong></p>
+      <ul>
+        {{- range .Root.PolicyViolations}}
+        <li>{{.Rule}}: {{.Message}}</li>
+        {{- end}}
+      </ul>
+    </div>
+    {{- end}}
+
+    <div class="charts">
+      <div class="chart">
+        <h2>Monthly cost by service</h2>
+        <svg id="services-chart"></svg>
+      </div>
+      <div class="chart">
+        <h2>Monthly cost by project</h2>
+        <svg id="projects-chart"></svg>
+      </div>
+      <div class="chart" id="waterfall">
+        <h2>Monthly cost changes</h2>
+        <svg id="waterfall-chart"></svg>
+      </div>
+    </div>
+
+    <h2>Resources</h2>
+    <div class="toolbar">
+      <input id="filter" type="search" placeholder="Filter by project, address, type or tag">
+    </div>
+    <table id="resources">
+      <thead>
+        <tr>
+          <th data-key="name">Resource</th>
+          <th data-key="project">Project</th>
+          <th data-key="resourceType">Type</th>
+          <th class="cost past" data-key="pastMonthlyCost">Previous</th>
+          <th class="cost" data-key="monthlyCost">Monthly cost</th>
+          <th class="cost past" data-key="diff">Diff</th>
+        </tr>
+      </thead>
+      <tbody></tbody>
+    </table>
+
+    <div class="warnings">
+      <p>{{.UnsupportedResourcesMessage}}</p>
+    </div>
+
+    <script>
+var report = {{.Data}};
+
+(function () {
+  var svgNS = "http://www.w3.org/2000/svg";
+  var currencyFormat = new Intl.NumberFormat(undefined, { style: "currency", currency: report.currency });
+
+  function formatCost(v) {
+    if (v === null || v === undefined) {
+      return "-";
+    }
+    return currencyFormat.format(v);
+  }
+
+  function formatChange(v) {
+    if (v === null || v === undefined) {
+      return "-";
+    }
+    return (v > 0 ? "+" : "") + currencyFormat.format(v);
+  }
+
+  function diff(past, current) {
+    if (past === null && current === null) {
+      return null;
+    }
+    return (current || 0) - (past || 0);
+  }
+
+  function el(tag, attrs, text) {
+    var e = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (k) {
+      e.setAttribute(k, attrs[k]);
+    });
+    if (text !== undefined) {
+      e.textContent = text;
+    }
+    return e;
+  }
+
+  function svgEl(tag, attrs, text) {
+    var e = document.createElementNS(svgNS, tag);
+    Object.keys(attrs || {}).forEach(function (k) {
+      e.setAttribute(k, attrs[k]);
+    });
+    if (text !== undefined) {
+      e.textContent = text;
+    }
+    return e;
+  }
+
+  function renderTotals() {
+    var totals = document.getElementById("totals");
+    var cards = [["Monthly cost", formatCost(report.totalMonthlyCost)]];
+    if (report.hasPast) {
+      cards.unshift(["Previous monthly cost", formatCost(report.totalPastMonthlyCost)]);
+      cards.push(["Monthly change", formatChange(diff(report.totalPastMonthlyCost, report.totalMonthlyCost))]);
+    }
+    cards.forEach(function (c) {
+      var card = el("div", { "class": "card" });
+      card.appendChild(el("div", { "class": "label" }, c[0]));
+      card.appendChild(el("div", { "class": "value" }, c[1]));
+      totals.appendChild(card);
+    });
+  }
+
+  // renderBarChart draws a horizontal bar for each item, with the label on
+  // the left and the cost on the right.
+  function renderBarChart(id, items) {
+    var svg = document.getElementById(id);
+    var rowHeight = 24;
+    var labelWidth = 240;
+    var valueWidth = 100;
+    var width = 640;
+    var barWidth = width - labelWidth - valueWidth;
+    var max = 0;
+
+    items.forEach(function (i) {
+      max = Math.max(max, i.value || 0);
+    });
+
+    svg.setAttribute("viewBox", "0 0 " + width + " " + Math.max(rowHeight, items.length * rowHeight));
+
+    items.forEach(function (item, idx) {
+      var y = idx * rowHeight;
+      var w = max > 0 ? ((item.value || 0) / max) * barWidth : 0;
+      var label = svgEl("text", { x: 0, y: y + 16 }, item.label);
+      label.appendChild(svgEl("title", {}, item.label));
+      svg.appendChild(label);
+      svg.appendChild(svgEl("rect", { x: labelWidth, y: y + 4, width: Math.max(w, 1), height: rowHeight - 8, fill: "#3b82f6" }));
+      svg.appendChild(svgEl("text", { x: labelWidth + Math.max(w, 1) + 6, y: y + 16 }, formatCost(item.value)));
+    });
+  }
+
+  // renderWaterfall draws the previous total, a bar for each change that
+  // starts where the previous one ended, and the new total.
+  function renderWaterfall() {
+    if (!report.hasPast) {
+      document.getElementById("waterfall").style.display = "none";
+      return;
+    }
+
+    var steps = [{ label: "Previous", start: 0, end: report.totalPastMonthlyCost || 0, total: true }];
+    var running = report.totalPastMonthlyCost || 0;
+    report.waterfall.forEach(function (b) {
+      steps.push({ label: b.name, start: running, end: running + b.change, change: b.change });
+      running += b.change;
+    });
+    steps.push({ label: "New", start: 0, end: report.totalMonthlyCost || 0, total: true });
+
+    var svg = document.getElementById("waterfall-chart");
+    var rowHeight = 24;
+    var labelWidth = 240;
+    var valueWidth = 100;
+    var width = 640;
+    var barWidth = width - labelWidth - valueWidth;
+    var max = 0;
+
+    steps.forEach(function (s) {
+      max = Math.max(max, s.start, s.end);
+    });
+
+    svg.setAttribute("viewBox", "0 0 " + width + " " + steps.length * rowHeight);
+
+    steps.forEach(function (s, idx) {
+      var y = idx * rowHeight;
+      var x1 = max > 0 ? (Math.min(s.start, s.end) / max) * barWidth : 0;
+      var x2 = max > 0 ? (Math.max(s.start, s.end) / max) * barWidth : 0;
+      var fill = "#6b7280";
+      var value = formatCost(s.end);
+      if (!s.total) {
+        fill = s.change > 0 ? "#dc2626" : "#16a34a";
+        value = formatChange(s.change);
+      }
+      var label = svgEl("text", { x: 0, y: y + 16 }, s.label);
+      label.appendChild(svgEl("title", {}, s.label));
+      svg.appendChild(label);
+      svg.appendChild(svgEl("rect", { x: labelWidth + x1, y: y + 4, width: Math.max(x2 - x1, 1), height: rowHeight - 8, fill: fill }));
+      svg.appendChild(svgEl("text", { x: labelWidth + x2 + 6, y: y + 16 }, value));
+    });
+  }
+
+  var sortKey = "monthlyCost";
+  var sortDesc = true;
+  var expanded = {};
+
+  function resourceKey(r) {
+    return r.project + "/" + r.name;
+  }
+
+  function sortValue(r, key) {
+    if (key === "diff") {
+      return diff(r.pastMonthlyCost, r.monthlyCost);
+    }
+    return r[key];
+  }
+
+  function matches(r, filter) {
+    if (filter === "") {
+      return true;
+    }
+    var text = [r.project, r.name, r.resourceType];
+    Object.keys(r.tags || {}).forEach(function (k) {
+      text.push(k + "=" + r.tags[k]);
+    });
+    return text.join(" ").toLowerCase().indexOf(filter) !== -1;
+  }
+
+  function renderResources() {
+    var filter = document.getElementById("filter").value.trim().toLowerCase();
+    var tbody = document.querySelector("#resources tbody");
+    tbody.innerHTML = "";
+
+    var resources = report.resources.filter(function (r) {
+      return matches(r, filter);
+    });
+
+    resources.sort(function (a, b) {
+      var av = sortValue(a, sortKey);
+      var bv = sortValue(b, sortKey);
+      if (av === bv) {
+        return a.name < b.name ? -1 : 1;
+      }
+      // Resources without a value are always last
+      if (av === null || av === undefined) {
+        return 1;
+      }
+      if (bv === null || bv === undefined) {
+        return -1;
+      }
+      var cmp = av < bv ? -1 : 1;
+      return sortDesc ? -cmp : cmp;
+    });
+
+    resources.forEach(function (r) {
+      var key = resourceKey(r);
+      var row = el("tr", { "class": "resource" + (expanded[key] ? " expanded" : "") });
+
+      var name = el("td", { "class": "name" }, r.name);
+      var tags = Object.keys(r.tags || {}).sort().map(function (k) {
+        return k + "=" + r.tags[k];
+      });
+      if (tags.length > 0) {
+        name.appendChild(el("div", { "class": "tags" }, tags.join(", ")));
+      }
+      if (r.sourceLocation) {
+        name.appendChild(el("div", { "class": "location" }, r.sourceLocation));
+      }
+
+      row.appendChild(name);
+      row.appendChild(el("td", {}, r.project));
+      row.appendChild(el("td", {}, r.resourceType));
+      row.appendChild(el("td", { "class": "cost past" }, formatCost(r.pastMonthlyCost)));
+      row.appendChild(el("td", { "class": "cost" }, formatCost(r.monthlyCost)));
+      row.appendChild(el("td", { "class": "cost past" }, formatChange(diff(r.pastMonthlyCost, r.monthlyCost))));
+      row.addEventListener("click", function () {
+        expanded[key] = !expanded[key];
+        renderResources();
+      });
+      tbody.appendChild(row);
+
+      if (!expanded[key]) {
+        return;
+      }
+
+      r.costComponents.forEach(function (c) {
+        var qty = c.monthlyQuantity === null ? "" : c.monthlyQuantity.toLocaleString() + " " + c.unit;
+        var crow = el("tr", { "class": "component" });
+        crow.appendChild(el("td", { "class": "name" }, c.name));
+        crow.appendChild(el("td", {}, qty));
+        crow.appendChild(el("td", {}, formatCost(c.price) + " per " + c.unit));
+        crow.appendChild(el("td", { "class": "cost past" }, formatCost(c.pastMonthlyCost)));
+        crow.appendChild(el("td", { "class": "cost" }, formatCost(c.monthlyCost)));
+        crow.appendChild(el("td", { "class": "cost past" }, formatChange(diff(c.pastMonthlyCost, c.monthlyCost))));
+        tbody.appendChild(crow);
+      });
+    });
+
+    document.querySelectorAll("#resources .past").forEach(function (e) {
+      e.style.display = report.hasPast ? "" : "none";
+    });
+
+    document.querySelectorAll("#resources th").forEach(function (th) {
+      th.classList.remove("sorted-asc", "sorted-desc");
+      if (th.getAttribute("data-key") === sortKey) {
+        th.classList.add(sortDesc ? "sorted-desc" : "sorted-asc");
+      }
+    });
+  }
+
+  document.querySelectorAll("#resources th").forEach(function (th) {
+    th.addEventListener("click", function () {
+      var key = th.getAttribute("data-key");
+      if (key === sortKey) {
+        sortDesc = !sortDesc;
+      } else {
+        sortKey = key;
+        sortDesc = th.classList.contains("cost");
+      }
+      renderResources();
+    });
+  });
+
+  document.getElementById("filter").addEventListener("input", renderResources);
+
+  renderTotals();
+  renderBarChart("services-chart", report.services.map(function (s) {
+    return { label: s.name + " (" + s.resourceCount + ")", value: s.monthlyCost };
+  }));
+  renderBarChart("projects-chart", report.projects.map(function (p) {
+    return { label: p.name, value: p.monthlyCost };
+  }));
+  renderWaterfall();
+  renderResources();
+})();
+    </script>
+  </body>
+</html>
+`
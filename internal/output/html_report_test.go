@@ -0,0 +1,106 @@
+package output
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildHTMLReportData(t *testing.T) {
+	root := Root{
+		TotalMonthlyCost: decimalPtr(decimal.NewFromInt(150)),
+		Projects: []Project{
+			{
+				Name: "org/prod",
+				PastBreakdown: &Breakdown{
+					TotalMonthlyCost: decimalPtr(decimal.NewFromInt(80)),
+					Resources: []Resource{
+						{
+							Name:        "aws_instance.web",
+							MonthlyCost: decimalPtr(decimal.NewFromInt(50)),
+							CostComponents: []CostComponent{
+								{Name: "Instance usage", Unit: "hours", Price: decimal.NewFromFloat(0.05), MonthlyQuantity: decimalPtr(decimal.NewFromInt(1000)), MonthlyCost: decimalPtr(decimal.NewFromInt(50))},
+							},
+						},
+						{
+							Name:        "aws_db_instance.db",
+							MonthlyCost: decimalPtr(decimal.NewFromInt(30)),
+						},
+					},
+				},
+				Breakdown: &Breakdown{
+					TotalMonthlyCost: decimalPtr(decimal.NewFromInt(150)),
+					Resources: []Resource{
+						{
+							Name:        "aws_instance.web",
+							Tags:        map[string]string{"team": "web"},
+							MonthlyCost: decimalPtr(decimal.NewFromInt(150)),
+							CostComponents: []CostComponent{
+								{Name: "Instance usage", Service: "AmazonEC2", Unit: "hours", Price: decimal.NewFromFloat(0.15), MonthlyQuantity: decimalPtr(decimal.NewFromInt(1000)), MonthlyCost: decimalPtr(decimal.NewFromInt(150))},
+							},
+							SubResources: []Resource{
+								{
+									Name: "root_block_device",
+									CostComponents: []CostComponent{
+										{Name: "Storage", Unit: "GB", MonthlyQuantity: decimalPtr(decimal.NewFromInt(8))},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	data := buildHTMLReportData(root, Options{})
+
+	assert.Equal(t, "USD", data.Currency)
+	assert.True(t, data.HasPast)
+	assert.Equal(t, 80.0, *data.TotalPastMonthlyCost)
+	assert.Equal(t, 150.0, *data.TotalMonthlyCost)
+
+	assert.Equal(t, []htmlReportService{
+		{Name: "AmazonEC2", ResourceCount: 1, MonthlyCost: floatPtr(150)},
+		{Name: "Other", ResourceCount: 1, MonthlyCost: nil},
+	}, data.Services)
+
+	assert.Len(t, data.Resources, 2)
+
+	web := data.Resources[0]
+	assert.Equal(t, "aws_instance.web", web.Name)
+	assert.Equal(t, "aws_instance", web.ResourceType)
+	assert.Equal(t, map[string]string{"team": "web"}, web.Tags)
+	assert.Equal(t, 50.0, *web.PastMonthlyCost)
+	assert.Equal(t, 150.0, *web.MonthlyCost)
+	assert.Equal(t, []string{"Instance usage", "root_block_device / Storage"}, []string{web.CostComponents[0].Name, web.CostComponents[1].Name})
+	assert.Equal(t, 0.15, web.CostComponents[0].Price)
+	assert.Nil(t, web.CostComponents[1].MonthlyCost)
+
+	db := data.Resources[1]
+	assert.Equal(t, "aws_db_instance.db", db.Name)
+	assert.Equal(t, 30.0, *db.PastMonthlyCost)
+	assert.Nil(t, db.MonthlyCost)
+
+	assert.Equal(t, []htmlReportWaterfallBar{
+		{Name: "aws_instance.web", Change: 100},
+		{Name: "aws_db_instance.db", Change: -30},
+	}, data.Waterfall)
+}
+
+func TestHTMLReportWaterfall(t *testing.T) {
+	resources := []htmlReportResource{
+		{Name: "unchanged", PastMonthlyCost: floatPtr(10), MonthlyCost: floatPtr(10)},
+	}
+	for i := 1; i <= 12; i++ {
+		resources = append(resources, htmlReportResource{Name: fmt.Sprintf("r%d", i), MonthlyCost: floatPtr(float64(i))})
+	}
+
+	bars := htmlReportWaterfall(resources)
+
+	assert.Len(t, bars, maxWaterfallSteps+1)
+	assert.Equal(t, htmlReportWaterfallBar{Name: "r12", Change: 12}, bars[0])
+	assert.Equal(t, htmlReportWaterfallBar{Name: "Other changes", Change: 3}, bars[maxWaterfallSteps])
+}
@@ -0,0 +1,175 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// ToJUnit renders the output as a JUnit report with a test suite per project
+// and a test case per resource. Resources with warnings or errors fail, and
+// unsupported resources are skipped. Notes, such as usage-based costs that
+// have no usage, are added to the output of the test case. Budget violations
+// are failures in their own test suite.
+func ToJUnit(out Root, opts Options) ([]byte, error) {
+	findings := BuildFindings(out, opts)
+
+	// Group the resource findings by project and resource
+	resourceFindings := make(map[string][]Finding)
+	for _, f := range findings {
+		if f.Resource != "" {
+			key := f.Project + "\x00" + f.Resource
+			resourceFindings[key] = append(resourceFindings[key], f)
+		}
+	}
+
+	report := junitTestSuites{Name: "Infracost"}
+
+	for _, p := range out.Projects {
+		project := p.Label(opts.DashboardEnabled)
+		suite := junitTestSuite{Name: project}
+
+		if p.Breakdown != nil {
+			for _, r := range p.Breakdown.Resources {
+				tc := junitTestCase{Name: r.Name, Classname: project}
+				if r.SourceLocation != nil {
+					tc.File = r.SourceLocation.Filename
+					tc.Line = r.SourceLocation.Line
+				}
+
+				var failures, notes []string
+				for _, f := range resourceFindings[project+"\x00"+r.Name] {
+					if f.Rule.Level == FindingLevelNote {
+						notes = append(notes, f.Message)
+					} else {
+						failures = append(failures, fmt.Sprintf("%s: %s", f.Rule.Name, f.Message))
+					}
+				}
+
+				if len(failures) > 0 {
+					tc.Failure = &junitFailure{
+						Message: failures[0],
+						Type:    "warning",
+						Text:    strings.Join(failures, "\n"),
+					}
+				}
+				tc.SystemOut = strings.Join(notes, "\n")
+
+				suite.Cases = append(suite.Cases, tc)
+			}
+		}
+
+		for _, r := range p.UnsupportedResources {
+			tc := junitTestCase{
+				Name:      r.Name,
+				Classname: project,
+				Skipped:   &junitSkipped{Message: fmt.Sprintf("%s is not supported yet", r.ResourceType)},
+			}
+			if r.SourceLocation != nil {
+				tc.File = r.SourceLocation.Filename
+				tc.Line = r.SourceLocation.Line
+			}
+
+			suite.Cases = append(suite.Cases, tc)
+		}
+
+		report.addSuite(suite)
+	}
+
+	if len(out.PolicyViolations) > 0 {
+		suite := junitTestSuite{Name: "Budget policies"}
+
+		for _, f := range findings {
+			if f.Rule.ID != budgetViolationRule.ID {
+				continue
+			}
+
+			classname := f.Project
+			if classname == "" {
+				classname = "Total"
+			}
+
+			tc := junitTestCase{
+				Name:      f.Message,
+				Classname: classname,
+				Failure: &junitFailure{
+					Message: f.Message,
+					Type:    "error",
+					Text:    f.Message,
+				},
+			}
+			for _, loc := range f.Locations {
+				if loc.SourceLocation != nil {
+					tc.File = loc.SourceLocation.Filename
+					tc.Line = loc.SourceLocation.Line
+					break
+				}
+			}
+
+			suite.Cases = append(suite.Cases, tc)
+		}
+
+		report.addSuite(suite)
+	}
+
+	b, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
+
+func (s *junitTestSuites) addSuite(suite junitTestSuite) {
+	for _, tc := range suite.Cases {
+		suite.Tests++
+		if tc.Failure != nil {
+			suite.Failures++
+		}
+		if tc.Skipped != nil {
+			suite.Skipped++
+		}
+	}
+
+	s.Tests += suite.Tests
+	s.Failures += suite.Failures
+	s.Skipped += suite.Skipped
+	s.Suites = append(s.Suites, suite)
+}
@@ -0,0 +1,394 @@
+package output
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+// ToMarkdown renders the output as GitHub and GitLab flavored markdown so it
+// can be posted as a pull request comment. Each project has a row in the
+// summary table, and each resource that changed is a collapsible section.
+func ToMarkdown(out Root, opts Options) ([]byte, error) {
+	var b strings.Builder
+
+	b.WriteString("## Infracost estimate\n\n")
+
+	if len(out.PolicyViolations) > 0 {
+		b.WriteString("> **Budget policy violations:**\n")
+		for _, v := range out.PolicyViolations {
+			b.WriteString(fmt.Sprintf("> - %s: %s\n", markdownText(v.Rule), markdownText(v.Message)))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(markdownSummaryTable(out, opts))
+
+	for _, project := range out.Projects {
+		b.WriteString("\n")
+		b.WriteString(markdownProject(out.Currency, project, opts))
+	}
+
+	footer := make([]string, 0)
+
+	if out.PriceEffectiveDate != "" {
+		footer = append(footer, fmt.Sprintf("Prices as of %s.", out.PriceEffectiveDate))
+	}
+
+	for _, e := range out.ExchangeRates {
+		footer = append(footer, fmt.Sprintf("Converted %s to %s at %s.", e.From, e.To, e.Rate.String()))
+	}
+
+	unsupportedMsg := out.unsupportedResourcesMessage(opts.ShowSkipped)
+	if unsupportedMsg != "" {
+		footer = append(footer, strings.ReplaceAll(unsupportedMsg, "\n", "  \n"))
+	}
+
+	if len(footer) > 0 {
+		b.WriteString("\n---\n\n")
+		b.WriteString(strings.Join(footer, "\n\n"))
+		b.WriteString("\n")
+	}
+
+	return []byte(b.String()), nil
+}
+
+func markdownSummaryTable(out Root, opts Options) string {
+	var b strings.Builder
+
+	b.WriteString("| Project | Previous | New | Diff |\n")
+	b.WriteString("| --- | ---: | ---: | ---: |\n")
+
+	var totalPast, totalNew *decimal.Decimal
+
+	for _, project := range out.Projects {
+		pastCost, newCost := projectCosts(project)
+
+		b.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+			markdownCell(project.Label(opts.DashboardEnabled)),
+			formatCost(out.Currency, pastCost),
+			formatCost(out.Currency, newCost),
+			markdownCostChange(out.Currency, pastCost, newCost, formatCost),
+		))
+
+		totalPast = addDecimalPtrs(totalPast, pastCost)
+		totalNew = addDecimalPtrs(totalNew, newCost)
+	}
+
+	if len(out.Projects) > 1 {
+		b.WriteString(fmt.Sprintf("| **Total** | **%s** | **%s** | **%s** |\n",
+			formatCost(out.Currency, totalPast),
+			formatCost(out.Currency, totalNew),
+			markdownCostChange(out.Currency, totalPast, totalNew, formatCost),
+		))
+	}
+
+	return b.String()
+}
+
+func markdownProject(currency string, project Project, opts Options) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("### %s\n\n", markdownText(project.Label(opts.DashboardEnabled))))
+
+	var pastResources, newResources []Resource
+	if project.PastBreakdown != nil {
+		pastResources = project.PastBreakdown.Resources
+	}
+	if project.Breakdown != nil {
+		newResources = project.Breakdown.Resources
+	}
+
+	// Show the resources that changed, or all the resources if there's no diff
+	resources := newResources
+	if project.Diff != nil {
+		resources = project.Diff.Resources
+	}
+
+	if len(resources) == 0 {
+		b.WriteString("No cost changes detected.\n")
+		return b.String()
+	}
+
+	hasNilCosts := false
+	sections := make([]string, 0, len(resources))
+
+	for _, r := range resources {
+		oldResource := findResourceByName(pastResources, pastResourceName(r))
+		newResource := findResourceByName(newResources, r.Name)
+
+		if (oldResource != nil && resourceHasNilCosts(*oldResource)) ||
+			(newResource != nil && resourceHasNilCosts(*newResource)) {
+			hasNilCosts = true
+		}
+
+		sections = append(sections, markdownResource(currency, r, oldResource, newResource))
+	}
+
+	if hasNilCosts {
+		b.WriteString("> **Warning:** this project has usage-based costs that aren't included in the estimate. ")
+		b.WriteString("Use `--usage-file` to estimate them, see https://infracost.io/usage-file\n\n")
+	}
+
+	b.WriteString(strings.Join(sections, "\n"))
+
+	return b.String()
+}
+
+// markdownResource returns the section of a resource. The resource is the diff
+// resource, or the new resource if there's no diff.
+func markdownResource(currency string, r Resource, oldResource *Resource, newResource *Resource) string {
+	var b strings.Builder
+
+	var oldCost, newCost *decimal.Decimal
+	if oldResource != nil {
+		oldCost = oldResource.MonthlyCost
+	}
+	if newResource != nil {
+		newCost = newResource.MonthlyCost
+	}
+
+	summary := "monthly cost depends on usage"
+	if oldCost != nil || newCost != nil {
+		summary = markdownCostChange(currency, oldCost, newCost, formatCost)
+	}
+
+	switch {
+	case oldResource == nil:
+		summary += ", added"
+	case newResource == nil:
+		summary += ", removed"
+	case oldCost != nil && newCost != nil:
+		summary += fmt.Sprintf(" (%s → %s)", formatCost(currency, oldCost), formatCost(currency, newCost))
+	}
+
+	if r.MovedFrom != "" {
+		summary += fmt.Sprintf(", moved from %s", r.MovedFrom)
+	}
+
+	b.WriteString("<details>\n")
+	b.WriteString(fmt.Sprintf("<summary><code>%s</code>: %s</summary>\n\n", html.EscapeString(r.Name), html.EscapeString(summary)))
+
+	if loc := markdownSourceLocation(oldResource, newResource); loc != "" {
+		b.WriteString(loc + "\n\n")
+	}
+
+	b.WriteString("| Cost component | Monthly qty | Unit | Previous | New | Diff |\n")
+	b.WriteString("| --- | ---: | --- | ---: | ---: | ---: |\n")
+
+	for _, row := range markdownCostComponentRows(currency, "", oldResource, newResource) {
+		b.WriteString(row)
+	}
+
+	if changes := changedAttributes(r); len(changes) > 0 {
+		b.WriteString("\nChanged attributes:\n")
+		for _, c := range changes {
+			b.WriteString(fmt.Sprintf("- `%s`: %s → %s\n", c.Name, markdownText(c.Before), markdownText(c.After)))
+		}
+	}
+
+	b.WriteString("\n</details>\n")
+
+	return b.String()
+}
+
+// changedAttributes returns the attribute changes that explain the changes of
+// the cost components of the resource and its sub-resources.
+func changedAttributes(r Resource) []*schema.AttributeChange {
+	changes := make([]*schema.AttributeChange, 0)
+	seen := make(map[string]bool)
+
+	var add func(r Resource)
+	add = func(r Resource) {
+		for _, c := range r.CostComponents {
+			for _, a := range c.ChangedAttributes {
+				if !seen[a.Name] {
+					seen[a.Name] = true
+					changes = append(changes, a)
+				}
+			}
+		}
+
+		for _, s := range r.SubResources {
+			add(s)
+		}
+	}
+	add(r)
+
+	return changes
+}
+
+// markdownSourceLocation returns where the resource is defined, preferring
+// the new version of the resource since that's the code in the change.
+func markdownSourceLocation(oldResource *Resource, newResource *Resource) string {
+	r := newResource
+	if r == nil || r.SourceLocation == nil {
+		r = oldResource
+	}
+	if r == nil || r.SourceLocation.String() == "" {
+		return ""
+	}
+
+	msg := fmt.Sprintf("Defined in `%s`", r.SourceLocation.String())
+	if r.SourceLocation.ModuleSource != "" {
+		msg += fmt.Sprintf(" from module `%s`", r.SourceLocation.ModuleSource)
+	}
+
+	return msg
+}
+
+// markdownCostComponentRows returns a table row for each cost component of the
+// resource and its sub-resources, with the cost components from both the old
+// and new versions of the resource.
+func markdownCostComponentRows(currency string, prefix string, oldResource *Resource, newResource *Resource) []string {
+	rows := make([]string, 0)
+
+	var oldComponents, newComponents []CostComponent
+	var oldSubResources, newSubResources []Resource
+
+	if oldResource != nil {
+		oldComponents = oldResource.CostComponents
+		oldSubResources = oldResource.SubResources
+	}
+	if newResource != nil {
+		newComponents = newResource.CostComponents
+		newSubResources = newResource.SubResources
+	}
+
+	for _, name := range mergeNames(costComponentNames(oldComponents), costComponentNames(newComponents)) {
+		oldComponent := findCostComponentByName(oldComponents, name)
+		newComponent := findCostComponentByName(newComponents, name)
+
+		c := newComponent
+		if c == nil {
+			c = oldComponent
+		}
+
+		var oldCost, newCost *decimal.Decimal
+		if oldComponent != nil {
+			oldCost = oldComponent.MonthlyCost
+		}
+		if newComponent != nil {
+			newCost = newComponent.MonthlyCost
+		}
+
+		rows = append(rows, fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+			markdownCell(prefix+name),
+			formatQuantity(c.MonthlyQuantity),
+			markdownCell(c.Unit),
+			formatCost2DP(currency, oldCost),
+			formatCost2DP(currency, newCost),
+			markdownCostChange(currency, oldCost, newCost, formatCost2DP),
+		))
+	}
+
+	for _, name := range mergeNames(resourceNames(oldSubResources), resourceNames(newSubResources)) {
+		rows = append(rows, markdownCostComponentRows(
+			currency,
+			prefix+name+" / ",
+			findResourceByName(oldSubResources, name),
+			findResourceByName(newSubResources, name),
+		)...)
+	}
+
+	return rows
+}
+
+// markdownCostChange formats the change between the costs. A missing cost is
+// treated as zero, unless both costs are missing since then the cost depends
+// on usage.
+func markdownCostChange(currency string, oldCost *decimal.Decimal, newCost *decimal.Decimal, format func(string, *decimal.Decimal) string) string {
+	if oldCost == nil && newCost == nil {
+		return "-"
+	}
+
+	oldValue := decimal.Zero
+	if oldCost != nil {
+		oldValue = *oldCost
+	}
+
+	newValue := decimal.Zero
+	if newCost != nil {
+		newValue = *newCost
+	}
+
+	diff := newValue.Sub(oldValue)
+
+	abs := diff.Abs()
+	s := getSym(diff) + format(currency, &abs)
+
+	percent := formatPercentChange(oldCost, newCost)
+	if percent != "" {
+		s += fmt.Sprintf(" (%s)", percent)
+	}
+
+	return s
+}
+
+func projectCosts(project Project) (*decimal.Decimal, *decimal.Decimal) {
+	var pastCost, newCost *decimal.Decimal
+
+	if project.PastBreakdown != nil {
+		pastCost = project.PastBreakdown.TotalMonthlyCost
+	}
+	if project.Breakdown != nil {
+		newCost = project.Breakdown.TotalMonthlyCost
+	}
+
+	return pastCost, newCost
+}
+
+func addDecimalPtrs(a *decimal.Decimal, b *decimal.Decimal) *decimal.Decimal {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	return decimalPtr(a.Add(*b))
+}
+
+func costComponentNames(components []CostComponent) []string {
+	names := make([]string, 0, len(components))
+	for _, c := range components {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func resourceNames(resources []Resource) []string {
+	names := make([]string, 0, len(resources))
+	for _, r := range resources {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// mergeNames returns the new names in order followed by any old names that
+// were removed.
+func mergeNames(oldNames []string, newNames []string) []string {
+	names := append([]string{}, newNames...)
+
+	for _, n := range oldNames {
+		if !contains(newNames, n) {
+			names = append(names, n)
+		}
+	}
+
+	return names
+}
+
+// markdownCell escapes text so it can be used in a markdown table cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(markdownText(s), "|", "\\|")
+}
+
+// markdownText escapes the characters that would otherwise be interpreted
+// as HTML.
+func markdownText(s string) string {
+	return strings.NewReplacer("<", "&lt;", ">", "&gt;").Replace(s)
+}
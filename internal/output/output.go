@@ -12,16 +12,25 @@ import (
 
 var outputVersion = "0.2"
 
+// Root is the output of a run. PriceEffectiveDate is the date the prices were
+// effective on when the run used --as-of, otherwise the prices were the
+// current prices at TimeGenerated.
 type Root struct {
-	Version          string           `json:"version"`
-	RunID            string           `json:"runId,omitempty"`
-	Currency         string           `json:"currency"`
-	Projects         []Project        `json:"projects"`
-	TotalHourlyCost  *decimal.Decimal `json:"totalHourlyCost"`
-	TotalMonthlyCost *decimal.Decimal `json:"totalMonthlyCost"`
-	TimeGenerated    time.Time        `json:"timeGenerated"`
-	Summary          *Summary         `json:"summary"`
-	FullSummary      *Summary         `json:"-"`
+	Version            string             `json:"version"`
+	RunID              string             `json:"runId,omitempty"`
+	Currency           string             `json:"currency"`
+	Projects           []Project          `json:"projects"`
+	TotalHourlyCost    *decimal.Decimal   `json:"totalHourlyCost"`
+	TotalMonthlyCost   *decimal.Decimal   `json:"totalMonthlyCost"`
+	TimeGenerated      time.Time          `json:"timeGenerated"`
+	PriceEffectiveDate string             `json:"priceEffectiveDate,omitempty"`
+	Summary            *Summary           `json:"summary"`
+	FullSummary        *Summary           `json:"-"`
+	PricingIssues      []PricingIssue     `json:"pricingIssues,omitempty"`
+	Commitments        *CommitmentSummary `json:"commitments,omitempty"`
+	ExchangeRates      []ExchangeRate     `json:"exchangeRates,omitempty"`
+	Rollups            []Rollup           `json:"rollups,omitempty"`
+	PolicyViolations   []PolicyViolation  `json:"policyViolations,omitempty"`
 }
 
 type Project struct {
@@ -32,6 +41,16 @@ type Project struct {
 	Diff          *Breakdown              `json:"diff"`
 	Summary       *Summary                `json:"summary"`
 	fullSummary   *Summary
+
+	UnsupportedResources []UnsupportedResource `json:"unsupportedResources,omitempty"`
+}
+
+// UnsupportedResource is a resource that Infracost doesn't have prices for
+// yet, so it's skipped from the breakdown.
+type UnsupportedResource struct {
+	Name           string                 `json:"name"`
+	ResourceType   string                 `json:"resourceType"`
+	SourceLocation *schema.SourceLocation `json:"sourceLocation,omitempty"`
 }
 
 func (p *Project) Label(dashboardEnabled bool) string {
@@ -48,23 +67,30 @@ type Breakdown struct {
 }
 
 type CostComponent struct {
-	Name            string           `json:"name"`
-	Unit            string           `json:"unit"`
-	HourlyQuantity  *decimal.Decimal `json:"hourlyQuantity"`
-	MonthlyQuantity *decimal.Decimal `json:"monthlyQuantity"`
-	Price           decimal.Decimal  `json:"price"`
-	HourlyCost      *decimal.Decimal `json:"hourlyCost"`
-	MonthlyCost     *decimal.Decimal `json:"monthlyCost"`
+	Name              string                    `json:"name"`
+	Service           string                    `json:"service,omitempty"`
+	Unit              string                    `json:"unit"`
+	HourlyQuantity    *decimal.Decimal          `json:"hourlyQuantity"`
+	MonthlyQuantity   *decimal.Decimal          `json:"monthlyQuantity"`
+	Price             decimal.Decimal           `json:"price"`
+	ListPrice         *decimal.Decimal          `json:"listPrice,omitempty"`
+	HourlyCost        *decimal.Decimal          `json:"hourlyCost"`
+	MonthlyCost       *decimal.Decimal          `json:"monthlyCost"`
+	MonthlyCredit     *decimal.Decimal          `json:"monthlyCredit,omitempty"`
+	Coverage          []CommitmentCoverage      `json:"commitmentCoverage,omitempty"`
+	ChangedAttributes []*schema.AttributeChange `json:"changedAttributes,omitempty"`
 }
 
 type Resource struct {
-	Name           string            `json:"name"`
-	Tags           map[string]string `json:"tags,omitempty"`
-	Metadata       map[string]string `json:"metadata"`
-	HourlyCost     *decimal.Decimal  `json:"hourlyCost"`
-	MonthlyCost    *decimal.Decimal  `json:"monthlyCost"`
-	CostComponents []CostComponent   `json:"costComponents,omitempty"`
-	SubResources   []Resource        `json:"subresources,omitempty"`
+	Name           string                 `json:"name"`
+	Tags           map[string]string      `json:"tags,omitempty"`
+	Metadata       map[string]string      `json:"metadata"`
+	SourceLocation *schema.SourceLocation `json:"sourceLocation,omitempty"`
+	MovedFrom      string                 `json:"movedFrom,omitempty"`
+	HourlyCost     *decimal.Decimal       `json:"hourlyCost"`
+	MonthlyCost    *decimal.Decimal       `json:"monthlyCost"`
+	CostComponents []CostComponent        `json:"costComponents,omitempty"`
+	SubResources   []Resource             `json:"subresources,omitempty"`
 }
 
 type Summary struct {
@@ -93,6 +119,32 @@ type Options struct {
 	GroupLabel       string
 	GroupKey         string
 	Fields           []string
+	MetricTags       []string
+}
+
+// outputUnsupportedResources returns the resources that were skipped because
+// they're not supported. Like the summary, resources from providers that
+// aren't supported at all are left out.
+func outputUnsupportedResources(resources []*schema.Resource) []UnsupportedResource {
+	arr := make([]UnsupportedResource, 0)
+
+	for _, r := range resources {
+		if !r.IsSkipped || r.NoPrice || !terraform.HasSupportedProvider(r.ResourceType) {
+			continue
+		}
+
+		arr = append(arr, UnsupportedResource{
+			Name:           r.Name,
+			ResourceType:   r.ResourceType,
+			SourceLocation: r.SourceLocation,
+		})
+	}
+
+	sort.Slice(arr, func(i, j int) bool {
+		return arr[i].Name < arr[j].Name
+	})
+
+	return arr
 }
 
 func outputBreakdown(resources []*schema.Resource) *Breakdown {
@@ -119,15 +171,24 @@ func outputBreakdown(resources []*schema.Resource) *Breakdown {
 func outputResource(r *schema.Resource) Resource {
 	comps := make([]CostComponent, 0, len(r.CostComponents))
 	for _, c := range r.CostComponents {
+		var service string
+		if c.ProductFilter != nil && c.ProductFilter.Service != nil {
+			service = *c.ProductFilter.Service
+		}
 
 		comps = append(comps, CostComponent{
-			Name:            c.Name,
-			Unit:            c.Unit,
-			HourlyQuantity:  c.UnitMultiplierHourlyQuantity(),
-			MonthlyQuantity: c.UnitMultiplierMonthlyQuantity(),
-			Price:           c.UnitMultiplierPrice(),
-			HourlyCost:      c.HourlyCost,
-			MonthlyCost:     c.MonthlyCost,
+			Name:              c.Name,
+			Service:           service,
+			Unit:              c.Unit,
+			HourlyQuantity:    c.UnitMultiplierHourlyQuantity(),
+			MonthlyQuantity:   c.UnitMultiplierMonthlyQuantity(),
+			Price:             c.UnitMultiplierPrice(),
+			ListPrice:         c.UnitMultiplierListPrice(),
+			HourlyCost:        c.HourlyCost,
+			MonthlyCost:       c.MonthlyCost,
+			MonthlyCredit:     c.MonthlyCredit(),
+			Coverage:          outputCommitmentCoverage(c),
+			ChangedAttributes: c.ChangedAttributes,
 		})
 	}
 
@@ -136,9 +197,16 @@ func outputResource(r *schema.Resource) Resource {
 		subresources = append(subresources, outputResource(s))
 	}
 
+	metadata := map[string]string{}
+	if region := resourceRegion(r); region != "" {
+		metadata["region"] = region
+	}
+
 	return Resource{
 		Name:           r.Name,
-		Metadata:       map[string]string{},
+		Metadata:       metadata,
+		SourceLocation: r.SourceLocation,
+		MovedFrom:      r.MovedFrom,
 		Tags:           r.Tags,
 		HourlyCost:     r.HourlyCost,
 		MonthlyCost:    r.MonthlyCost,
@@ -147,6 +215,24 @@ func outputResource(r *schema.Resource) Resource {
 	}
 }
 
+// resourceRegion returns the region from the price filters of the cost
+// components, falling back to the sub-resources.
+func resourceRegion(r *schema.Resource) string {
+	for _, c := range r.CostComponents {
+		if c.ProductFilter != nil && c.ProductFilter.Region != nil && *c.ProductFilter.Region != "" {
+			return *c.ProductFilter.Region
+		}
+	}
+
+	for _, s := range r.SubResources {
+		if region := resourceRegion(s); region != "" {
+			return region
+		}
+	}
+
+	return ""
+}
+
 func ToOutputFormat(projects []*schema.Project) Root {
 	var totalMonthlyCost, totalHourlyCost *decimal.Decimal
 
@@ -196,6 +282,8 @@ func ToOutputFormat(projects []*schema.Project) Root {
 			Diff:          diff,
 			Summary:       summary,
 			fullSummary:   fullSummary,
+
+			UnsupportedResources: outputUnsupportedResources(project.Resources),
 		})
 	}
 
@@ -0,0 +1,208 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/shopspring/decimal"
+)
+
+// PolicyViolation is a budget rule from the config file that the costs
+// exceeded. Project is empty for rules checked against the total of all the
+// projects.
+type PolicyViolation struct {
+	Rule    string          `json:"rule"`
+	Project string          `json:"project,omitempty"`
+	Check   string          `json:"check"`
+	Limit   decimal.Decimal `json:"limit"`
+	Actual  decimal.Decimal `json:"actual"`
+	Message string          `json:"message"`
+
+	// Resources are the addresses of the resources with a cost that count
+	// towards the budget, so the violation can be linked back to them.
+	Resources []string `json:"resources,omitempty"`
+}
+
+// budgetCosts are the previous and new monthly costs of the resources a
+// budget rule applies to.
+type budgetCosts struct {
+	past      decimal.Decimal
+	current   decimal.Decimal
+	hasPast   bool
+	resources []string
+}
+
+// EvaluateBudgets checks the costs against the budget rules and returns the
+// violations.
+func EvaluateBudgets(out Root, policies config.PolicySpec, opts Options) []PolicyViolation {
+	violations := make([]PolicyViolation, 0)
+
+	for i, rule := range policies.Budgets {
+		label := rule.Label(i)
+
+		if rule.Project == "" {
+			costs := budgetCostsFor(rule, out.Projects)
+			violations = append(violations, checkBudget(out.Currency, label, "", rule, costs)...)
+			continue
+		}
+
+		for _, p := range out.Projects {
+			name := p.Label(opts.DashboardEnabled)
+			if !rule.MatchesProject(p.Name) {
+				continue
+			}
+
+			costs := budgetCostsFor(rule, []Project{p})
+			violations = append(violations, checkBudget(out.Currency, label, name, rule, costs)...)
+		}
+	}
+
+	return violations
+}
+
+func budgetCostsFor(rule *config.BudgetRule, projects []Project) budgetCosts {
+	var costs budgetCosts
+
+	for _, p := range projects {
+		if p.PastBreakdown != nil {
+			costs.hasPast = true
+			costs.past = costs.past.Add(budgetResourcesCost(rule, p.PastBreakdown.Resources))
+		}
+
+		if p.Breakdown != nil {
+			costs.current = costs.current.Add(budgetResourcesCost(rule, p.Breakdown.Resources))
+
+			for _, r := range p.Breakdown.Resources {
+				if budgetMatchesResource(rule, r) && !decimalOrZero(r.MonthlyCost).IsZero() {
+					costs.resources = append(costs.resources, r.Name)
+				}
+			}
+		}
+	}
+
+	return costs
+}
+
+func budgetResourcesCost(rule *config.BudgetRule, resources []Resource) decimal.Decimal {
+	total := decimal.Zero
+
+	for _, r := range resources {
+		if !budgetMatchesResource(rule, r) {
+			continue
+		}
+
+		total = total.Add(decimalOrZero(r.MonthlyCost))
+	}
+
+	return total
+}
+
+func budgetMatchesResource(rule *config.BudgetRule, r Resource) bool {
+	if rule.ResourceType != "" && ResourceType(r.Name) != rule.ResourceType {
+		return false
+	}
+
+	for k, v := range rule.Tags {
+		if r.Tags[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func checkBudget(currency string, label string, project string, rule *config.BudgetRule, costs budgetCosts) []PolicyViolation {
+	violations := make([]PolicyViolation, 0)
+
+	scope := "Total monthly cost"
+	if project != "" {
+		scope = fmt.Sprintf("Monthly cost of %s", project)
+	}
+	if rule.ResourceType != "" || len(rule.Tags) > 0 {
+		scope += " for " + budgetFilterDescription(rule)
+	}
+
+	if rule.MaxMonthlyCost != nil {
+		limit := decimal.NewFromFloat(*rule.MaxMonthlyCost)
+		if costs.current.GreaterThan(limit) {
+			violations = append(violations, PolicyViolation{
+				Rule:    label,
+				Project: project,
+				Check:   "max_monthly_cost",
+				Limit:   limit,
+				Actual:  costs.current,
+				Message: fmt.Sprintf("%s is %s, over the budget of %s", scope, formatCost2DP(currency, &costs.current), formatCost2DP(currency, &limit)),
+
+				Resources: costs.resources,
+			})
+		}
+	}
+
+	if !costs.hasPast {
+		return violations
+	}
+
+	increase := costs.current.Sub(costs.past)
+
+	if rule.MaxMonthlyIncrease != nil {
+		limit := decimal.NewFromFloat(*rule.MaxMonthlyIncrease)
+		if increase.GreaterThan(limit) {
+			violations = append(violations, PolicyViolation{
+				Rule:    label,
+				Project: project,
+				Check:   "max_monthly_increase",
+				Limit:   limit,
+				Actual:  increase,
+				Message: fmt.Sprintf("%s increases by %s, more than the maximum of %s", scope, formatCost2DP(currency, &increase), formatCost2DP(currency, &limit)),
+
+				Resources: costs.resources,
+			})
+		}
+	}
+
+	// A percent increase can't be calculated without a previous cost
+	if rule.MaxMonthlyIncreasePercent != nil && costs.past.IsPositive() {
+		limit := decimal.NewFromFloat(*rule.MaxMonthlyIncreasePercent)
+		percent := increase.Div(costs.past).Mul(decimal.NewFromInt(100)).Round(2)
+		if percent.GreaterThan(limit) {
+			violations = append(violations, PolicyViolation{
+				Rule:    label,
+				Project: project,
+				Check:   "max_monthly_increase_percent",
+				Limit:   limit,
+				Actual:  percent,
+				Message: fmt.Sprintf("%s increases by %s%%, more than the maximum of %s%%", scope, percent.String(), limit.String()),
+
+				Resources: costs.resources,
+			})
+		}
+	}
+
+	return violations
+}
+
+func budgetFilterDescription(rule *config.BudgetRule) string {
+	parts := make([]string, 0)
+
+	if rule.ResourceType != "" {
+		parts = append(parts, rule.ResourceType)
+	}
+
+	if len(rule.Tags) > 0 {
+		parts = append(parts, "tags "+formatTags(rule.Tags))
+	}
+
+	return strings.Join(parts, " with ")
+}
+
+// PolicyViolationsMessage lists the violations, one per line.
+func PolicyViolationsMessage(violations []PolicyViolation) string {
+	lines := make([]string, 0, len(violations))
+
+	for _, v := range violations {
+		lines = append(lines, fmt.Sprintf("Budget %s: %s", v.Rule, v.Message))
+	}
+
+	return strings.Join(lines, "\n")
+}
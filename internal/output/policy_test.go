@@ -0,0 +1,94 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestEvaluateBudgets(t *testing.T) {
+	resource := func(name string, team string, monthlyCost int64) Resource {
+		return Resource{Name: name, Tags: map[string]string{"team": team}, MonthlyCost: decimalPtr(decimal.NewFromInt(monthlyCost))}
+	}
+
+	root := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name:          "org/prod",
+				PastBreakdown: &Breakdown{Resources: []Resource{resource("aws_instance.web", "web", 100)}},
+				Breakdown:     &Breakdown{Resources: []Resource{resource("aws_instance.web", "web", 150), resource("aws_db_instance.db", "data", 200)}},
+			},
+			{
+				Name:      "org/dev",
+				Breakdown: &Breakdown{Resources: []Resource{resource("aws_instance.web", "web", 20)}},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		rule     config.BudgetRule
+		expected []PolicyViolation
+	}{
+		"total under budget": {
+			rule: config.BudgetRule{MaxMonthlyCost: floatPtr(400)},
+		},
+		"total over budget": {
+			rule: config.BudgetRule{MaxMonthlyCost: floatPtr(300)},
+			expected: []PolicyViolation{
+				{Rule: "#1", Check: "max_monthly_cost", Limit: decimal.NewFromInt(300), Actual: decimal.NewFromInt(370), Message: "Total monthly cost is $370.00, over the budget of $300.00"},
+			},
+		},
+		"project pattern": {
+			rule: config.BudgetRule{Name: "projects", Project: "org/*", MaxMonthlyCost: floatPtr(100)},
+			expected: []PolicyViolation{
+				{Rule: "projects", Project: "org/prod", Check: "max_monthly_cost", Limit: decimal.NewFromInt(100), Actual: decimal.NewFromInt(350), Message: "Monthly cost of org/prod is $350.00, over the budget of $100.00"},
+			},
+		},
+		"resource type and tags": {
+			rule: config.BudgetRule{ResourceType: "aws_instance", Tags: map[string]string{"team": "web"}, MaxMonthlyCost: floatPtr(150)},
+			expected: []PolicyViolation{
+				{Rule: "#1", Check: "max_monthly_cost", Limit: decimal.NewFromInt(150), Actual: decimal.NewFromInt(170), Message: "Total monthly cost for aws_instance with tags team=web is $170.00, over the budget of $150.00"},
+			},
+		},
+		"increase": {
+			rule: config.BudgetRule{Project: "*prod", MaxMonthlyIncrease: floatPtr(200), MaxMonthlyIncreasePercent: floatPtr(100)},
+			expected: []PolicyViolation{
+				{Rule: "#1", Project: "org/prod", Check: "max_monthly_increase", Limit: decimal.NewFromInt(200), Actual: decimal.NewFromInt(250), Message: "Monthly cost of org/prod increases by $250.00, more than the maximum of $200.00"},
+				{Rule: "#1", Project: "org/prod", Check: "max_monthly_increase_percent", Limit: decimal.NewFromInt(100), Actual: decimal.NewFromInt(250), Message: "Monthly cost of org/prod increases by 250%, more than the maximum of 100%"},
+			},
+		},
+		"increase without previous cost": {
+			rule: config.BudgetRule{Project: "*dev", MaxMonthlyIncrease: floatPtr(0), MaxMonthlyIncreasePercent: floatPtr(0)},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rule := tc.rule
+			violations := EvaluateBudgets(root, config.PolicySpec{Budgets: []*config.BudgetRule{&rule}}, Options{})
+
+			if tc.expected == nil {
+				assert.Empty(t, violations)
+				return
+			}
+
+			assert.Len(t, violations, len(tc.expected))
+			for i, v := range violations {
+				e := tc.expected[i]
+				assert.Equal(t, e.Rule, v.Rule)
+				assert.Equal(t, e.Project, v.Project)
+				assert.Equal(t, e.Check, v.Check)
+				assert.True(t, e.Limit.Equal(v.Limit), "limit %s", v.Limit)
+				assert.True(t, e.Actual.Equal(v.Actual), "actual %s", v.Actual)
+				assert.Equal(t, e.Message, v.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// PricingIssue is a cost component whose price lookup didn't match exactly
+// one price. These are reported when running with --strict-pricing.
+type PricingIssue struct {
+	ProjectName   string `json:"projectName"`
+	ResourceName  string `json:"resourceName"`
+	SubResource   string `json:"subresource,omitempty"`
+	CostComponent string `json:"costComponent"`
+	Type          string `json:"type"`
+	Matches       int    `json:"matches"`
+	Message       string `json:"message"`
+}
+
+// BuildPricingIssues collects the pricing issues of all the cost components in
+// the projects. Issues for resources in both the past and current state are
+// only reported once.
+func BuildPricingIssues(projects []*schema.Project) []PricingIssue {
+	issues := make([]PricingIssue, 0)
+	seen := make(map[PricingIssue]bool)
+
+	for _, project := range projects {
+		for _, r := range append(append([]*schema.Resource{}, project.Resources...), project.PastResources...) {
+			if r.IsSkipped {
+				continue
+			}
+
+			for _, issue := range resourcePricingIssues(project.Name, r.Name, nil, r) {
+				if seen[issue] {
+					continue
+				}
+				seen[issue] = true
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.ProjectName != b.ProjectName {
+			return a.ProjectName < b.ProjectName
+		}
+		if a.ResourceName != b.ResourceName {
+			return a.ResourceName < b.ResourceName
+		}
+		return a.SubResource < b.SubResource
+	})
+
+	return issues
+}
+
+func resourcePricingIssues(projectName string, resourceName string, path []string, r *schema.Resource) []PricingIssue {
+	issues := make([]PricingIssue, 0)
+
+	for _, c := range r.CostComponents {
+		for _, i := range c.PricingIssues {
+			issues = append(issues, PricingIssue{
+				ProjectName:   projectName,
+				ResourceName:  resourceName,
+				SubResource:   strings.Join(path, "."),
+				CostComponent: c.Name,
+				Type:          string(i.Type),
+				Matches:       i.Matches,
+				Message:       i.Message(),
+			})
+		}
+	}
+
+	for _, s := range r.SubResources {
+		subPath := append(append([]string{}, path...), s.Name)
+		issues = append(issues, resourcePricingIssues(projectName, resourceName, subPath, s)...)
+	}
+
+	return issues
+}
+
+// PricingIssuesMessage returns a summary of the pricing issues that can be
+// shown to the user.
+func PricingIssuesMessage(issues []PricingIssue) string {
+	noun := "cost components"
+	if len(issues) == 1 {
+		noun = "cost component"
+	}
+
+	lines := []string{fmt.Sprintf("Strict pricing failed, %d %s didn't match exactly one price:", len(issues), noun)}
+
+	for _, i := range issues {
+		name := i.ResourceName
+		if i.SubResource != "" {
+			name += " → " + i.SubResource
+		}
+
+		lines = append(lines, fmt.Sprintf("  - %s → %s: %s", name, i.CostComponent, i.Message))
+	}
+
+	return strings.Join(lines, "\n")
+}
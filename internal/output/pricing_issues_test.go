@@ -0,0 +1,52 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestBuildPricingIssues(t *testing.T) {
+	storage := &schema.CostComponent{Name: "Storage"}
+	storage.AddPricingIssue(schema.PricingIssueNoProducts, 0)
+
+	instance := &schema.CostComponent{Name: "Instance usage"}
+	instance.AddPricingIssue(schema.PricingIssueMultiplePrices, 2)
+
+	r := &schema.Resource{
+		Name:           "aws_instance.web",
+		CostComponents: []*schema.CostComponent{instance},
+		SubResources: []*schema.Resource{
+			{Name: "root_block_device", CostComponents: []*schema.CostComponent{storage}},
+		},
+	}
+
+	project := &schema.Project{
+		Name:          "my-project",
+		Resources:     []*schema.Resource{r},
+		PastResources: []*schema.Resource{r},
+	}
+
+	issues := BuildPricingIssues([]*schema.Project{project})
+
+	assert.Equal(t, []PricingIssue{
+		{
+			ProjectName:   "my-project",
+			ResourceName:  "aws_instance.web",
+			CostComponent: "Instance usage",
+			Type:          "multiple_prices",
+			Matches:       2,
+			Message:       "multiple prices found, used the first price",
+		},
+		{
+			ProjectName:   "my-project",
+			ResourceName:  "aws_instance.web",
+			SubResource:   "root_block_device",
+			CostComponent: "Storage",
+			Type:          "no_products",
+			Matches:       0,
+			Message:       "no products found",
+		},
+	}, issues)
+}
@@ -0,0 +1,202 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var prometheusLabelNameRegex = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+type prometheusMetric struct {
+	name string
+	help string
+	rows []prometheusRow
+}
+
+type prometheusTag struct {
+	key   string
+	label string
+}
+
+type prometheusRow struct {
+	labels [][2]string
+	value  decimal.Decimal
+}
+
+// ToPrometheus returns the costs in the Prometheus text exposition format, so
+// they can be pushed to a Pushgateway or served by the exporter. Each resource
+// has gauges labelled by its project, type, address, provider and the tags in
+// opts.MetricTags. It returns an error if the same series would be written
+// twice, since Prometheus rejects the whole scrape.
+func ToPrometheus(out Root, opts Options) ([]byte, error) {
+	tags, err := prometheusTags(opts.MetricTags)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceCost := &prometheusMetric{
+		name: "infracost_monthly_cost",
+		help: "Monthly cost of the resource",
+	}
+	resourcePastCost := &prometheusMetric{
+		name: "infracost_past_monthly_cost",
+		help: "Monthly cost of the resource before the changes",
+	}
+	resourceDiff := &prometheusMetric{
+		name: "infracost_monthly_cost_diff",
+		help: "Change in the monthly cost of the resource",
+	}
+	projectCost := &prometheusMetric{
+		name: "infracost_project_monthly_cost",
+		help: "Total monthly cost of the project",
+	}
+	projectDiff := &prometheusMetric{
+		name: "infracost_project_monthly_cost_diff",
+		help: "Change in the total monthly cost of the project",
+	}
+	totalCost := &prometheusMetric{
+		name: "infracost_total_monthly_cost",
+		help: "Total monthly cost of all projects",
+	}
+	violationActual := &prometheusMetric{
+		name: "infracost_budget_violation_actual",
+		help: "Monthly cost, increase or percent increase that violated the budget policy",
+	}
+	violationLimit := &prometheusMetric{
+		name: "infracost_budget_violation_limit",
+		help: "Limit of the budget policy that was violated",
+	}
+
+	currency := currencyOrUSD(out.Currency)
+
+	for _, p := range out.Projects {
+		project := p.Label(opts.DashboardEnabled)
+		projectLabels := [][2]string{{"project", project}, {"currency", currency}}
+
+		if p.Breakdown != nil {
+			addResourceRows(resourceCost, p.Breakdown.Resources, project, currency, tags)
+
+			if p.Breakdown.TotalMonthlyCost != nil {
+				projectCost.rows = append(projectCost.rows, prometheusRow{labels: projectLabels, value: *p.Breakdown.TotalMonthlyCost})
+			}
+		}
+
+		if p.PastBreakdown != nil {
+			addResourceRows(resourcePastCost, p.PastBreakdown.Resources, project, currency, tags)
+		}
+
+		if p.Diff != nil {
+			addResourceRows(resourceDiff, p.Diff.Resources, project, currency, tags)
+
+			if p.Diff.TotalMonthlyCost != nil {
+				projectDiff.rows = append(projectDiff.rows, prometheusRow{labels: projectLabels, value: *p.Diff.TotalMonthlyCost})
+			}
+		}
+	}
+
+	if out.TotalMonthlyCost != nil {
+		totalCost.rows = append(totalCost.rows, prometheusRow{labels: [][2]string{{"currency", currency}}, value: *out.TotalMonthlyCost})
+	}
+
+	for _, v := range out.PolicyViolations {
+		labels := [][2]string{{"rule", v.Rule}, {"project", v.Project}, {"check", v.Check}, {"currency", currency}}
+
+		violationActual.rows = append(violationActual.rows, prometheusRow{labels: labels, value: v.Actual})
+		violationLimit.rows = append(violationLimit.rows, prometheusRow{labels: labels, value: v.Limit})
+	}
+
+	var buf bytes.Buffer
+
+	for _, m := range []*prometheusMetric{resourceCost, resourcePastCost, resourceDiff, projectCost, projectDiff, totalCost, violationActual, violationLimit} {
+		if len(m.rows) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(&buf, "# TYPE %s gauge\n", m.name)
+
+		// Prometheus rejects the whole scrape if a series is repeated, which
+		// happens when files for projects with the same name are combined
+		seen := make(map[string]bool, len(m.rows))
+
+		for _, row := range m.rows {
+			labels := make([]string, 0, len(row.labels))
+			for _, l := range row.labels {
+				labels = append(labels, fmt.Sprintf("%s=\"%s\"", l[0], escapePrometheusLabelValue(l[1])))
+			}
+
+			series := fmt.Sprintf("%s{%s}", m.name, strings.Join(labels, ","))
+			if seen[series] {
+				return nil, fmt.Errorf("Metric %s is repeated, projects in different files must have different names to be combined", series)
+			}
+			seen[series] = true
+
+			fmt.Fprintf(&buf, "%s %s\n", series, row.value.String())
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func addResourceRows(m *prometheusMetric, resources []Resource, project string, currency string, tags []prometheusTag) {
+	for _, r := range resources {
+		if r.MonthlyCost == nil {
+			continue
+		}
+
+		labels := [][2]string{
+			{"project", project},
+			{"resource_type", ResourceType(r.Name)},
+			{"resource", r.Name},
+			{"provider", resourceProvider(r.Name)},
+			{"currency", currency},
+		}
+
+		for _, t := range tags {
+			labels = append(labels, [2]string{t.label, r.Tags[t.key]})
+		}
+
+		m.rows = append(m.rows, prometheusRow{labels: labels, value: *r.MonthlyCost})
+	}
+}
+
+// prometheusTags returns the label names of the tag keys. Repeated keys are
+// only added once. Different keys that map to the same label name, e.g.
+// cost-center and cost.center, are rejected since Prometheus doesn't allow
+// duplicate label names.
+func prometheusTags(keys []string) ([]prometheusTag, error) {
+	tags := make([]prometheusTag, 0, len(keys))
+	keysByLabel := make(map[string]string, len(keys))
+
+	for _, k := range keys {
+		label := prometheusTagLabelName(k)
+
+		if existing, ok := keysByLabel[label]; ok {
+			if existing == k {
+				continue
+			}
+
+			return nil, fmt.Errorf("Metric tags %s and %s both have the label name %s, only one of them can be used", existing, k, label)
+		}
+
+		keysByLabel[label] = k
+		tags = append(tags, prometheusTag{key: k, label: label})
+	}
+
+	return tags, nil
+}
+
+// prometheusTagLabelName returns the label name for a tag, e.g. tag_cost_center
+// for cost-center, since label names can only contain letters, digits and
+// underscores.
+func prometheusTagLabelName(key string) string {
+	return "tag_" + prometheusLabelNameRegex.ReplaceAllString(key, "_")
+}
+
+func escapePrometheusLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
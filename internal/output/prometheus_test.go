@@ -0,0 +1,100 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToPrometheus(t *testing.T) {
+	root := Root{
+		Currency:         "EUR",
+		TotalMonthlyCost: decimalPtr(decimal.NewFromInt(120)),
+		Projects: []Project{
+			{
+				Name: "org/prod",
+				PastBreakdown: &Breakdown{
+					TotalMonthlyCost: decimalPtr(decimal.NewFromInt(100)),
+					Resources: []Resource{
+						{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(100))},
+					},
+				},
+				Breakdown: &Breakdown{
+					TotalMonthlyCost: decimalPtr(decimal.NewFromInt(120)),
+					Resources: []Resource{
+						{Name: "aws_instance.web", Tags: map[string]string{"cost-center": "a\"b"}, MonthlyCost: decimalPtr(decimal.NewFromFloat(120))},
+						{Name: "aws_lambda_function.fn"},
+					},
+				},
+				Diff: &Breakdown{
+					TotalMonthlyCost: decimalPtr(decimal.NewFromInt(20)),
+					Resources: []Resource{
+						{Name: "aws_instance.web", Tags: map[string]string{"cost-center": "a\"b"}, MonthlyCost: decimalPtr(decimal.NewFromInt(20))},
+					},
+				},
+			},
+		},
+		PolicyViolations: []PolicyViolation{
+			{Rule: "prod", Project: "org/prod", Check: "max_monthly_increase", Limit: decimal.NewFromInt(10), Actual: decimal.NewFromInt(20)},
+		},
+	}
+
+	b, err := ToPrometheus(root, Options{MetricTags: []string{"cost-center"}})
+	assert.NoError(t, err)
+
+	expected := `# HELP infracost_monthly_cost Monthly cost of the resource
+# TYPE infracost_monthly_cost gauge
+infracost_monthly_cost{project="org/prod",resource_type="aws_instance",resource="aws_instance.web",provider="aws",currency="EUR",tag_cost_center="a\"b"} 120
+# HELP infracost_past_monthly_cost Monthly cost of the resource before the changes
+# TYPE infracost_past_monthly_cost gauge
+infracost_past_monthly_cost{project="org/prod",resource_type="aws_instance",resource="aws_instance.web",provider="aws",currency="EUR",tag_cost_center=""} 100
+# HELP infracost_monthly_cost_diff Change in the monthly cost of the resource
+# TYPE infracost_monthly_cost_diff gauge
+infracost_monthly_cost_diff{project="org/prod",resource_type="aws_instance",resource="aws_instance.web",provider="aws",currency="EUR",tag_cost_center="a\"b"} 20
+# HELP infracost_project_monthly_cost Total monthly cost of the project
+# TYPE infracost_project_monthly_cost gauge
+infracost_project_monthly_cost{project="org/prod",currency="EUR"} 120
+# HELP infracost_project_monthly_cost_diff Change in the total monthly cost of the project
+# TYPE infracost_project_monthly_cost_diff gauge
+infracost_project_monthly_cost_diff{project="org/prod",currency="EUR"} 20
+# HELP infracost_total_monthly_cost Total monthly cost of all projects
+# TYPE infracost_total_monthly_cost gauge
+infracost_total_monthly_cost{currency="EUR"} 120
+# HELP infracost_budget_violation_actual Monthly cost, increase or percent increase that violated the budget policy
+# TYPE infracost_budget_violation_actual gauge
+infracost_budget_violation_actual{rule="prod",project="org/prod",check="max_monthly_increase",currency="EUR"} 20
+# HELP infracost_budget_violation_limit Limit of the budget policy that was violated
+# TYPE infracost_budget_violation_limit gauge
+infracost_budget_violation_limit{rule="prod",project="org/prod",check="max_monthly_increase",currency="EUR"} 10
+`
+
+	assert.Equal(t, expected, string(b))
+}
+
+func TestToPrometheusDuplicateProjects(t *testing.T) {
+	project := Project{
+		Name: "org/prod",
+		Breakdown: &Breakdown{
+			TotalMonthlyCost: decimalPtr(decimal.NewFromInt(10)),
+			Resources: []Resource{
+				{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(10))},
+			},
+		},
+	}
+
+	_, err := ToPrometheus(Root{Projects: []Project{project, project}}, Options{})
+	assert.EqualError(t, err, `Metric infracost_monthly_cost{project="org/prod",resource_type="aws_instance",resource="aws_instance.web",provider="aws",currency="USD"} is repeated, projects in different files must have different names to be combined`)
+}
+
+func TestPrometheusTags(t *testing.T) {
+	tags, err := prometheusTags([]string{"team", "cost-center", "team"})
+	assert.NoError(t, err)
+	assert.Equal(t, []prometheusTag{{key: "team", label: "tag_team"}, {key: "cost-center", label: "tag_cost_center"}}, tags)
+
+	_, err = prometheusTags([]string{"cost-center", "cost.center"})
+	assert.EqualError(t, err, "Metric tags cost-center and cost.center both have the label name tag_cost_center, only one of them can be used")
+
+	_, err = ToPrometheus(Root{}, Options{MetricTags: []string{"cost-center", "cost_center"}})
+	assert.Error(t, err)
+}
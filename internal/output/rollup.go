@@ -0,0 +1,156 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rollup is the subtotal of the resource costs grouped by a dimension, such
+// as a tag or the resource type. Resources without a value for the dimension
+// are grouped under an empty name.
+type Rollup struct {
+	GroupBy string        `json:"groupBy"`
+	Groups  []RollupGroup `json:"groups"`
+}
+
+type RollupGroup struct {
+	Name             string           `json:"name"`
+	ResourceCount    int              `json:"resourceCount"`
+	TotalHourlyCost  *decimal.Decimal `json:"totalHourlyCost"`
+	TotalMonthlyCost *decimal.Decimal `json:"totalMonthlyCost"`
+}
+
+var groupByDimensions = []string{"type", "module", "provider", "region"}
+
+// ValidateGroupBy checks each group by is one of the dimensions or a tag in
+// the form tag:<key>.
+func ValidateGroupBy(groupBy []string) error {
+	for _, g := range groupBy {
+		if strings.HasPrefix(g, "tag:") {
+			if strings.TrimPrefix(g, "tag:") == "" {
+				return fmt.Errorf("Invalid group by %s. Expected a tag key, e.g. tag:team", g)
+			}
+			continue
+		}
+
+		if !contains(groupByDimensions, g) {
+			return fmt.Errorf("Invalid group by %s. Expected tag:<key>, %s", g, strings.Join(groupByDimensions, ", "))
+		}
+	}
+
+	return nil
+}
+
+// BuildRollups returns a rollup of the resource costs across all the
+// projects for each group by. The groups are sorted by the most expensive
+// first.
+func BuildRollups(projects []Project, groupBy []string) []Rollup {
+	if len(groupBy) == 0 {
+		return nil
+	}
+
+	rollups := make([]Rollup, 0, len(groupBy))
+
+	for _, g := range groupBy {
+		groups := make(map[string]*RollupGroup)
+
+		for _, p := range projects {
+			if p.Breakdown == nil {
+				continue
+			}
+
+			for _, r := range p.Breakdown.Resources {
+				name := groupName(r, g)
+
+				group, ok := groups[name]
+				if !ok {
+					group = &RollupGroup{Name: name}
+					groups[name] = group
+				}
+
+				group.ResourceCount++
+				group.TotalHourlyCost = addDecimalPtrs(group.TotalHourlyCost, r.HourlyCost)
+				group.TotalMonthlyCost = addDecimalPtrs(group.TotalMonthlyCost, r.MonthlyCost)
+			}
+		}
+
+		rollup := Rollup{GroupBy: g, Groups: make([]RollupGroup, 0, len(groups))}
+		for _, group := range groups {
+			rollup.Groups = append(rollup.Groups, *group)
+		}
+
+		sort.Slice(rollup.Groups, func(i, j int) bool {
+			a := decimalOrZero(rollup.Groups[i].TotalMonthlyCost)
+			b := decimalOrZero(rollup.Groups[j].TotalMonthlyCost)
+			if a.Equal(b) {
+				return rollup.Groups[i].Name < rollup.Groups[j].Name
+			}
+			return a.GreaterThan(b)
+		})
+
+		rollups = append(rollups, rollup)
+	}
+
+	return rollups
+}
+
+func groupName(r Resource, groupBy string) string {
+	switch groupBy {
+	case "type":
+		return ResourceType(r.Name)
+	case "module":
+		return resourceModule(r.Name)
+	case "provider":
+		return resourceProvider(r.Name)
+	case "region":
+		return r.Metadata["region"]
+	}
+
+	return r.Tags[strings.TrimPrefix(groupBy, "tag:")]
+}
+
+// resourceModule returns the module path of a resource address without the
+// indexes, e.g. module.app.module.db for module.app["a"].module.db.aws_db_instance.db.
+func resourceModule(address string) string {
+	parts := strings.Split(addressIndexRegex.ReplaceAllString(address, ""), ".")
+
+	n := 2
+	if len(parts) >= 3 && parts[len(parts)-3] == "data" {
+		n = 3
+	}
+
+	if len(parts) <= n {
+		return ""
+	}
+
+	return strings.Join(parts[:len(parts)-n], ".")
+}
+
+// resourceProvider returns the provider from the resource type, e.g. aws for
+// aws_instance.
+func resourceProvider(address string) string {
+	return strings.SplitN(ResourceType(address), "_", 2)[0]
+}
+
+// rollupGroupLabel is the label of the group in the table output.
+func rollupGroupLabel(groupBy string, name string) string {
+	if name != "" {
+		return name
+	}
+
+	if groupBy == "module" {
+		return "(root module)"
+	}
+
+	return "(none)"
+}
+
+func decimalOrZero(d *decimal.Decimal) decimal.Decimal {
+	if d == nil {
+		return decimal.Zero
+	}
+	return *d
+}
@@ -0,0 +1,64 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateGroupBy(t *testing.T) {
+	assert.NoError(t, ValidateGroupBy([]string{"tag:team", "type", "module", "provider", "region"}))
+	assert.EqualError(t, ValidateGroupBy([]string{"tag:"}), "Invalid group by tag:. Expected a tag key, e.g. tag:team")
+	assert.EqualError(t, ValidateGroupBy([]string{"team"}), "Invalid group by team. Expected tag:<key>, type, module, provider, region")
+}
+
+func TestBuildRollups(t *testing.T) {
+	resource := func(name string, team string, monthlyCost *decimal.Decimal) Resource {
+		r := Resource{Name: name, MonthlyCost: monthlyCost, Metadata: map[string]string{"region": "us-east-1"}}
+		if team != "" {
+			r.Tags = map[string]string{"team": team}
+		}
+		return r
+	}
+
+	projects := []Project{
+		{Breakdown: &Breakdown{Resources: []Resource{
+			resource(`module.app["a"].aws_instance.web`, "web", decimalPtr(decimal.NewFromInt(10))),
+			resource("module.app.module.db.aws_db_instance.db", "data", decimalPtr(decimal.NewFromInt(30))),
+		}}},
+		{Breakdown: &Breakdown{Resources: []Resource{
+			resource("aws_instance.api", "web", decimalPtr(decimal.NewFromInt(25))),
+			resource("data.aws_lambda_function.fn", "", nil),
+		}}},
+	}
+
+	rollups := BuildRollups(projects, []string{"tag:team", "module", "provider"})
+
+	assert.Equal(t, []Rollup{
+		{
+			GroupBy: "tag:team",
+			Groups: []RollupGroup{
+				{Name: "web", ResourceCount: 2, TotalMonthlyCost: decimalPtr(decimal.NewFromInt(35))},
+				{Name: "data", ResourceCount: 1, TotalMonthlyCost: decimalPtr(decimal.NewFromInt(30))},
+				{Name: "", ResourceCount: 1},
+			},
+		},
+		{
+			GroupBy: "module",
+			Groups: []RollupGroup{
+				{Name: "module.app.module.db", ResourceCount: 1, TotalMonthlyCost: decimalPtr(decimal.NewFromInt(30))},
+				{Name: "", ResourceCount: 2, TotalMonthlyCost: decimalPtr(decimal.NewFromInt(25))},
+				{Name: "module.app", ResourceCount: 1, TotalMonthlyCost: decimalPtr(decimal.NewFromInt(10))},
+			},
+		},
+		{
+			GroupBy: "provider",
+			Groups: []RollupGroup{
+				{Name: "aws", ResourceCount: 4, TotalMonthlyCost: decimalPtr(decimal.NewFromInt(65))},
+			},
+		},
+	}, rollups)
+
+	assert.Nil(t, BuildRollups(projects, nil))
+}
@@ -0,0 +1,161 @@
+package output
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/infracost/infracost/internal/version"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind"`
+}
+
+// ToSARIF renders the findings as a SARIF log so they can be uploaded to code
+// scanning tools. Results are linked to the Terraform file and line of the
+// resource when it's known, and always have the resource address as a logical
+// location.
+func ToSARIF(out Root, opts Options) ([]byte, error) {
+	rules := make([]sarifRule, 0, len(FindingRules))
+	ruleIndexes := make(map[string]int, len(FindingRules))
+
+	for i, r := range FindingRules {
+		rules = append(rules, sarifRule{
+			ID:                   r.ID,
+			Name:                 r.Name,
+			ShortDescription:     sarifMessage{Text: r.Description},
+			DefaultConfiguration: sarifConfiguration{Level: r.Level},
+		})
+		ruleIndexes[r.ID] = i
+	}
+
+	results := make([]sarifResult, 0)
+
+	for _, f := range BuildFindings(out, opts) {
+		result := sarifResult{
+			RuleID:    f.Rule.ID,
+			RuleIndex: ruleIndexes[f.Rule.ID],
+			Level:     f.Rule.Level,
+			Message:   sarifMessage{Text: f.Message},
+		}
+
+		for _, loc := range f.Locations {
+			l := sarifLocation{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               loc.Resource,
+					FullyQualifiedName: sarifQualifiedName(loc.Project, loc.Resource),
+					Kind:               "resource",
+				}},
+			}
+
+			if loc.SourceLocation != nil && loc.SourceLocation.Filename != "" {
+				l.PhysicalLocation = &sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(loc.SourceLocation.Filename)},
+					Region:           sarifRegion{StartLine: loc.SourceLocation.Line},
+				}
+			}
+
+			result.Locations = append(result.Locations, l)
+		}
+
+		results = append(results, result)
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "Infracost",
+						Version:        version.Version,
+						InformationURI: "https://www.infracost.io",
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+
+	return json.MarshalIndent(log, "", "  ")
+}
+
+func sarifQualifiedName(project string, resource string) string {
+	if project == "" {
+		return resource
+	}
+
+	return project + "/" + resource
+}
@@ -3,6 +3,7 @@ package output
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/infracost/infracost/internal/ui"
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -66,6 +67,31 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 		fmt.Sprintf("%*s ", tableLen-(len(overallTitle)+1), totalOut), // pad based on the last line length
 	)
 
+	for _, rollup := range out.Rollups {
+		s += "\n----------------------------------\n"
+		s += rollupTable(out.Currency, rollup)
+	}
+
+	if out.Commitments != nil {
+		s += "\n----------------------------------\n"
+		s += commitmentSummaryTable(out.Currency, out.Commitments)
+	}
+
+	if out.PriceEffectiveDate != "" {
+		s += "\n----------------------------------\n"
+		s += fmt.Sprintf("Prices as of %s", out.PriceEffectiveDate)
+	}
+
+	if len(out.ExchangeRates) > 0 {
+		s += "\n----------------------------------\n"
+		s += exchangeRatesSummary(out.ExchangeRates)
+	}
+
+	if len(out.PolicyViolations) > 0 {
+		s += "\n----------------------------------\n"
+		s += policyViolationsSummary(out.PolicyViolations)
+	}
+
 	unsupportedMsg := out.unsupportedResourcesMessage(opts.ShowSkipped)
 
 	if hasNilCosts || unsupportedMsg != "" {
@@ -247,3 +273,78 @@ func buildCostComponentRows(t table.Writer, currency string, costComponents []Co
 		}
 	}
 }
+
+func rollupTable(currency string, rollup Rollup) string {
+	t := table.NewWriter()
+	t.Style().Options.DrawBorder = false
+	t.Style().Options.SeparateColumns = false
+	t.Style().Options.SeparateRows = false
+	t.Style().Options.SeparateHeader = false
+	t.Style().Format.Header = text.FormatDefault
+
+	t.AppendHeader(table.Row{
+		ui.UnderlineString("Name"),
+		ui.UnderlineString("Resources"),
+		ui.UnderlineString(formatTitleWithCurrency("Monthly Cost", currency)),
+	})
+
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 1, Align: text.AlignLeft, AlignHeader: text.AlignLeft},
+		{Number: 2, Align: text.AlignRight, AlignHeader: text.AlignRight},
+		{Number: 3, Align: text.AlignRight, AlignHeader: text.AlignRight},
+	})
+
+	for _, g := range rollup.Groups {
+		t.AppendRow(table.Row{
+			rollupGroupLabel(rollup.GroupBy, g.Name),
+			g.ResourceCount,
+			formatCost2DP(currency, g.TotalMonthlyCost),
+		})
+	}
+
+	return fmt.Sprintf("%s\n%s", ui.BoldString("Monthly cost by "+rollup.GroupBy), t.Render())
+}
+
+func commitmentSummaryTable(currency string, summary *CommitmentSummary) string {
+	s := fmt.Sprintf("%s\n", ui.BoldString("Reserved Instance and Savings Plan coverage"))
+
+	for _, c := range summary.Commitments {
+		s += fmt.Sprintf("  %s (%s): %s of %s used (%s%%)\n",
+			c.Name,
+			c.Type,
+			formatCost2DP(currency, &c.MonthlyUsed),
+			formatCost2DP(currency, &c.MonthlyCommitment),
+			c.UtilizationPercent.String(),
+		)
+	}
+
+	s += fmt.Sprintf("Covered spend: %s at on-demand rates, %s with commitments\n",
+		formatCost2DP(currency, &summary.CoveredOnDemandMonthlyCost),
+		formatCost2DP(currency, &summary.CoveredMonthlyCost),
+	)
+	s += fmt.Sprintf("On-demand spend: %s\n", formatCost2DP(currency, &summary.OnDemandMonthlyCost))
+	s += fmt.Sprintf("Coverage: %s%%, saving %s per month", summary.CoveragePercent.String(), formatCost2DP(currency, &summary.MonthlySavings))
+
+	return s
+}
+
+func policyViolationsSummary(violations []PolicyViolation) string {
+	return fmt.Sprintf("%s\n%s", ui.BoldString("Budget policy violations"), PolicyViolationsMessage(violations))
+}
+
+func exchangeRatesSummary(rates []ExchangeRate) string {
+	lines := make([]string, 0, len(rates))
+
+	for _, e := range rates {
+		line := fmt.Sprintf("Converted %s to %s at %s", e.From, e.To, e.Rate.String())
+		if e.Source != "" {
+			line += fmt.Sprintf(" from %s", e.Source)
+		}
+		if !e.Timestamp.IsZero() {
+			line += fmt.Sprintf(" (rates as of %s)", e.Timestamp.UTC().Format(time.RFC3339))
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
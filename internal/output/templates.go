@@ -68,6 +68,12 @@ tr.resource.top-level {
   color: #ffffff;
 }
 
+tr.resource.top-level a.location {
+  color: #e5e7eb;
+  font-size: 0.75rem;
+  margin-left: 0.5rem;
+}
+
 tr.tags {
   background-color: #6b7280;
   color: #ffffff;
@@ -145,6 +151,9 @@ iVBORw0KGgoAAAANSUhEUgAAAMAAAADACAMAAABlApw1AAAABGdBTUEAALGPC/xhBQAAAAFzUkdCAK7O
       {{if gt .Indent 1}}{{repeat (int (add .Indent -1)) "&nbsp;&nbsp;&nbsp;&nbsp;" | safeHTML}}{{end}}
       {{if gt .Indent 0}}<span class="arrow">&#8627;</span>{{end}}
       {{.Resource.Name}}
+      {{- if eq .Indent 0}}{{with .Resource.SourceLocation}}{{if .Filename}}
+      <a class="location" href="{{.Filename}}">{{.String}}</a>
+      {{- end}}{{end}}{{end}}
     </td>
     {{template "emptyTableRows" dict "Fields" $fields}}
   </tr>
@@ -278,6 +287,17 @@ iVBORw0KGgoAAAANSUhEUgAAAMAAAADACAMAAABlApw1AAAABGdBTUEAALGPC/xhBQAAAAFzUkdCAK7O
       </tbody>
     </table>
 
+    {{- if .Root.PolicyViolations}}
+      <div class="warnings policy-violations">
+        <p><strong>Budget policy violations</strong></p>
+        <ul>
+          {{range .Root.PolicyViolations}}
+            <li>{{.Rule}}: {{.Message}}</li>
+          {{end}}
+        </ul>
+      </div>
+    {{- end}}
+
     <div class="warnings">
       <p>{{.UnsupportedResourcesMessage | replaceNewLines}}</p>
     </div>
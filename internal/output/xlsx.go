@@ -0,0 +1,170 @@
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// xlsxSheetData is a sheet of the workbook.
+type xlsxSheetData struct {
+	name    string
+	columns []flatColumn
+	rows    [][]string
+}
+
+// ToXLSX renders the same rows as ToCSV as an Excel workbook. The cost
+// components are on the first sheet and the budget policy violations, if
+// there are any, on a second sheet. Prices, quantities and costs are written
+// as numbers.
+func ToXLSX(out Root, opts Options) ([]byte, error) {
+	columns, rows := flatRows(out, opts)
+
+	sheets := []xlsxSheetData{{name: "Cost components", columns: columns, rows: rows}}
+	if len(out.PolicyViolations) > 0 {
+		sheets = append(sheets, xlsxSheetData{name: "Policy violations", columns: flatViolationColumns, rows: flatViolationRows(out.PolicyViolations)})
+	}
+
+	files := xlsxWorkbookFiles(sheets)
+	for i, sheet := range sheets {
+		files = append(files, xlsxFile{
+			name:    fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1),
+			content: xlsxSheet(sheet.columns, sheet.rows),
+		})
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := w.Write([]byte(f.content)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type xlsxFile struct {
+	name    string
+	content string
+}
+
+// xlsxWorkbookFiles returns the files that describe the workbook and its
+// sheets.
+func xlsxWorkbookFiles(sheets []xlsxSheetData) []xlsxFile {
+	var overrides, sheetElems, rels strings.Builder
+
+	for i, sheet := range sheets {
+		n := i + 1
+
+		overrides.WriteString(fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`+"\n", n))
+
+		var name bytes.Buffer
+		_ = xml.EscapeText(&name, []byte(sheet.name))
+		sheetElems.WriteString(fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, name.String(), n, n))
+
+		rels.WriteString(fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`+"\n", n, n))
+	}
+
+	return []xlsxFile{
+		{
+			name: "[Content_Types].xml",
+			content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+` + overrides.String() + `</Types>`,
+		},
+		{
+			name: "_rels/.rels",
+			content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`,
+		},
+		{
+			name: "xl/workbook.xml",
+			content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets>` + sheetElems.String() + `</sheets>
+</workbook>`,
+		},
+		{
+			name: "xl/_rels/workbook.xml.rels",
+			content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+` + rels.String() + `</Relationships>`,
+		},
+	}
+}
+
+func xlsxSheet(columns []flatColumn, rows [][]string) string {
+	var b strings.Builder
+
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	b.WriteString(xlsxRow(1, flatHeader(columns), nil))
+
+	for i, row := range rows {
+		b.WriteString(xlsxRow(i+2, row, columns))
+	}
+
+	b.WriteString(`</sheetData></worksheet>`)
+
+	return b.String()
+}
+
+// xlsxRow writes a row of cells. Cells in numeric columns are written as
+// numbers, everything else as inline strings. Empty cells are omitted.
+func xlsxRow(n int, values []string, columns []flatColumn) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf(`<row r="%d">`, n))
+
+	for i, v := range values {
+		if v == "" {
+			continue
+		}
+
+		ref := fmt.Sprintf("%s%d", xlsxColumnName(i), n)
+
+		if columns != nil && columns[i].Numeric {
+			b.WriteString(fmt.Sprintf(`<c r="%s"><v>%s</v></c>`, ref, v))
+			continue
+		}
+
+		var escaped bytes.Buffer
+		_ = xml.EscapeText(&escaped, []byte(v))
+		b.WriteString(fmt.Sprintf(`<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, escaped.String()))
+	}
+
+	b.WriteString(`</row>`)
+
+	return b.String()
+}
+
+// xlsxColumnName returns the spreadsheet name of the zero-indexed column,
+// e.g. A, B, ..., Z, AA.
+func xlsxColumnName(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+
+	return name
+}
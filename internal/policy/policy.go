@@ -0,0 +1,186 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/infracost/infracost/internal/output"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/pkg/errors"
+)
+
+// Query is the Rego query that's evaluated. Policies are written in the
+// infracost package and add results to the deny and warn sets.
+const Query = "data.infracost"
+
+// Result is a deny or warn result from a policy. Policies can return a
+// message string, or an object with a msg and the resource address it's for.
+type Result struct {
+	Message  string `json:"msg"`
+	Resource string `json:"resource,omitempty"`
+	Project  string `json:"project,omitempty"`
+}
+
+// Results are the results of evaluating the policies.
+type Results struct {
+	Deny []Result `json:"deny"`
+	Warn []Result `json:"warn"`
+}
+
+// Evaluator evaluates the Rego policies loaded from files or directories.
+type Evaluator struct {
+	query rego.PreparedEvalQuery
+}
+
+// NewEvaluator loads and compiles the .rego files from the paths.
+func NewEvaluator(ctx context.Context, paths []string) (*Evaluator, error) {
+	r := rego.New(
+		rego.Query(Query),
+		rego.Load(paths, nil),
+	)
+
+	query, err := r.PrepareForEval(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error loading policies")
+	}
+
+	return &Evaluator{query: query}, nil
+}
+
+// Eval evaluates the policies against the Infracost output.
+func (e *Evaluator) Eval(ctx context.Context, out output.Root) (*Results, error) {
+	input, err := Input(out)
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := e.query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error evaluating policies")
+	}
+
+	results := &Results{
+		Deny: make([]Result, 0),
+		Warn: make([]Result, 0),
+	}
+
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return results, nil
+	}
+
+	doc, ok := rs[0].Expressions[0].Value.(map[string]interface{})
+	if !ok {
+		return results, nil
+	}
+
+	results.Deny, err = parseResults(doc["deny"])
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid deny result")
+	}
+
+	results.Warn, err = parseResults(doc["warn"])
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid warn result")
+	}
+
+	return results, nil
+}
+
+// Input returns the policy input for the Infracost output. It's the same as
+// the Infracost JSON, with the resource type added to each resource and
+// sub-resource so policies don't need to parse the addresses. Sub-resources
+// whose names aren't resource addresses, e.g. root_block_device, have the
+// type of the resource they're part of.
+func Input(out output.Root) (map[string]interface{}, error) {
+	b, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+
+	var input map[string]interface{}
+	err = json.Unmarshal(b, &input)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, _ := input["projects"].([]interface{})
+	for _, p := range projects {
+		project, _ := p.(map[string]interface{})
+
+		for _, key := range []string{"pastBreakdown", "breakdown", "diff"} {
+			breakdown, _ := project[key].(map[string]interface{})
+			addResourceTypes(breakdown["resources"], "")
+		}
+	}
+
+	return input, nil
+}
+
+func addResourceTypes(v interface{}, parentType string) {
+	resources, _ := v.([]interface{})
+
+	for _, r := range resources {
+		resource, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _ := resource["name"].(string)
+		resourceType := output.ResourceType(name)
+		if resourceType == "" {
+			resourceType = parentType
+		}
+
+		resource["resourceType"] = resourceType
+		addResourceTypes(resource["subresources"], resourceType)
+	}
+}
+
+// parseResults parses the results from a deny or warn set. They are sorted
+// so the output is stable.
+func parseResults(v interface{}) ([]Result, error) {
+	results := make([]Result, 0)
+
+	if v == nil {
+		return results, nil
+	}
+
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a set, got %T", v)
+	}
+
+	for _, item := range items {
+		switch i := item.(type) {
+		case string:
+			results = append(results, Result{Message: i})
+		case map[string]interface{}:
+			r := Result{}
+			r.Message, _ = i["msg"].(string)
+			r.Resource, _ = i["resource"].(string)
+			r.Project, _ = i["project"].(string)
+
+			if r.Message == "" {
+				return nil, errors.New("expected an object with a msg")
+			}
+
+			results = append(results, r)
+		default:
+			return nil, fmt.Errorf("expected a string or an object with a msg, got %T", item)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Project != results[j].Project {
+			return results[i].Project < results[j].Project
+		}
+		if results[i].Resource != results[j].Resource {
+			return results[i].Resource < results[j].Resource
+		}
+		return results[i].Message < results[j].Message
+	})
+
+	return results, nil
+}
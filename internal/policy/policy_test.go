@@ -0,0 +1,133 @@
+package policy
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/output"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePolicy(t *testing.T, policy string) string {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.rego")
+	require.NoError(t, ioutil.WriteFile(path, []byte(policy), 0600))
+	return dir
+}
+
+func testRoot() output.Root {
+	monthlyCost := decimal.NewFromInt(600)
+	return output.Root{
+		Projects: []output.Project{
+			{
+				Name: "dev",
+				Breakdown: &output.Breakdown{
+					Resources:        []output.Resource{{Name: `module.app.aws_ebs_volume.data["a"]`, MonthlyCost: &monthlyCost}},
+					TotalMonthlyCost: &monthlyCost,
+				},
+			},
+		},
+	}
+}
+
+func TestEval(t *testing.T) {
+	dir := writePolicy(t, `package infracost
+
+deny[{"msg": "gp2 volume", "resource": r.name}] {
+	r := input.projects[_].breakdown.resources[_]
+	r.resourceType == "aws_ebs_volume"
+}
+
+deny["plain message"] {
+	true
+}
+
+warn[{"msg": msg, "project": p.name}] {
+	p := input.projects[_]
+	to_number(p.breakdown.totalMonthlyCost) > 200
+	msg := "over 200"
+}
+`)
+
+	e, err := NewEvaluator(context.Background(), []string{dir})
+	require.NoError(t, err)
+
+	results, err := e.Eval(context.Background(), testRoot())
+	require.NoError(t, err)
+
+	assert.Equal(t, &Results{
+		Deny: []Result{
+			{Message: "plain message"},
+			{Message: "gp2 volume", Resource: `module.app.aws_ebs_volume.data["a"]`},
+		},
+		Warn: []Result{
+			{Message: "over 200", Project: "dev"},
+		},
+	}, results)
+}
+
+func TestEvalInvalidResult(t *testing.T) {
+	dir := writePolicy(t, `package infracost
+
+deny[1] {
+	true
+}
+`)
+
+	e, err := NewEvaluator(context.Background(), []string{dir})
+	require.NoError(t, err)
+
+	_, err = e.Eval(context.Background(), testRoot())
+	assert.EqualError(t, err, "Invalid deny result: expected a string or an object with a msg, got json.Number")
+}
+
+func TestNewEvaluatorInvalidPolicy(t *testing.T) {
+	dir := writePolicy(t, "package infracost\n\ndeny[msg] {\n")
+
+	_, err := NewEvaluator(context.Background(), []string{dir})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Error loading policies")
+}
+
+func TestInputSubResourceTypes(t *testing.T) {
+	root := output.Root{
+		Projects: []output.Project{
+			{
+				Name: "dev",
+				Breakdown: &output.Breakdown{
+					Resources: []output.Resource{
+						{
+							Name: "aws_instance.web",
+							SubResources: []output.Resource{
+								{Name: "root_block_device"},
+								{
+									Name:         "aws_launch_template.lt",
+									SubResources: []output.Resource{{Name: "ebs_block_device[0]"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	input, err := Input(root)
+	require.NoError(t, err)
+
+	project := input["projects"].([]interface{})[0].(map[string]interface{})
+	resource := project["breakdown"].(map[string]interface{})["resources"].([]interface{})[0].(map[string]interface{})
+	subResources := resource["subresources"].([]interface{})
+	rootBlockDevice := subResources[0].(map[string]interface{})
+	launchTemplate := subResources[1].(map[string]interface{})
+	ebsBlockDevice := launchTemplate["subresources"].([]interface{})[0].(map[string]interface{})
+
+	assert.Equal(t, "aws_instance", resource["resourceType"])
+	assert.Equal(t, "aws_instance", rootBlockDevice["resourceType"])
+	assert.Equal(t, "aws_launch_template", launchTemplate["resourceType"])
+	assert.Equal(t, "aws_launch_template", ebsBlockDevice["resourceType"])
+}
@@ -0,0 +1,303 @@
+package prices
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/util"
+	"github.com/infracost/infracost/internal/version"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+const minOverridesFileVersion = "0.1"
+const maxOverridesFileVersion = "0.1"
+
+var overrideRegexCache sync.Map
+
+// PriceOverrides replaces the list prices from the Cloud Pricing API with
+// negotiated prices. For each cost component the first matching price rule is
+// used. If no price rule matches, the first matching discount rule is applied
+// to the list price. Credits are then taken off the monthly costs of the
+// matching cost components until they are used up.
+type PriceOverrides struct {
+	Version   string          `yaml:"version"`
+	Prices    []*PriceRule    `yaml:"prices"`
+	Discounts []*DiscountRule `yaml:"discounts"`
+	Credits   []*CreditRule   `yaml:"credits"`
+}
+
+// OverrideMatcher selects the cost components a rule applies to. Empty fields
+// match everything. String fields can also be regexes in the /pattern/flags
+// format, e.g. /^Instance usage/i.
+type OverrideMatcher struct {
+	ResourceType  string `yaml:"resource_type,omitempty"`
+	CostComponent string `yaml:"cost_component,omitempty"`
+	PriceHash     string `yaml:"price_hash,omitempty"`
+	Provider      string `yaml:"provider,omitempty"`
+	Service       string `yaml:"service,omitempty"`
+	Region        string `yaml:"region,omitempty"`
+}
+
+// PriceRule sets the price of the matching cost components. The price is per
+// unit shown in the output, in the currency prices are fetched in. This is USD
+// when the output is converted using exchange rates.
+type PriceRule struct {
+	OverrideMatcher `yaml:",inline"`
+	Price           *float64 `yaml:"price"`
+}
+
+// DiscountRule takes a percentage off the list price of the matching cost
+// components.
+type DiscountRule struct {
+	OverrideMatcher `yaml:",inline"`
+	Percent         float64 `yaml:"percent"`
+}
+
+// CreditRule is a monthly amount that is taken off the costs of the matching
+// cost components.
+type CreditRule struct {
+	OverrideMatcher `yaml:",inline"`
+	Name            string  `yaml:"name"`
+	MonthlyAmount   float64 `yaml:"monthly_amount"`
+}
+
+func LoadPriceOverrides(path string) (*PriceOverrides, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading price overrides file")
+	}
+
+	var o PriceOverrides
+	err = yaml.Unmarshal(data, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing price overrides file")
+	}
+
+	if !version.IsSupportedFileVersion(o.Version, minOverridesFileVersion, maxOverridesFileVersion) {
+		return nil, fmt.Errorf("Invalid price overrides file version. Supported versions are %s ≤ x ≤ %s", minOverridesFileVersion, maxOverridesFileVersion)
+	}
+
+	for i, r := range o.Prices {
+		if r.Price == nil {
+			return nil, fmt.Errorf("Invalid price overrides file: prices[%d] is missing a price", i)
+		}
+	}
+
+	for i, r := range o.Discounts {
+		if r.Percent < 0 || r.Percent > 100 {
+			return nil, fmt.Errorf("Invalid price overrides file: discounts[%d] percent must be between 0 and 100", i)
+		}
+	}
+
+	matchers := make([]OverrideMatcher, 0)
+	for _, r := range o.Prices {
+		matchers = append(matchers, r.OverrideMatcher)
+	}
+	for _, r := range o.Discounts {
+		matchers = append(matchers, r.OverrideMatcher)
+	}
+	for _, r := range o.Credits {
+		matchers = append(matchers, r.OverrideMatcher)
+	}
+
+	for _, m := range matchers {
+		for _, v := range []string{m.ResourceType, m.CostComponent, m.PriceHash, m.Provider, m.Service, m.Region} {
+			if _, err := compileOverrideRegex(v); err != nil {
+				return nil, errors.Wrapf(err, "Invalid price overrides file: invalid regex %s", v)
+			}
+		}
+	}
+
+	return &o, nil
+}
+
+// Apply applies the price and discount overrides to the resources of the
+// projects. The prices must have already been populated.
+func (o *PriceOverrides) Apply(projects []*schema.Project) {
+	for _, project := range projects {
+		for _, resources := range [][]*schema.Resource{project.PastResources, project.Resources} {
+			for _, oc := range overrideComponents(resources) {
+				o.applyPrice(oc)
+			}
+		}
+	}
+}
+
+// ApplyCredits takes the credits off the costs of the resources of the
+// projects. It should be run after any commitments have been applied so the
+// credits are only used for the cost that isn't covered by them.
+func (o *PriceOverrides) ApplyCredits(projects []*schema.Project) {
+	for _, project := range projects {
+		for _, resources := range [][]*schema.Resource{project.PastResources, project.Resources} {
+			o.applyCredits(overrideComponents(resources))
+		}
+	}
+}
+
+type overrideComponent struct {
+	resourceType string
+	resource     *schema.Resource
+	component    *schema.CostComponent
+}
+
+// overrideComponents returns all the cost components of the resources, sorted
+// by resource name so credits are always used up in the same order.
+func overrideComponents(resources []*schema.Resource) []overrideComponent {
+	sorted := append([]*schema.Resource{}, resources...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	components := make([]overrideComponent, 0)
+
+	for _, r := range sorted {
+		if r.IsSkipped {
+			continue
+		}
+
+		for _, c := range r.CostComponents {
+			components = append(components, overrideComponent{r.ResourceType, r, c})
+		}
+
+		for _, s := range r.FlattenedSubResources() {
+			for _, c := range s.CostComponents {
+				components = append(components, overrideComponent{r.ResourceType, s, c})
+			}
+		}
+	}
+
+	return components
+}
+
+func (o *PriceOverrides) applyPrice(oc overrideComponent) {
+	c := oc.component
+
+	for _, r := range o.Prices {
+		if !r.matches(oc) {
+			continue
+		}
+
+		price := decimal.NewFromFloat(*r.Price)
+		if !c.UnitMultiplier.IsZero() {
+			price = price.Div(c.UnitMultiplier)
+		}
+
+		log.Debugf("Overriding price of %s %s with %s", oc.resource.Name, c.Name, price)
+		c.OverridePrice(price)
+		return
+	}
+
+	for _, r := range o.Discounts {
+		if !r.matches(oc) {
+			continue
+		}
+
+		multiplier := decimal.NewFromInt(1).Sub(decimal.NewFromFloat(r.Percent).Div(decimal.NewFromInt(100)))
+
+		log.Debugf("Applying %v%% discount to %s %s", r.Percent, oc.resource.Name, c.Name)
+		c.OverridePrice(c.Price().Mul(multiplier))
+		return
+	}
+}
+
+func (o *PriceOverrides) applyCredits(components []overrideComponent) {
+	if len(o.Credits) == 0 {
+		return
+	}
+
+	for _, oc := range components {
+		oc.component.CalculateCosts()
+	}
+
+	for _, r := range o.Credits {
+		remaining := decimal.NewFromFloat(r.MonthlyAmount)
+
+		for _, oc := range components {
+			if !remaining.IsPositive() {
+				break
+			}
+
+			c := oc.component
+			if !r.matches(oc) || c.MonthlyCost == nil {
+				continue
+			}
+
+			available := c.MonthlyCost.Sub(c.MonthlyCreditAmount())
+			if !available.IsPositive() {
+				continue
+			}
+
+			credit := decimal.Min(available, remaining)
+			remaining = remaining.Sub(credit)
+
+			log.Debugf("Applying %s of credit %s to %s %s", credit, r.Name, oc.resource.Name, c.Name)
+			c.AddMonthlyCredit(credit)
+		}
+	}
+}
+
+func (m OverrideMatcher) matches(oc overrideComponent) bool {
+	c := oc.component
+
+	var provider, service, region string
+	if c.ProductFilter != nil {
+		provider = util.StringValue(c.ProductFilter.VendorName)
+		service = util.StringValue(c.ProductFilter.Service)
+		region = util.StringValue(c.ProductFilter.Region)
+	}
+
+	return matchesOverrideValue(m.ResourceType, oc.resourceType) &&
+		matchesOverrideValue(m.CostComponent, c.Name) &&
+		matchesOverrideValue(m.PriceHash, c.PriceHash()) &&
+		matchesOverrideValue(m.Provider, provider) &&
+		matchesOverrideValue(m.Service, service) &&
+		matchesOverrideValue(m.Region, region)
+}
+
+func matchesOverrideValue(pattern string, v string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	re, _ := compileOverrideRegex(pattern)
+	if re != nil {
+		return re.MatchString(v)
+	}
+
+	return pattern == v
+}
+
+// compileOverrideRegex compiles patterns in the /pattern/flags format. It
+// returns nil if the pattern isn't a regex.
+func compileOverrideRegex(pattern string) (*regexp.Regexp, error) {
+	i := strings.LastIndex(pattern, "/")
+	if !strings.HasPrefix(pattern, "/") || i <= 0 {
+		return nil, nil
+	}
+
+	if re, ok := overrideRegexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	expr := pattern[1:i]
+	if strings.Contains(pattern[i+1:], "i") {
+		expr = "(?i)" + expr
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	overrideRegexCache.Store(pattern, re)
+
+	return re, nil
+}
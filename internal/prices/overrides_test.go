@@ -0,0 +1,188 @@
+package prices
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/util"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func overrideTestProject() *schema.Project {
+	component := func(name string, priceHash string, region string, price string) *schema.CostComponent {
+		c := &schema.CostComponent{
+			Name:           name,
+			UnitMultiplier: decimal.NewFromInt(1),
+			HourlyQuantity: util.DecimalPtr(decimal.NewFromInt(1)),
+			ProductFilter: &schema.ProductFilter{
+				VendorName: util.StrPtr("aws"),
+				Service:    util.StrPtr("AmazonEC2"),
+				Region:     util.StrPtr(region),
+			},
+		}
+		c.SetPrice(decimal.RequireFromString(price))
+		c.SetPriceHash(priceHash)
+		return c
+	}
+
+	return &schema.Project{
+		Name: "my-project",
+		Resources: []*schema.Resource{
+			{
+				Name:           "aws_instance.web",
+				ResourceType:   "aws_instance",
+				CostComponents: []*schema.CostComponent{component("Instance usage (t3.large)", "large-hash", "us-east-1", "0.1")},
+			},
+			{
+				Name:           "aws_instance.app",
+				ResourceType:   "aws_instance",
+				CostComponents: []*schema.CostComponent{component("Instance usage (t3.micro)", "micro-hash", "eu-west-1", "0.01")},
+			},
+		},
+	}
+}
+
+func TestPriceOverridesApply(t *testing.T) {
+	price := 0.05
+
+	tests := map[string]struct {
+		overrides   *PriceOverrides
+		prices      map[string]string
+		listPrices  map[string]string
+		credits     map[string]string
+		monthlyCost map[string]string
+	}{
+		"price by price hash": {
+			overrides: &PriceOverrides{
+				Prices: []*PriceRule{{OverrideMatcher: OverrideMatcher{PriceHash: "large-hash"}, Price: &price}},
+			},
+			prices:     map[string]string{"aws_instance.web": "0.05", "aws_instance.app": "0.01"},
+			listPrices: map[string]string{"aws_instance.web": "0.1"},
+		},
+		"price by cost component regex takes precedence over discount": {
+			overrides: &PriceOverrides{
+				Prices:    []*PriceRule{{OverrideMatcher: OverrideMatcher{CostComponent: "/T3\\.MICRO/i"}, Price: &price}},
+				Discounts: []*DiscountRule{{Percent: 50}},
+			},
+			prices:     map[string]string{"aws_instance.web": "0.05", "aws_instance.app": "0.05"},
+			listPrices: map[string]string{"aws_instance.web": "0.1", "aws_instance.app": "0.01"},
+		},
+		"discount by provider and region": {
+			overrides: &PriceOverrides{
+				Discounts: []*DiscountRule{{OverrideMatcher: OverrideMatcher{Provider: "aws", Region: "us-east-1"}, Percent: 20}},
+			},
+			prices:     map[string]string{"aws_instance.web": "0.08", "aws_instance.app": "0.01"},
+			listPrices: map[string]string{"aws_instance.web": "0.1"},
+		},
+		"credits are used up in resource name order": {
+			overrides: &PriceOverrides{
+				Credits: []*CreditRule{{OverrideMatcher: OverrideMatcher{ResourceType: "aws_instance"}, Name: "startup", MonthlyAmount: 10}},
+			},
+			prices:      map[string]string{"aws_instance.web": "0.1", "aws_instance.app": "0.01"},
+			credits:     map[string]string{"aws_instance.app": "7.3", "aws_instance.web": "2.7"},
+			monthlyCost: map[string]string{"aws_instance.app": "0", "aws_instance.web": "70.3"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			project := overrideTestProject()
+			tc.overrides.Apply([]*schema.Project{project})
+			tc.overrides.ApplyCredits([]*schema.Project{project})
+
+			for _, r := range project.Resources {
+				c := r.CostComponents[0]
+				c.CalculateCosts()
+
+				assert.Equal(t, tc.prices[r.Name], c.Price().String(), r.Name)
+
+				if lp, ok := tc.listPrices[r.Name]; ok {
+					require.NotNil(t, c.ListPrice(), r.Name)
+					assert.Equal(t, lp, c.ListPrice().String(), r.Name)
+				} else {
+					assert.Nil(t, c.ListPrice(), r.Name)
+				}
+
+				if credit, ok := tc.credits[r.Name]; ok {
+					require.NotNil(t, c.MonthlyCredit(), r.Name)
+					assert.Equal(t, credit, c.MonthlyCredit().String(), r.Name)
+				} else {
+					assert.Nil(t, c.MonthlyCredit(), r.Name)
+				}
+
+				if cost, ok := tc.monthlyCost[r.Name]; ok {
+					assert.Equal(t, cost, c.MonthlyCost.String(), r.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestPriceOverridesCreditsWithCommitmentCoverage(t *testing.T) {
+	project := overrideTestProject()
+	c := project.Resources[0].CostComponents[0]
+
+	// 40% of the $73 monthly cost is saved by a commitment
+	c.CommitmentCoverage = []*schema.CommitmentCoverage{
+		{Commitment: "ri", Type: schema.CommitmentReservedInstance, HourlyOnDemandCost: decimal.RequireFromString("0.1"), HourlyCost: decimal.RequireFromString("0.06")},
+	}
+
+	o := &PriceOverrides{
+		Credits: []*CreditRule{{OverrideMatcher: OverrideMatcher{PriceHash: "large-hash"}, Name: "startup", MonthlyAmount: 100}},
+	}
+	o.ApplyCredits([]*schema.Project{project})
+
+	schema.CalculateCosts(project)
+
+	assert.Equal(t, "43.8", c.MonthlyCredit().String())
+	assert.Equal(t, "0", c.MonthlyCost.String())
+	assert.Equal(t, "0", c.HourlyCost.String())
+	assert.Equal(t, "0", project.Resources[0].MonthlyCost.String())
+}
+
+func TestLoadPriceOverrides(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		err     string
+	}{
+		"valid": {
+			content: "version: 0.1\nprices:\n  - price_hash: abc\n    price: 0.05\ndiscounts:\n  - provider: aws\n    percent: 10\ncredits:\n  - name: startup\n    monthly_amount: 100\n",
+		},
+		"unsupported version": {
+			content: "version: 0.2\n",
+			err:     "Invalid price overrides file version. Supported versions are 0.1 ≤ x ≤ 0.1",
+		},
+		"missing price": {
+			content: "version: 0.1\nprices:\n  - price_hash: abc\n",
+			err:     "Invalid price overrides file: prices[0] is missing a price",
+		},
+		"invalid percent": {
+			content: "version: 0.1\ndiscounts:\n  - percent: 120\n",
+			err:     "Invalid price overrides file: discounts[0] percent must be between 0 and 100",
+		},
+		"invalid regex": {
+			content: "version: 0.1\ndiscounts:\n  - region: /us-(/\n    percent: 10\n",
+			err:     "Invalid price overrides file: invalid regex /us-(/",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "overrides.yml")
+			require.NoError(t, ioutil.WriteFile(path, []byte(tc.content), 0600))
+
+			_, err := LoadPriceOverrides(path)
+			if tc.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
@@ -2,32 +2,216 @@ package prices
 
 import (
 	"runtime"
+	"sort"
+	"strings"
 
 	"github.com/infracost/infracost/internal/apiclient"
 	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/pricestore"
 	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/util"
 
 	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
 )
 
-func PopulatePrices(cfg *config.Config, project *schema.Project) error {
-	resources := project.AllResources()
+// maxBatchSize is the maximum number of queries sent in one request to the
+// Cloud Pricing API.
+const maxBatchSize = 100
+
+// PopulatePrices gets the prices for the resources of all the projects. The
+// projects are priced together so identical queries are only run once.
+func PopulatePrices(cfg *config.Config, projects []*schema.Project) error {
+	resources := make([]*schema.Resource, 0)
+	for _, project := range projects {
+		resources = append(resources, project.AllResources()...)
+	}
+
+	var overrides *PriceOverrides
+	if cfg.PriceOverridesFile != "" {
+		var err error
+		overrides, err = LoadPriceOverrides(cfg.PriceOverridesFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	var spotPriceHistory *SpotPriceHistory
+	if cfg.SpotPriceHistoryFile != "" {
+		var err error
+		spotPriceHistory, err = LoadSpotPriceHistory(cfg.SpotPriceHistoryFile)
+		if err != nil {
+			return err
+		}
+	}
 
 	c := apiclient.NewPricingAPIClient(cfg)
 
+	if cfg.PricingSnapshot != "" {
+		snapshot, err := pricestore.Load(cfg.PricingSnapshot)
+		if err != nil {
+			return err
+		}
+		c.Snapshot = snapshot
+
+		if cfg.AsOf != "" && !snapshot.HasPriceHistory() {
+			log.Warnf("The price snapshot has no price effective dates, using the snapshot prices for %s", cfg.AsOf)
+		}
+	}
+
 	err := GetPricesConcurrent(c, resources)
 	if err != nil {
 		return err
 	}
+
+	if spotPriceHistory != nil {
+		spotPriceHistory.Apply(projects, cfg.SpotPriceStatistic)
+	}
+
+	if overrides != nil {
+		overrides.Apply(projects)
+	}
+
+	return nil
+}
+
+// ApplyCredits takes the credits from the price overrides file off the costs
+// of the projects. The prices and any commitments must have already been
+// applied, so the credits are taken off the cost after the commitments.
+func ApplyCredits(cfg *config.Config, projects []*schema.Project) error {
+	if cfg.PriceOverridesFile == "" {
+		return nil
+	}
+
+	overrides, err := LoadPriceOverrides(cfg.PriceOverridesFile)
+	if err != nil {
+		return err
+	}
+
+	overrides.ApplyCredits(projects)
+
+	return nil
+}
+
+// ExportSnapshot gets the full product and price details for every cost
+// component of the projects so they can be saved as a price snapshot.
+func ExportSnapshot(cfg *config.Config, projects []*schema.Project) (*pricestore.Store, error) {
+	c := apiclient.NewPricingAPIClient(cfg)
+	s := pricestore.NewStore()
+
+	for _, project := range projects {
+		for _, r := range project.AllResources() {
+			if r.IsSkipped {
+				continue
+			}
+
+			err := exportResource(c, s, r)
+			if err != nil {
+				return s, err
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// ExportResourceTypesSnapshot gets the full product and price details for
+// every product that the cost components of the resources could use, so
+// resources of the same types can be priced from the snapshot whatever their
+// attributes are. The products are matched by their service, product family
+// and region.
+func ExportResourceTypesSnapshot(cfg *config.Config, resources []*schema.Resource) (*pricestore.Store, error) {
+	c := apiclient.NewPricingAPIClient(cfg)
+	s := pricestore.NewStore()
+
+	r := &schema.Resource{
+		Name:           "resource_types",
+		CostComponents: productFamilyCostComponents(resources),
+	}
+
+	return s, exportResource(c, s, r)
+}
+
+// productFamilyCostComponents returns a cost component for each unique
+// product family used by the cost components of the resources and their
+// sub-resources. The cost components have no attribute or price filters so
+// they match all the products and prices of the product family.
+func productFamilyCostComponents(resources []*schema.Resource) []*schema.CostComponent {
+	components := make([]*schema.CostComponent, 0)
+	seen := make(map[string]bool)
+
+	for _, r := range resources {
+		all := append([]*schema.CostComponent{}, r.CostComponents...)
+		for _, sub := range r.FlattenedSubResources() {
+			all = append(all, sub.CostComponents...)
+		}
+
+		for _, c := range all {
+			if c.ProductFilter == nil {
+				continue
+			}
+
+			filter := &schema.ProductFilter{
+				VendorName:    c.ProductFilter.VendorName,
+				Service:       c.ProductFilter.Service,
+				ProductFamily: c.ProductFilter.ProductFamily,
+				Region:        c.ProductFilter.Region,
+			}
+
+			key := strings.Join([]string{util.StringValue(filter.VendorName), util.StringValue(filter.Service), util.StringValue(filter.ProductFamily), util.StringValue(filter.Region)}, "/")
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			components = append(components, &schema.CostComponent{
+				Name:          key,
+				ProductFilter: filter,
+			})
+		}
+	}
+
+	return components
+}
+
+func exportResource(c *apiclient.PricingAPIClient, s *pricestore.Store, r *schema.Resource) error {
+	results, err := c.RunExportQueries(r)
+	if err != nil {
+		return err
+	}
+
+	for _, res := range results {
+		for _, p := range res.Result.Get("data.products").Array() {
+			s.AddProduct(pricestore.ParseProduct(p, c.Currency))
+		}
+	}
+
 	return nil
 }
 
 // GetPricesConcurrent gets the prices of all resources concurrently.
-// Concurrency level is calculated using the following formula:
-// max(min(4, numCPU * 4), 16)
+// The queries for all the cost components are deduplicated and then sent in
+// batches of at most maxBatchSize queries. Concurrency level is calculated
+// using the following formula: max(min(4, numCPU * 4), 16)
 func GetPricesConcurrent(c *apiclient.PricingAPIClient, resources []*schema.Resource) error {
+	// Sort the resources so the results are fanned out, and any warnings are
+	// logged, in a consistent order
+	resources = append([]*schema.Resource{}, resources...)
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resources[i].Name < resources[j].Name
+	})
+
+	plan, err := c.PlanQueries(resources)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Planned %d unique queries for %d resources", len(plan.Queries), len(resources))
+
+	batches := batchIndexes(len(plan.Queries), maxBatchSize)
+	results := make([]gjson.Result, len(plan.Queries))
+
 	// Set the number of workers
 	numWorkers := 4
 	numCPU := runtime.NumCPU()
@@ -37,24 +221,29 @@ func GetPricesConcurrent(c *apiclient.PricingAPIClient, resources []*schema.Reso
 	if numWorkers > 16 {
 		numWorkers = 16
 	}
-	numJobs := len(resources)
-	jobs := make(chan *schema.Resource, numJobs)
+	numJobs := len(batches)
+	jobs := make(chan [2]int, numJobs)
 	resultErrors := make(chan error, numJobs)
 
-	// Fire up the workers
+	// Fire up the workers. Each batch writes to its own range of the results
+	// so the workers don't need to synchronise.
 	for i := 0; i < numWorkers; i++ {
-		go func(jobs <-chan *schema.Resource, resultErrors chan<- error) {
-			for r := range jobs {
-				err := GetPrices(c, r)
+		go func(jobs <-chan [2]int, resultErrors chan<- error) {
+			for b := range jobs {
+				batchResults, err := c.RunQueryBatch(plan.Queries[b[0]:b[1]])
+				if err == nil {
+					copy(results[b[0]:b[1]], batchResults)
+				}
 				resultErrors <- err
 			}
 		}(jobs, resultErrors)
 	}
 
 	// Feed the workers the jobs of getting prices
-	for _, r := range resources {
-		jobs <- r
+	for _, b := range batches {
+		jobs <- b
 	}
+	close(jobs)
 
 	// Get the result of the jobs
 	for i := 0; i < numJobs; i++ {
@@ -63,24 +252,31 @@ func GetPricesConcurrent(c *apiclient.PricingAPIClient, resources []*schema.Reso
 			return err
 		}
 	}
-	return nil
-}
 
-func GetPrices(c *apiclient.PricingAPIClient, r *schema.Resource) error {
-	if r.IsSkipped {
-		return nil
+	// Fan the results back out to every cost component that needs them. This is
+	// done after all the batches have finished since it modifies the resources.
+	for i, keys := range plan.Keys {
+		for _, k := range keys {
+			setCostComponentPrice(c.Currency, k.Resource, k.CostComponent, results[i])
+		}
 	}
 
-	results, err := c.RunQueries(r)
-	if err != nil {
-		return err
-	}
+	return nil
+}
 
-	for _, r := range results {
-		setCostComponentPrice(c.Currency, r.Resource, r.CostComponent, r.Result)
+// batchIndexes splits n items into [start, end) ranges of at most size items.
+func batchIndexes(n int, size int) [][2]int {
+	batches := make([][2]int, 0, (n+size-1)/size)
+
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		batches = append(batches, [2]int{start, end})
 	}
 
-	return nil
+	return batches
 }
 
 func setCostComponentPrice(currency string, r *schema.Resource, c *schema.CostComponent, res gjson.Result) {
@@ -95,11 +291,13 @@ func setCostComponentPrice(currency string, r *schema.Resource, c *schema.CostCo
 		}
 
 		log.Warnf("No products found for %s %s, using 0.00", r.Name, c.Name)
+		c.AddPricingIssue(schema.PricingIssueNoProducts, 0)
 		c.SetPrice(decimal.Zero)
 		return
 	}
 	if len(products) > 1 {
 		log.Warnf("Multiple products found for %s %s, using the first product", r.Name, c.Name)
+		c.AddPricingIssue(schema.PricingIssueMultipleProducts, len(products))
 	}
 
 	prices := products[0].Get("prices").Array()
@@ -111,17 +309,20 @@ func setCostComponentPrice(currency string, r *schema.Resource, c *schema.CostCo
 		}
 
 		log.Warnf("No prices found for %s %s, using 0.00", r.Name, c.Name)
+		c.AddPricingIssue(schema.PricingIssueNoPrices, 0)
 		c.SetPrice(decimal.Zero)
 		return
 	}
 	if len(prices) > 1 {
 		log.Warnf("Multiple prices found for %s %s, using the first price", r.Name, c.Name)
+		c.AddPricingIssue(schema.PricingIssueMultiplePrices, len(prices))
 	}
 
 	var err error
 	p, err = decimal.NewFromString(prices[0].Get(currency).String())
 	if err != nil {
 		log.Warnf("Error converting price to '%v' (using 0.00)  '%v': %s", currency, prices[0].Get(currency).String(), err.Error())
+		c.AddPricingIssue(schema.PricingIssueInvalidPrice, 1)
 		c.SetPrice(decimal.Zero)
 		return
 	}
@@ -0,0 +1,149 @@
+package prices
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/pricestore"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/util"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func instanceResource(name string, instanceType string) *schema.Resource {
+	return &schema.Resource{
+		Name: name,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name: fmt.Sprintf("Instance usage (Linux/UNIX, on-demand, %s)", instanceType),
+				ProductFilter: &schema.ProductFilter{
+					VendorName: util.StrPtr("aws"),
+					Service:    util.StrPtr("AmazonEC2"),
+					AttributeFilters: []*schema.AttributeFilter{
+						{Key: "instanceType", Value: util.StrPtr(instanceType)},
+					},
+				},
+				PriceFilter: &schema.PriceFilter{PurchaseOption: util.StrPtr("on_demand")},
+			},
+		},
+	}
+}
+
+func testClient() *apiclient.PricingAPIClient {
+	s := pricestore.NewStore()
+
+	for i, instanceType := range []string{"t3.micro", "t3.large"} {
+		s.AddProduct(&pricestore.Product{
+			ProductHash: instanceType,
+			VendorName:  "aws",
+			Service:     "AmazonEC2",
+			Attributes:  []*pricestore.Attribute{{Key: "instanceType", Value: instanceType}},
+			Prices: []*pricestore.Price{
+				{PriceHash: instanceType + "-od", PurchaseOption: "on_demand", Amounts: map[string]string{"USD": fmt.Sprintf("0.0%d", i+1)}},
+			},
+		})
+	}
+
+	c := apiclient.NewPricingAPIClient(&config.Config{PricingCacheDisabled: true})
+	c.Snapshot = s
+
+	return c
+}
+
+func TestPlanQueriesDeduplicates(t *testing.T) {
+	c := testClient()
+
+	resources := []*schema.Resource{
+		instanceResource("aws_instance.a", "t3.micro"),
+		instanceResource("aws_instance.b", "t3.micro"),
+		instanceResource("aws_instance.c", "t3.large"),
+		{Name: "aws_instance.skipped", IsSkipped: true},
+	}
+
+	plan, err := c.PlanQueries(resources)
+	require.NoError(t, err)
+
+	assert.Len(t, plan.Queries, 2)
+	assert.Len(t, plan.Keys[0], 2)
+	assert.Len(t, plan.Keys[1], 1)
+	assert.Equal(t, "aws_instance.c", plan.Keys[1][0].Resource.Name)
+}
+
+func TestGetPricesConcurrentFansOutResults(t *testing.T) {
+	c := testClient()
+
+	resources := make([]*schema.Resource, 0)
+	for i := 0; i < maxBatchSize*2+1; i++ {
+		instanceType := "t3.micro"
+		if i%2 == 1 {
+			instanceType = "t3.large"
+		}
+		resources = append(resources, instanceResource(fmt.Sprintf("aws_instance.r%d", i), instanceType))
+	}
+
+	err := GetPricesConcurrent(c, resources)
+	require.NoError(t, err)
+
+	for i, r := range resources {
+		expected := decimal.RequireFromString("0.01")
+		if i%2 == 1 {
+			expected = decimal.RequireFromString("0.02")
+		}
+
+		assert.True(t, expected.Equal(r.CostComponents[0].Price()), "unexpected price for %s", r.Name)
+	}
+}
+
+func TestBatchIndexes(t *testing.T) {
+	assert.Equal(t, [][2]int{}, batchIndexes(0, 3))
+	assert.Equal(t, [][2]int{{0, 3}}, batchIndexes(3, 3))
+	assert.Equal(t, [][2]int{{0, 3}, {3, 6}, {6, 7}}, batchIndexes(7, 3))
+}
+
+func TestProductFamilyCostComponents(t *testing.T) {
+	web := instanceResource("aws_instance.web", "t3.micro")
+	web.CostComponents[0].ProductFilter.ProductFamily = util.StrPtr("Compute Instance")
+	web.CostComponents[0].ProductFilter.Region = util.StrPtr("us-east-1")
+	web.SubResources = []*schema.Resource{
+		{
+			Name: "root_block_device",
+			CostComponents: []*schema.CostComponent{
+				{
+					Name: "Storage (general purpose SSD, gp2)",
+					ProductFilter: &schema.ProductFilter{
+						VendorName:    util.StrPtr("aws"),
+						Service:       util.StrPtr("AmazonEC2"),
+						ProductFamily: util.StrPtr("Storage"),
+						Region:        util.StrPtr("us-east-1"),
+						AttributeFilters: []*schema.AttributeFilter{
+							{Key: "volumeApiName", Value: util.StrPtr("gp2")},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	app := instanceResource("aws_instance.app", "t3.large")
+	app.CostComponents[0].ProductFilter.ProductFamily = util.StrPtr("Compute Instance")
+	app.CostComponents[0].ProductFilter.Region = util.StrPtr("us-east-1")
+
+	components := productFamilyCostComponents([]*schema.Resource{web, app})
+	require.Len(t, components, 2)
+
+	assert.Equal(t, "aws/AmazonEC2/Compute Instance/us-east-1", components[0].Name)
+	assert.Equal(t, &schema.ProductFilter{
+		VendorName:    util.StrPtr("aws"),
+		Service:       util.StrPtr("AmazonEC2"),
+		ProductFamily: util.StrPtr("Compute Instance"),
+		Region:        util.StrPtr("us-east-1"),
+	}, components[0].ProductFilter)
+	assert.Nil(t, components[0].PriceFilter)
+
+	assert.Equal(t, "aws/AmazonEC2/Storage/us-east-1", components[1].Name)
+	assert.Empty(t, components[1].ProductFilter.AttributeFilters)
+}
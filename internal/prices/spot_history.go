@@ -0,0 +1,308 @@
+package prices
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/util"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultSpotPriceStatistic = "p50"
+
+var availabilityZoneRegex = regexp.MustCompile(`^(.*\d)[a-z]+$`)
+var percentileStatisticRegex = regexp.MustCompile(`^p(\d{1,3}(\.\d+)?)$`)
+
+// SpotPriceHistory is a set of historical spot prices, such as the output of
+// `aws ec2 describe-spot-price-history`. It is used to price spot instances
+// using a statistic of the history instead of the current spot price.
+type SpotPriceHistory struct {
+	// samples are the spot prices keyed by region, instance type and
+	// operating system.
+	samples map[string][]decimal.Decimal
+	// zoneSamples are the spot prices keyed by availability zone, instance
+	// type and operating system.
+	zoneSamples map[string][]decimal.Decimal
+}
+
+// SpotPrice is a spot price for an instance type in an availability zone.
+type SpotPrice struct {
+	AvailabilityZone   string `json:"AvailabilityZone"`
+	InstanceType       string `json:"InstanceType"`
+	ProductDescription string `json:"ProductDescription"`
+	SpotPrice          string `json:"SpotPrice"`
+	Timestamp          string `json:"Timestamp"`
+}
+
+// LoadSpotPriceHistory loads a spot price history file. JSON files use the
+// format of `aws ec2 describe-spot-price-history`. CSV files need a header row
+// with availability_zone, instance_type and spot_price columns and can also
+// have product_description and timestamp columns.
+func LoadSpotPriceHistory(path string) (*SpotPriceHistory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading spot price history file")
+	}
+	defer f.Close()
+
+	var prices []SpotPrice
+	if strings.ToLower(filepath.Ext(path)) == ".csv" {
+		prices, err = readSpotPricesCSV(f)
+	} else {
+		prices, err = readSpotPricesJSON(f)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing spot price history file")
+	}
+
+	h := &SpotPriceHistory{
+		samples:     make(map[string][]decimal.Decimal),
+		zoneSamples: make(map[string][]decimal.Decimal),
+	}
+
+	for i, p := range prices {
+		price, err := decimal.NewFromString(p.SpotPrice)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing spot price history file: invalid spot price %q in row %d", p.SpotPrice, i+1)
+		}
+
+		m := availabilityZoneRegex.FindStringSubmatch(p.AvailabilityZone)
+		if m == nil {
+			return nil, fmt.Errorf("Error parsing spot price history file: invalid availability zone %q in row %d", p.AvailabilityZone, i+1)
+		}
+
+		os := spotOperatingSystem(p.ProductDescription)
+
+		key := spotPriceKey(m[1], p.InstanceType, os)
+		h.samples[key] = append(h.samples[key], price)
+
+		zoneKey := spotPriceKey(p.AvailabilityZone, p.InstanceType, os)
+		h.zoneSamples[zoneKey] = append(h.zoneSamples[zoneKey], price)
+	}
+
+	for _, m := range []map[string][]decimal.Decimal{h.samples, h.zoneSamples} {
+		for _, samples := range m {
+			sort.Slice(samples, func(i, j int) bool {
+				return samples[i].LessThan(samples[j])
+			})
+		}
+	}
+
+	return h, nil
+}
+
+func readSpotPricesJSON(r io.Reader) ([]SpotPrice, error) {
+	var out struct {
+		SpotPriceHistory []SpotPrice `json:"SpotPriceHistory"`
+	}
+
+	err := json.NewDecoder(r).Decode(&out)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.SpotPriceHistory, nil
+}
+
+func readSpotPricesCSV(r io.Reader) ([]SpotPrice, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return []SpotPrice{}, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range []string{"availability_zone", "instance_type", "spot_price"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing %s column", name)
+		}
+	}
+
+	get := func(row []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	prices := make([]SpotPrice, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		prices = append(prices, SpotPrice{
+			AvailabilityZone:   get(row, "availability_zone"),
+			InstanceType:       get(row, "instance_type"),
+			ProductDescription: get(row, "product_description"),
+			SpotPrice:          get(row, "spot_price"),
+			Timestamp:          get(row, "timestamp"),
+		})
+	}
+
+	return prices, nil
+}
+
+// spotOperatingSystem maps the product descriptions used by the spot price
+// history to the operatingSystem attribute used by the Cloud Pricing API.
+// Prices without a product description are assumed to be for Linux.
+func spotOperatingSystem(productDescription string) string {
+	d := strings.ToLower(productDescription)
+
+	switch {
+	case strings.HasPrefix(d, "windows"):
+		return "Windows"
+	case strings.HasPrefix(d, "red hat"):
+		return "RHEL"
+	case strings.HasPrefix(d, "suse"):
+		return "SUSE"
+	}
+
+	return "Linux"
+}
+
+func spotPriceKey(region string, instanceType string, os string) string {
+	return fmt.Sprintf("%s/%s/%s", region, instanceType, os)
+}
+
+// ValidateSpotPriceStatistic checks the statistic is one of mean, min, max or
+// a percentile such as p90.
+func ValidateSpotPriceStatistic(statistic string) error {
+	if statistic == "" || statistic == "mean" || statistic == "min" || statistic == "max" {
+		return nil
+	}
+
+	m := percentileStatisticRegex.FindStringSubmatch(statistic)
+	if m != nil {
+		p, _ := strconv.ParseFloat(m[1], 64)
+		if p > 0 && p <= 100 {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Invalid spot price statistic %s. Expected: mean, min, max or a percentile such as p90", statistic)
+}
+
+// Price returns the statistic of the spot prices for the instance type. If the
+// availability zone is set and is in the history only its prices are used,
+// otherwise the prices from all the availability zones in the region are used.
+// Each price change in the history counts as one sample.
+func (h *SpotPriceHistory) Price(region string, zone string, instanceType string, os string, statistic string) (decimal.Decimal, bool) {
+	var samples []decimal.Decimal
+	if zone != "" {
+		samples = h.zoneSamples[spotPriceKey(zone, instanceType, os)]
+	}
+
+	if len(samples) == 0 {
+		samples = h.samples[spotPriceKey(region, instanceType, os)]
+	}
+
+	if len(samples) == 0 {
+		return decimal.Zero, false
+	}
+
+	switch statistic {
+	case "min":
+		return samples[0], true
+	case "max":
+		return samples[len(samples)-1], true
+	case "mean":
+		total := decimal.Zero
+		for _, s := range samples {
+			total = total.Add(s)
+		}
+		return total.Div(decimal.NewFromInt(int64(len(samples)))), true
+	}
+
+	if statistic == "" {
+		statistic = defaultSpotPriceStatistic
+	}
+
+	p, _ := strconv.ParseFloat(strings.TrimPrefix(statistic, "p"), 64)
+
+	// Use the nearest-rank method so the result is always one of the samples
+	rank := decimal.NewFromFloat(p).Div(decimal.NewFromInt(100)).Mul(decimal.NewFromInt(int64(len(samples)))).Ceil().IntPart()
+	if rank < 1 {
+		rank = 1
+	}
+
+	return samples[rank-1], true
+}
+
+// Apply replaces the prices of the spot instance cost components with the
+// statistic of the spot price history. Cost components that aren't in the
+// history keep the current spot price.
+func (h *SpotPriceHistory) Apply(projects []*schema.Project, statistic string) {
+	for _, project := range projects {
+		for _, r := range project.AllResources() {
+			if r.IsSkipped {
+				continue
+			}
+
+			for _, c := range spotCostComponents(r) {
+				f := c.ProductFilter
+
+				instanceType := attributeFilterValue(f, "instanceType")
+				os := attributeFilterValue(f, "operatingSystem")
+				region := util.StringValue(f.Region)
+
+				price, ok := h.Price(region, r.AvailabilityZone, instanceType, os, statistic)
+				if !ok {
+					log.Warnf("No spot price history found for %s %s in %s, using the current spot price", os, instanceType, region)
+					continue
+				}
+
+				log.Debugf("Using spot price history for %s %s: %s", r.Name, c.Name, price)
+				c.SetPrice(price)
+			}
+		}
+	}
+}
+
+func spotCostComponents(r *schema.Resource) []*schema.CostComponent {
+	components := make([]*schema.CostComponent, 0)
+
+	all := append([]*schema.CostComponent{}, r.CostComponents...)
+	for _, s := range r.FlattenedSubResources() {
+		all = append(all, s.CostComponents...)
+	}
+
+	for _, c := range all {
+		if c.ProductFilter == nil || c.PriceFilter == nil {
+			continue
+		}
+
+		if util.StringValue(c.ProductFilter.Service) == "AmazonEC2" &&
+			util.StringValue(c.PriceFilter.PurchaseOption) == "spot" &&
+			attributeFilterValue(c.ProductFilter, "instanceType") != "" {
+			components = append(components, c)
+		}
+	}
+
+	return components
+}
+
+func attributeFilterValue(f *schema.ProductFilter, key string) string {
+	for _, a := range f.AttributeFilters {
+		if a.Key == key && a.Value != nil {
+			return *a.Value
+		}
+	}
+
+	return ""
+}
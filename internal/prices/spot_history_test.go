@@ -0,0 +1,139 @@
+package prices
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/util"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSpotPriceHistory(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadSpotPriceHistory(t *testing.T) {
+	csvPath := writeSpotPriceHistory(t, "history.csv", `availability_zone,instance_type,product_description,spot_price
+us-east-1a,m5.large,Linux/UNIX,0.04
+us-east-1b,m5.large,Linux/UNIX (Amazon VPC),0.01
+us-east-1c,m5.large,Linux/UNIX,0.03
+us-east-1a,m5.large,Linux/UNIX,0.02
+us-east-1a,m5.large,Windows,0.5
+eu-west-1a,m5.large,Linux/UNIX,0.9
+`)
+
+	jsonPath := writeSpotPriceHistory(t, "history.json", `{
+  "SpotPriceHistory": [
+    {"AvailabilityZone": "us-east-1a", "InstanceType": "m5.large", "ProductDescription": "Linux/UNIX", "SpotPrice": "0.040000", "Timestamp": "2021-09-01T00:00:00.000Z"},
+    {"AvailabilityZone": "us-east-1b", "InstanceType": "m5.large", "ProductDescription": "Linux/UNIX", "SpotPrice": "0.010000", "Timestamp": "2021-09-02T00:00:00.000Z"},
+    {"AvailabilityZone": "us-east-1c", "InstanceType": "m5.large", "ProductDescription": "Linux/UNIX", "SpotPrice": "0.030000", "Timestamp": "2021-09-03T00:00:00.000Z"},
+    {"AvailabilityZone": "us-east-1a", "InstanceType": "m5.large", "ProductDescription": "Linux/UNIX", "SpotPrice": "0.020000", "Timestamp": "2021-09-04T00:00:00.000Z"}
+  ]
+}`)
+
+	expected := map[string]string{
+		"min":  "0.01",
+		"max":  "0.04",
+		"mean": "0.025",
+		"p50":  "0.02",
+		"p75":  "0.03",
+		"p90":  "0.04",
+		"":     "0.02",
+	}
+
+	for _, path := range []string{csvPath, jsonPath} {
+		h, err := LoadSpotPriceHistory(path)
+		require.NoError(t, err)
+
+		for statistic, price := range expected {
+			p, ok := h.Price("us-east-1", "", "m5.large", "Linux", statistic)
+			require.True(t, ok)
+			assert.Equal(t, price, p.String(), "%s %s", filepath.Ext(path), statistic)
+		}
+
+		_, ok := h.Price("us-west-2", "", "m5.large", "Linux", "p50")
+		assert.False(t, ok)
+	}
+
+	h, err := LoadSpotPriceHistory(csvPath)
+	require.NoError(t, err)
+
+	p, ok := h.Price("us-east-1", "", "m5.large", "Windows", "max")
+	require.True(t, ok)
+	assert.Equal(t, "0.5", p.String())
+}
+
+func TestLoadSpotPriceHistoryErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing column":    "availability_zone,spot_price\nus-east-1a,0.01\n",
+		"invalid price":     "availability_zone,instance_type,spot_price\nus-east-1a,m5.large,cheap\n",
+		"invalid zone name": "availability_zone,instance_type,spot_price\nnowhere,m5.large,0.01\n",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadSpotPriceHistory(writeSpotPriceHistory(t, "history.csv", content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValidateSpotPriceStatistic(t *testing.T) {
+	for _, s := range []string{"", "min", "max", "mean", "p50", "p99.9", "p100"} {
+		assert.NoError(t, ValidateSpotPriceStatistic(s), s)
+	}
+
+	for _, s := range []string{"median", "p0", "p101", "90"} {
+		assert.Error(t, ValidateSpotPriceStatistic(s), s)
+	}
+}
+
+func TestSpotPriceHistoryApply(t *testing.T) {
+	h, err := LoadSpotPriceHistory(writeSpotPriceHistory(t, "history.csv", "availability_zone,instance_type,spot_price\nus-east-1a,m5.large,0.02\nus-east-1b,m5.large,0.03\n"))
+	require.NoError(t, err)
+
+	component := func(purchaseOption string) *schema.CostComponent {
+		c := &schema.CostComponent{
+			ProductFilter: &schema.ProductFilter{
+				Region:  util.StrPtr("us-east-1"),
+				Service: util.StrPtr("AmazonEC2"),
+				AttributeFilters: []*schema.AttributeFilter{
+					{Key: "instanceType", Value: util.StrPtr("m5.large")},
+					{Key: "operatingSystem", Value: util.StrPtr("Linux")},
+				},
+			},
+			PriceFilter: &schema.PriceFilter{PurchaseOption: util.StrPtr(purchaseOption)},
+		}
+		c.SetPrice(decimal.RequireFromString("0.05"))
+		return c
+	}
+
+	spot := component("spot")
+	onDemand := component("on_demand")
+	zoneSpot := component("spot")
+
+	h.Apply([]*schema.Project{{
+		Resources: []*schema.Resource{
+			{
+				Name:           "aws_autoscaling_group.asg",
+				SubResources:   []*schema.Resource{{Name: "aws_launch_template.lt", CostComponents: []*schema.CostComponent{onDemand, spot}}},
+				CostComponents: []*schema.CostComponent{},
+			},
+			{
+				Name:             "aws_instance.web",
+				AvailabilityZone: "us-east-1a",
+				CostComponents:   []*schema.CostComponent{zoneSpot},
+			},
+		},
+	}}, "max")
+
+	assert.Equal(t, "0.03", spot.Price().String())
+	assert.Equal(t, "0.05", onDemand.Price().String())
+	assert.Equal(t, "0.02", zoneSpot.Price().String())
+}
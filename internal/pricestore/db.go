@@ -0,0 +1,253 @@
+package pricestore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/version"
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	metaBucket     = []byte("meta")
+	productsBucket = []byte("products")
+
+	versionKey    = []byte("version")
+	currenciesKey = []byte("currencies")
+)
+
+// DB is a price database stored in a local bbolt file. Products are stored in
+// a bucket per vendor and service, keyed by region and product hash, so
+// queries only read the products of the service and region they filter on
+// instead of loading the whole database into memory.
+type DB struct {
+	db *bolt.DB
+}
+
+// OpenDB opens the price database at the path, creating it if it doesn't
+// exist and readOnly is false.
+func OpenDB(path string, readOnly bool) (*DB, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second, ReadOnly: readOnly})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error opening price database %s", path)
+	}
+
+	d := &DB{db: db}
+
+	if readOnly {
+		err = d.checkVersion()
+	} else {
+		err = d.init()
+	}
+
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+func (d *DB) init() error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateBucketIfNotExists(productsBucket)
+		if err != nil {
+			return err
+		}
+
+		v := meta.Get(versionKey)
+		if v == nil {
+			return meta.Put(versionKey, []byte(storeVersion))
+		}
+
+		if !version.IsSupportedFileVersion(string(v), minStoreVersion, maxStoreVersion) {
+			return invalidDBVersionError()
+		}
+
+		return nil
+	})
+}
+
+func (d *DB) checkVersion() error {
+	return d.db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		if meta == nil || tx.Bucket(productsBucket) == nil {
+			return errors.New("Invalid price database, create it with infracost pricing-server load")
+		}
+
+		if !version.IsSupportedFileVersion(string(meta.Get(versionKey)), minStoreVersion, maxStoreVersion) {
+			return invalidDBVersionError()
+		}
+
+		return nil
+	})
+}
+
+func invalidDBVersionError() error {
+	return fmt.Errorf("Invalid price database version. Supported versions are %s ≤ x ≤ %s", minStoreVersion, maxStoreVersion)
+}
+
+// AddProducts adds the products to the database. Products that already exist
+// have their prices merged the same way as Store.AddProduct.
+func (d *DB) AddProducts(products []*Product) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		root := tx.Bucket(productsBucket)
+
+		currencies := make(map[string]bool)
+		if v := meta.Get(currenciesKey); v != nil {
+			err := json.Unmarshal(v, &currencies)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, p := range products {
+			b, err := root.CreateBucketIfNotExists([]byte(serviceKey(p.VendorName, p.Service)))
+			if err != nil {
+				return err
+			}
+
+			key := productKey(p.Region, p.ProductHash)
+
+			if v := b.Get(key); v != nil {
+				var existing Product
+				err := json.Unmarshal(v, &existing)
+				if err != nil {
+					return errors.Wrapf(err, "Error reading product %s", p.ProductHash)
+				}
+
+				mergeProduct(&existing, p)
+				p = &existing
+			}
+
+			setEffectiveDateEnds(p)
+
+			for _, price := range p.Prices {
+				for currency := range price.Amounts {
+					currencies[currency] = true
+				}
+			}
+
+			v, err := json.Marshal(p)
+			if err != nil {
+				return err
+			}
+
+			err = b.Put(key, v)
+			if err != nil {
+				return err
+			}
+		}
+
+		v, err := json.Marshal(currencies)
+		if err != nil {
+			return err
+		}
+
+		return meta.Put(currenciesKey, v)
+	})
+}
+
+// Products returns the products matching the product filter with all their
+// prices.
+func (d *DB) Products(productFilter *schema.ProductFilter) ([]*Product, error) {
+	results := make([]*Product, 0)
+
+	if productFilter == nil {
+		return results, nil
+	}
+
+	var prefix []byte
+	if productFilter.Region != nil {
+		prefix = productKey(*productFilter.Region, "")
+	}
+
+	err := d.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(productsBucket)
+
+		return root.ForEach(func(name []byte, _ []byte) error {
+			if productFilter.VendorName != nil && productFilter.Service != nil && string(name) != serviceKey(*productFilter.VendorName, *productFilter.Service) {
+				return nil
+			}
+
+			c := root.Bucket(name).Cursor()
+
+			for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+				var p Product
+				err := json.Unmarshal(v, &p)
+				if err != nil {
+					return errors.Wrapf(err, "Error reading product %s", k)
+				}
+
+				if matchesProductFilter(&p, productFilter) {
+					results = append(results, &p)
+				}
+			}
+
+			return nil
+		})
+	})
+
+	return results, err
+}
+
+// ProductCount returns the number of products in the database.
+func (d *DB) ProductCount() (int, error) {
+	count := 0
+
+	err := d.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(productsBucket)
+
+		return root.ForEach(func(name []byte, _ []byte) error {
+			count += root.Bucket(name).Stats().KeyN
+			return nil
+		})
+	})
+
+	return count, err
+}
+
+// Currencies returns the currencies of the prices in the database.
+func (d *DB) Currencies() ([]string, error) {
+	currencies := make(map[string]bool)
+
+	err := d.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(metaBucket).Get(currenciesKey)
+		if v == nil {
+			return nil
+		}
+
+		return json.Unmarshal(v, &currencies)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(currencies))
+	for c := range currencies {
+		result = append(result, c)
+	}
+
+	sort.Strings(result)
+
+	return result, nil
+}
+
+func productKey(region string, productHash string) []byte {
+	return []byte(region + "\x00" + productHash)
+}
@@ -0,0 +1,154 @@
+package pricestore
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDB(t *testing.T) *DB {
+	db, err := OpenDB(filepath.Join(t.TempDir(), "prices.db"), false)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	require.NoError(t, db.AddProducts(testStore().Products))
+	require.NoError(t, db.AddProducts([]*Product{
+		{
+			ProductHash: "p3",
+			VendorName:  "aws",
+			Service:     "AmazonEC2",
+			Region:      "eu-west-1",
+			Attributes:  []*Attribute{{Key: "instanceType", Value: "t3.micro"}},
+			Prices: []*Price{
+				{PriceHash: "p3-od", PurchaseOption: "on_demand", Amounts: map[string]string{"EUR": "0.0098"}},
+			},
+		},
+		{
+			ProductHash: "p4",
+			VendorName:  "aws",
+			Service:     "AmazonRDS",
+			Region:      "us-east-1",
+			Prices:      []*Price{},
+		},
+	}))
+
+	return db
+}
+
+func productHashes(products []*Product) []string {
+	hashes := make([]string, 0, len(products))
+	for _, p := range products {
+		hashes = append(hashes, p.ProductHash)
+	}
+
+	sort.Strings(hashes)
+
+	return hashes
+}
+
+func TestDBProducts(t *testing.T) {
+	db := testDB(t)
+
+	tests := map[string]struct {
+		productFilter  *schema.ProductFilter
+		expectedHashes []string
+	}{
+		"service and region": {
+			productFilter:  &schema.ProductFilter{VendorName: util.StrPtr("aws"), Service: util.StrPtr("AmazonEC2"), Region: util.StrPtr("us-east-1")},
+			expectedHashes: []string{"p1", "p2"},
+		},
+		"attribute without region": {
+			productFilter: &schema.ProductFilter{
+				VendorName:       util.StrPtr("aws"),
+				Service:          util.StrPtr("AmazonEC2"),
+				AttributeFilters: []*schema.AttributeFilter{{Key: "instanceType", Value: util.StrPtr("t3.micro")}},
+			},
+			expectedHashes: []string{"p1", "p3"},
+		},
+		"region without service": {
+			productFilter:  &schema.ProductFilter{Region: util.StrPtr("us-east-1")},
+			expectedHashes: []string{"p1", "p2", "p4"},
+		},
+		"region prefix of another region": {
+			productFilter:  &schema.ProductFilter{Region: util.StrPtr("us-east")},
+			expectedHashes: []string{},
+		},
+		"no matches": {
+			productFilter:  &schema.ProductFilter{VendorName: util.StrPtr("azure")},
+			expectedHashes: []string{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			products, err := db.Products(tc.productFilter)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedHashes, productHashes(products))
+		})
+	}
+}
+
+func TestDBAddProductsMergesPrices(t *testing.T) {
+	db := testDB(t)
+
+	err := db.AddProducts([]*Product{
+		{
+			ProductHash:   "p1",
+			VendorName:    "aws",
+			Service:       "AmazonEC2",
+			ProductFamily: "Compute Instance",
+			Region:        "us-east-1",
+			Prices: []*Price{
+				{PriceHash: "p1-od", PurchaseOption: "on_demand", Amounts: map[string]string{"GBP": "0.0080"}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	products, err := db.Products(&schema.ProductFilter{Region: util.StrPtr("us-east-1")})
+	require.NoError(t, err)
+
+	var p1 *Product
+	for _, p := range products {
+		if p.ProductHash == "p1" {
+			p1 = p
+		}
+	}
+
+	require.NotNil(t, p1)
+	require.Len(t, p1.Prices, 2)
+	assert.Equal(t, map[string]string{"USD": "0.0104", "GBP": "0.0080"}, p1.Prices[0].Amounts)
+
+	count, err := db.ProductCount()
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	currencies, err := db.Currencies()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"EUR", "GBP", "USD"}, currencies)
+}
+
+func TestOpenDBReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.db")
+
+	_, err := OpenDB(path, true)
+	assert.Error(t, err)
+
+	db, err := OpenDB(path, false)
+	require.NoError(t, err)
+	require.NoError(t, db.AddProducts(testStore().Products))
+	require.NoError(t, db.Close())
+
+	db, err = OpenDB(path, true)
+	require.NoError(t, err)
+	defer db.Close()
+
+	count, err := db.ProductCount()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,190 @@
+package pricestore
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+var regexCache sync.Map
+
+// Query returns the products matching the product filter. Each returned
+// product only contains the prices that match the price filter.
+func (s *Store) Query(productFilter *schema.ProductFilter, priceFilter *schema.PriceFilter) []*Product {
+	results := make([]*Product, 0)
+
+	if productFilter == nil {
+		return results
+	}
+
+	candidates := s.Products
+	if productFilter.VendorName != nil && productFilter.Service != nil {
+		candidates = s.productsByService[serviceKey(*productFilter.VendorName, *productFilter.Service)]
+	}
+
+	for _, p := range candidates {
+		if !matchesProductFilter(p, productFilter) {
+			continue
+		}
+
+		results = append(results, &Product{
+			ProductHash:   p.ProductHash,
+			VendorName:    p.VendorName,
+			Service:       p.Service,
+			ProductFamily: p.ProductFamily,
+			Region:        p.Region,
+			Sku:           p.Sku,
+			Attributes:    p.Attributes,
+			Prices:        p.FilterPrices(priceFilter),
+		})
+	}
+
+	return results
+}
+
+// FilterPrices returns the prices of the product that match the price filter.
+func (p *Product) FilterPrices(priceFilter *schema.PriceFilter) []*Price {
+	prices := make([]*Price, 0, len(p.Prices))
+
+	for _, price := range p.Prices {
+		if matchesPriceFilter(price, priceFilter) {
+			prices = append(prices, price)
+		}
+	}
+
+	return prices
+}
+
+func matchesProductFilter(p *Product, f *schema.ProductFilter) bool {
+	if !matchesString(p.VendorName, f.VendorName) ||
+		!matchesString(p.Service, f.Service) ||
+		!matchesString(p.ProductFamily, f.ProductFamily) ||
+		!matchesString(p.Region, f.Region) ||
+		!matchesString(p.Sku, f.Sku) {
+		return false
+	}
+
+	for _, a := range f.AttributeFilters {
+		v, _ := p.Attribute(a.Key)
+
+		if a.Value != nil && v != *a.Value {
+			return false
+		}
+
+		if a.ValueRegex != nil && !matchesRegex(v, *a.ValueRegex) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesPriceFilter(p *Price, f *schema.PriceFilter) bool {
+	if f == nil {
+		return matchesEffectiveDate(p, nil)
+	}
+
+	if !matchesString(p.PurchaseOption, f.PurchaseOption) ||
+		!matchesString(p.Unit, f.Unit) ||
+		!matchesString(p.Description, f.Description) ||
+		!matchesAmount(p.StartUsageAmount, f.StartUsageAmount) ||
+		!matchesAmount(p.EndUsageAmount, f.EndUsageAmount) ||
+		!matchesString(p.TermLength, f.TermLength) ||
+		!matchesString(p.TermPurchaseOption, f.TermPurchaseOption) ||
+		!matchesString(p.TermOfferingClass, f.TermOfferingClass) {
+		return false
+	}
+
+	if f.DescriptionRegex != nil && !matchesRegex(p.Description, *f.DescriptionRegex) {
+		return false
+	}
+
+	return matchesEffectiveDate(p, f.EffectiveDate)
+}
+
+// matchesEffectiveDate checks the price is effective on the date, or today if
+// no date is given, so only one version of each price is matched.
+func matchesEffectiveDate(p *Price, date *string) bool {
+	d := time.Now().UTC().Format(EffectiveDateLayout)
+	if date != nil {
+		d = *date
+	}
+
+	if p.EffectiveDateStart != "" && d < p.EffectiveDateStart {
+		return false
+	}
+
+	if p.EffectiveDateEnd != "" && d >= p.EffectiveDateEnd {
+		return false
+	}
+
+	return true
+}
+
+func matchesString(v string, f *string) bool {
+	return f == nil || v == *f
+}
+
+// matchesAmount compares usage amounts numerically where possible so
+// values like "0" and "0.0000000000" are treated as the same amount.
+func matchesAmount(v string, f *string) bool {
+	if f == nil || v == *f {
+		return true
+	}
+
+	vd, err := decimal.NewFromString(v)
+	if err != nil {
+		return false
+	}
+
+	fd, err := decimal.NewFromString(*f)
+	if err != nil {
+		return false
+	}
+
+	return vd.Equal(fd)
+}
+
+// matchesRegex matches the value against a regex in the `/pattern/flags`
+// format used by the Cloud Pricing API. Only the `i` flag is supported.
+func matchesRegex(v string, pattern string) bool {
+	re, err := compileRegex(pattern)
+	if err != nil {
+		log.Debugf("Invalid regex %s: %s", pattern, err)
+		return false
+	}
+
+	return re.MatchString(v)
+}
+
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	expr := pattern
+	if strings.HasPrefix(pattern, "/") && strings.LastIndex(pattern, "/") > 0 {
+		i := strings.LastIndex(pattern, "/")
+		expr = pattern[1:i]
+		flags := pattern[i+1:]
+
+		if strings.Contains(flags, "i") {
+			expr = fmt.Sprintf("(?i)%s", expr)
+		}
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	regexCache.Store(pattern, re)
+
+	return re, nil
+}
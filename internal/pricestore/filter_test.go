@@ -0,0 +1,177 @@
+package pricestore
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStore() *Store {
+	s := NewStore()
+
+	s.AddProduct(&Product{
+		ProductHash:   "p1",
+		VendorName:    "aws",
+		Service:       "AmazonEC2",
+		ProductFamily: "Compute Instance",
+		Region:        "us-east-1",
+		Attributes: []*Attribute{
+			{Key: "instanceType", Value: "t3.micro"},
+			{Key: "operatingSystem", Value: "Linux"},
+		},
+		Prices: []*Price{
+			{PriceHash: "p1-od", PurchaseOption: "on_demand", Unit: "Hrs", StartUsageAmount: "0", Amounts: map[string]string{"USD": "0.0104"}},
+			{PriceHash: "p1-ri", PurchaseOption: "reserved", Unit: "Hrs", TermLength: "1yr", Amounts: map[string]string{"USD": "0.0065"}},
+		},
+	})
+
+	s.AddProduct(&Product{
+		ProductHash:   "p2",
+		VendorName:    "aws",
+		Service:       "AmazonEC2",
+		ProductFamily: "Compute Instance",
+		Region:        "us-east-1",
+		Attributes: []*Attribute{
+			{Key: "instanceType", Value: "t3.large"},
+			{Key: "operatingSystem", Value: "Linux"},
+		},
+		Prices: []*Price{
+			{PriceHash: "p2-od", PurchaseOption: "on_demand", Unit: "Hrs", StartUsageAmount: "0", Amounts: map[string]string{"USD": "0.0832"}},
+		},
+	})
+
+	return s
+}
+
+func TestQuery(t *testing.T) {
+	s := testStore()
+
+	tests := map[string]struct {
+		productFilter  *schema.ProductFilter
+		priceFilter    *schema.PriceFilter
+		expectedHashes []string
+	}{
+		"exact attribute": {
+			productFilter: &schema.ProductFilter{
+				VendorName:       util.StrPtr("aws"),
+				Service:          util.StrPtr("AmazonEC2"),
+				AttributeFilters: []*schema.AttributeFilter{{Key: "instanceType", Value: util.StrPtr("t3.micro")}},
+			},
+			priceFilter:    &schema.PriceFilter{PurchaseOption: util.StrPtr("on_demand")},
+			expectedHashes: []string{"p1-od"},
+		},
+		"case insensitive regex": {
+			productFilter: &schema.ProductFilter{
+				VendorName:       util.StrPtr("aws"),
+				Service:          util.StrPtr("AmazonEC2"),
+				AttributeFilters: []*schema.AttributeFilter{{Key: "operatingSystem", ValueRegex: util.StrPtr("/^linux$/i")}},
+			},
+			priceFilter:    &schema.PriceFilter{PurchaseOption: util.StrPtr("on_demand")},
+			expectedHashes: []string{"p1-od", "p2-od"},
+		},
+		"numeric usage amount": {
+			productFilter: &schema.ProductFilter{
+				VendorName:       util.StrPtr("aws"),
+				Service:          util.StrPtr("AmazonEC2"),
+				AttributeFilters: []*schema.AttributeFilter{{Key: "instanceType", Value: util.StrPtr("t3.large")}},
+			},
+			priceFilter:    &schema.PriceFilter{StartUsageAmount: util.StrPtr("0.0000")},
+			expectedHashes: []string{"p2-od"},
+		},
+		"nil price filter": {
+			productFilter: &schema.ProductFilter{
+				VendorName:       util.StrPtr("aws"),
+				Service:          util.StrPtr("AmazonEC2"),
+				AttributeFilters: []*schema.AttributeFilter{{Key: "instanceType", Value: util.StrPtr("t3.micro")}},
+			},
+			expectedHashes: []string{"p1-od", "p1-ri"},
+		},
+		"no match": {
+			productFilter: &schema.ProductFilter{
+				VendorName: util.StrPtr("aws"),
+				Service:    util.StrPtr("AmazonRDS"),
+			},
+			expectedHashes: []string{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			hashes := []string{}
+			for _, p := range s.Query(tc.productFilter, tc.priceFilter) {
+				for _, price := range p.Prices {
+					hashes = append(hashes, price.PriceHash)
+				}
+			}
+
+			assert.Equal(t, tc.expectedHashes, hashes)
+		})
+	}
+}
+
+func TestAddProductMergesPrices(t *testing.T) {
+	s := testStore()
+
+	s.AddProduct(&Product{
+		ProductHash: "p2",
+		VendorName:  "aws",
+		Service:     "AmazonEC2",
+		Prices: []*Price{
+			{PriceHash: "p2-od", Amounts: map[string]string{"EUR": "0.07"}},
+			{PriceHash: "p2-spot", PurchaseOption: "spot", Amounts: map[string]string{"USD": "0.025"}},
+		},
+	})
+
+	assert.Len(t, s.Products, 2)
+	assert.Len(t, s.Products[1].Prices, 2)
+	assert.Equal(t, map[string]string{"USD": "0.0832", "EUR": "0.07"}, s.Products[1].Prices[0].Amounts)
+}
+
+func TestQueryEffectiveDate(t *testing.T) {
+	s := NewStore()
+
+	s.AddProduct(&Product{
+		ProductHash: "p1",
+		VendorName:  "aws",
+		Service:     "AmazonEC2",
+		Prices: []*Price{
+			{PriceHash: "p1-od", EffectiveDateStart: "2020-01-01", Amounts: map[string]string{"USD": "0.012"}},
+		},
+	})
+	s.AddProduct(&Product{
+		ProductHash: "p1",
+		VendorName:  "aws",
+		Service:     "AmazonEC2",
+		Prices: []*Price{
+			{PriceHash: "p1-od", EffectiveDateStart: "2021-01-01", Amounts: map[string]string{"USD": "0.0104"}},
+		},
+	})
+
+	require.Len(t, s.Products[0].Prices, 2)
+	assert.Equal(t, "2021-01-01", s.Products[0].Prices[0].EffectiveDateEnd)
+	assert.Equal(t, "", s.Products[0].Prices[1].EffectiveDateEnd)
+
+	tests := []struct {
+		date   *string
+		amount string
+	}{
+		{util.StrPtr("2020-01-01"), "0.012"},
+		{util.StrPtr("2020-12-31"), "0.012"},
+		{util.StrPtr("2021-01-01"), "0.0104"},
+		{nil, "0.0104"},
+	}
+
+	for _, tc := range tests {
+		products := s.Query(&schema.ProductFilter{VendorName: util.StrPtr("aws"), Service: util.StrPtr("AmazonEC2")}, &schema.PriceFilter{EffectiveDate: tc.date})
+		require.Len(t, products, 1)
+		require.Len(t, products[0].Prices, 1)
+		assert.Equal(t, tc.amount, products[0].Prices[0].Amounts["USD"])
+	}
+
+	products := s.Query(&schema.ProductFilter{VendorName: util.StrPtr("aws"), Service: util.StrPtr("AmazonEC2")}, &schema.PriceFilter{EffectiveDate: util.StrPtr("2019-12-31")})
+	require.Len(t, products, 1)
+	assert.Len(t, products[0].Prices, 0)
+}
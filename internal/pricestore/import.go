@@ -0,0 +1,169 @@
+package pricestore
+
+import (
+	"crypto/md5" // nolint:gosec
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ImportFile imports a bulk price file from a cloud vendor into the store.
+// Supported formats are "aws" for the AWS Price List bulk JSON offer files,
+// "azure" for the Azure Retail Prices API JSON and "gcp" for the GCP Cloud
+// Billing Catalog API SKU exports. It returns the number of products imported.
+func (s *Store) ImportFile(format string, path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error opening %s", path)
+	}
+	defer f.Close()
+
+	var count int
+
+	switch strings.ToLower(format) {
+	case "aws":
+		count, err = s.ImportAWSPriceList(f)
+	case "azure":
+		count, err = s.ImportAzureRetailPrices(f)
+	case "gcp":
+		count, err = s.ImportGCPSkus(f)
+	default:
+		return 0, fmt.Errorf("Unsupported price file format %s", format)
+	}
+
+	if err != nil {
+		return count, errors.Wrapf(err, "Error importing %s", path)
+	}
+
+	return count, nil
+}
+
+// The hashes follow the same scheme for every vendor so that re-importing a
+// newer price file updates the existing products and prices in place.
+func productHash(vendorName string, parts ...string) string {
+	return hashOf(append([]string{vendorName}, parts...)...)
+}
+
+func priceHash(productHash string, p *Price) string {
+	return hashOf(
+		productHash,
+		p.PurchaseOption,
+		p.Unit,
+		p.StartUsageAmount,
+		p.EndUsageAmount,
+		p.TermLength,
+		p.TermPurchaseOption,
+		p.TermOfferingClass,
+	)
+}
+
+// setPriceHashes sets the hash of each of the product's prices and sorts them
+// so the order is stable between imports.
+func setPriceHashes(p *Product) {
+	for _, price := range p.Prices {
+		price.PriceHash = priceHash(p.ProductHash, price)
+	}
+
+	sort.Slice(p.Prices, func(i, j int) bool {
+		return p.Prices[i].PriceHash < p.Prices[j].PriceHash
+	})
+}
+
+// effectiveDate converts a timestamp from a price file, e.g.
+// 2021-09-01T00:00:00Z, into the date format used for price effective dates.
+func effectiveDate(timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return ""
+	}
+
+	return t.UTC().Format(EffectiveDateLayout)
+}
+
+func hashOf(parts ...string) string {
+	sum := md5.Sum([]byte(strings.Join(parts, "-"))) // nolint:gosec
+	return hex.EncodeToString(sum[:])
+}
+
+// walkObject reads a JSON object from the decoder, calling fn for each key.
+// fn must consume the value of the key from the decoder. This lets us stream
+// the bulk price files, some of which are too big to load into memory at once.
+func walkObject(dec *json.Decoder, fn func(key string) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	return walkObjectBody(dec, fn)
+}
+
+// walkObjectBody is the same as walkObject for when the opening delimiter has
+// already been read from the decoder.
+func walkObjectBody(dec *json.Decoder, fn func(key string) error) error {
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		key, ok := t.(string)
+		if !ok {
+			return fmt.Errorf("expected object key, got %v", t)
+		}
+
+		err = fn(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := dec.Token()
+	return err
+}
+
+// walkArray reads a JSON array from the decoder, calling fn for each element.
+// fn must consume the element from the decoder.
+func walkArray(dec *json.Decoder, fn func() error) error {
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+
+	return walkArrayBody(dec, fn)
+}
+
+// walkArrayBody is the same as walkArray for when the opening delimiter has
+// already been read from the decoder.
+func walkArrayBody(dec *json.Decoder, fn func() error) error {
+	for dec.More() {
+		err := fn()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := dec.Token()
+	return err
+}
+
+func skipValue(dec *json.Decoder) error {
+	var v json.RawMessage
+	return dec.Decode(&v)
+}
+
+func expectDelim(dec *json.Decoder, d json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if t != d {
+		return fmt.Errorf("expected %s, got %v", d, t)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package pricestore
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+type awsProduct struct {
+	Sku           string            `json:"sku"`
+	ProductFamily string            `json:"productFamily"`
+	Attributes    map[string]string `json:"attributes"`
+}
+
+type awsTerm struct {
+	EffectiveDate   string `json:"effectiveDate"`
+	PriceDimensions map[string]struct {
+		Description  string            `json:"description"`
+		BeginRange   string            `json:"beginRange"`
+		EndRange     string            `json:"endRange"`
+		Unit         string            `json:"unit"`
+		PricePerUnit map[string]string `json:"pricePerUnit"`
+	} `json:"priceDimensions"`
+	TermAttributes map[string]string `json:"termAttributes"`
+}
+
+var awsPurchaseOptions = map[string]string{
+	"OnDemand": "on_demand",
+	"Reserved": "reserved",
+}
+
+// ImportAWSPriceList imports an AWS Price List bulk JSON offer file, e.g.
+// https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonEC2/current/index.json
+func (s *Store) ImportAWSPriceList(r io.Reader) (int, error) {
+	dec := json.NewDecoder(r)
+
+	var offerCode string
+	products := make(map[string]*awsProduct)
+	prices := make(map[string][]*Price)
+
+	err := walkObject(dec, func(key string) error {
+		switch key {
+		case "offerCode":
+			return dec.Decode(&offerCode)
+		case "products":
+			return walkObject(dec, func(sku string) error {
+				var p awsProduct
+				if err := dec.Decode(&p); err != nil {
+					return err
+				}
+				products[sku] = &p
+				return nil
+			})
+		case "terms":
+			return walkObject(dec, func(termType string) error {
+				return walkObject(dec, func(sku string) error {
+					var terms map[string]awsTerm
+					if err := dec.Decode(&terms); err != nil {
+						return err
+					}
+					prices[sku] = append(prices[sku], awsTermPrices(termType, terms)...)
+					return nil
+				})
+			})
+		default:
+			return skipValue(dec)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	skus := make([]string, 0, len(products))
+	for sku := range products {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+
+	for _, sku := range skus {
+		p := products[sku]
+
+		service := p.Attributes["servicecode"]
+		if service == "" {
+			service = offerCode
+		}
+
+		product := &Product{
+			VendorName:    "aws",
+			Service:       service,
+			ProductFamily: p.ProductFamily,
+			Region:        p.Attributes["regionCode"],
+			Sku:           sku,
+			Attributes:    sortedAttributes(p.Attributes),
+			Prices:        append([]*Price{}, prices[sku]...),
+		}
+		product.ProductHash = productHash(product.VendorName, sku)
+
+		setPriceHashes(product)
+		s.AddProduct(product)
+	}
+
+	return len(skus), nil
+}
+
+func awsTermPrices(termType string, terms map[string]awsTerm) []*Price {
+	prices := make([]*Price, 0)
+
+	purchaseOption, ok := awsPurchaseOptions[termType]
+	if !ok {
+		return prices
+	}
+
+	for _, term := range terms {
+		for _, d := range term.PriceDimensions {
+			endUsageAmount := d.EndRange
+			if endUsageAmount == "Inf" {
+				endUsageAmount = ""
+			}
+
+			prices = append(prices, &Price{
+				PurchaseOption:     purchaseOption,
+				Unit:               d.Unit,
+				Description:        d.Description,
+				StartUsageAmount:   d.BeginRange,
+				EndUsageAmount:     endUsageAmount,
+				TermLength:         term.TermAttributes["LeaseContractLength"],
+				TermPurchaseOption: term.TermAttributes["PurchaseOption"],
+				TermOfferingClass:  term.TermAttributes["OfferingClass"],
+				EffectiveDateStart: effectiveDate(term.EffectiveDate),
+				Amounts:            d.PricePerUnit,
+			})
+		}
+	}
+
+	return prices
+}
+
+func sortedAttributes(m map[string]string) []*Attribute {
+	attrs := make([]*Attribute, 0, len(m))
+	for k, v := range m {
+		attrs = append(attrs, &Attribute{Key: k, Value: v})
+	}
+
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].Key < attrs[j].Key
+	})
+
+	return attrs
+}
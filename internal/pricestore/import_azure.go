@@ -0,0 +1,127 @@
+package pricestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type azureItem struct {
+	CurrencyCode       string      `json:"currencyCode"`
+	TierMinimumUnits   json.Number `json:"tierMinimumUnits"`
+	RetailPrice        json.Number `json:"retailPrice"`
+	ArmRegionName      string      `json:"armRegionName"`
+	Location           string      `json:"location"`
+	EffectiveStartDate string      `json:"effectiveStartDate"`
+	MeterID            string      `json:"meterId"`
+	MeterName          string      `json:"meterName"`
+	ProductID          string      `json:"productId"`
+	SkuID              string      `json:"skuId"`
+	ProductName        string      `json:"productName"`
+	SkuName            string      `json:"skuName"`
+	ServiceName        string      `json:"serviceName"`
+	ServiceID          string      `json:"serviceId"`
+	ServiceFamily      string      `json:"serviceFamily"`
+	UnitOfMeasure      string      `json:"unitOfMeasure"`
+	Type               string      `json:"type"`
+	ArmSkuName         string      `json:"armSkuName"`
+	ReservationTerm    string      `json:"reservationTerm"`
+}
+
+// ImportAzureRetailPrices imports prices from the Azure Retail Prices API,
+// https://prices.azure.com/api/retail/prices. The file can either be a page
+// of the API response with an Items array, or an array of the items.
+func (s *Store) ImportAzureRetailPrices(r io.Reader) (int, error) {
+	dec := json.NewDecoder(r)
+
+	products := make(map[string]*Product)
+	order := make([]string, 0)
+
+	importItem := func() error {
+		var item azureItem
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+
+		p := azureProduct(item)
+		existing, ok := products[p.ProductHash]
+		if !ok {
+			products[p.ProductHash] = p
+			order = append(order, p.ProductHash)
+			existing = p
+		}
+
+		existing.Prices = append(existing.Prices, azurePrice(item))
+		return nil
+	}
+
+	t, err := dec.Token()
+	if err != nil {
+		return 0, err
+	}
+
+	switch t {
+	case json.Delim('['):
+		err = walkArrayBody(dec, importItem)
+	case json.Delim('{'):
+		err = walkObjectBody(dec, func(key string) error {
+			if key == "Items" {
+				return walkArray(dec, importItem)
+			}
+			return skipValue(dec)
+		})
+	default:
+		err = fmt.Errorf("expected an object or array, got %v", t)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	for _, h := range order {
+		p := products[h]
+		setPriceHashes(p)
+		s.AddProduct(p)
+	}
+
+	return len(order), nil
+}
+
+func azureProduct(item azureItem) *Product {
+	p := &Product{
+		VendorName:    "azure",
+		Service:       item.ServiceName,
+		ProductFamily: item.ServiceFamily,
+		Region:        item.ArmRegionName,
+		Sku:           fmt.Sprintf("%s/%s", item.SkuID, item.MeterID),
+		Attributes: sortedAttributes(map[string]string{
+			"productId":     item.ProductID,
+			"productName":   item.ProductName,
+			"skuId":         item.SkuID,
+			"skuName":       item.SkuName,
+			"serviceId":     item.ServiceID,
+			"meterId":       item.MeterID,
+			"meterName":     item.MeterName,
+			"armSkuName":    item.ArmSkuName,
+			"armRegionName": item.ArmRegionName,
+			"location":      item.Location,
+		}),
+		Prices: make([]*Price, 0),
+	}
+	p.ProductHash = productHash(p.VendorName, p.Region, p.Sku)
+
+	return p
+}
+
+func azurePrice(item azureItem) *Price {
+	return &Price{
+		PurchaseOption:     item.Type,
+		Unit:               item.UnitOfMeasure,
+		StartUsageAmount:   item.TierMinimumUnits.String(),
+		TermLength:         item.ReservationTerm,
+		EffectiveDateStart: effectiveDate(item.EffectiveStartDate),
+		Amounts: map[string]string{
+			strings.ToUpper(item.CurrencyCode): item.RetailPrice.String(),
+		},
+	}
+}
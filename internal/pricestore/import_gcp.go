@@ -0,0 +1,152 @@
+package pricestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+type gcpSku struct {
+	Name        string `json:"name"`
+	SkuID       string `json:"skuId"`
+	Description string `json:"description"`
+	Category    struct {
+		ServiceDisplayName string `json:"serviceDisplayName"`
+		ResourceFamily     string `json:"resourceFamily"`
+		ResourceGroup      string `json:"resourceGroup"`
+		UsageType          string `json:"usageType"`
+	} `json:"category"`
+	ServiceRegions []string `json:"serviceRegions"`
+	PricingInfo    []struct {
+		EffectiveTime     string `json:"effectiveTime"`
+		PricingExpression struct {
+			UsageUnit   string `json:"usageUnit"`
+			TieredRates []struct {
+				StartUsageAmount json.Number `json:"startUsageAmount"`
+				UnitPrice        struct {
+					CurrencyCode string      `json:"currencyCode"`
+					Units        json.Number `json:"units"`
+					Nanos        json.Number `json:"nanos"`
+				} `json:"unitPrice"`
+			} `json:"tieredRates"`
+		} `json:"pricingExpression"`
+	} `json:"pricingInfo"`
+}
+
+// ImportGCPSkus imports SKUs from the GCP Cloud Billing Catalog API,
+// https://cloud.google.com/billing/docs/reference/rest/v1/services.skus/list.
+// The file can either be a page of the API response with a skus array, or an
+// array of the SKUs. A product is added for each of the SKU's service regions.
+func (s *Store) ImportGCPSkus(r io.Reader) (int, error) {
+	dec := json.NewDecoder(r)
+	count := 0
+
+	importSku := func() error {
+		var sku gcpSku
+		if err := dec.Decode(&sku); err != nil {
+			return err
+		}
+
+		for _, p := range gcpProducts(sku) {
+			setPriceHashes(p)
+			s.AddProduct(p)
+			count++
+		}
+
+		return nil
+	}
+
+	t, err := dec.Token()
+	if err != nil {
+		return 0, err
+	}
+
+	switch t {
+	case json.Delim('['):
+		err = walkArrayBody(dec, importSku)
+	case json.Delim('{'):
+		err = walkObjectBody(dec, func(key string) error {
+			if key == "skus" {
+				return walkArray(dec, importSku)
+			}
+			return skipValue(dec)
+		})
+	default:
+		err = fmt.Errorf("expected an object or array, got %v", t)
+	}
+	if err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+func gcpProducts(sku gcpSku) []*Product {
+	products := make([]*Product, 0, len(sku.ServiceRegions))
+
+	// The pricing info is a timeline of the prices when the SKUs are listed
+	// with a start and end time. If any of the pricing info doesn't have an
+	// effective time then only the first pricing info, the current price, is
+	// used.
+	pricingInfo := sku.PricingInfo
+	for _, info := range pricingInfo {
+		if effectiveDate(info.EffectiveTime) == "" && len(pricingInfo) > 1 {
+			pricingInfo = pricingInfo[:1]
+			break
+		}
+	}
+
+	prices := make([]*Price, 0)
+	for _, info := range pricingInfo {
+		start := effectiveDate(info.EffectiveTime)
+		expr := info.PricingExpression
+
+		for _, rate := range expr.TieredRates {
+			units, _ := decimal.NewFromString(rate.UnitPrice.Units.String())
+			nanos, _ := decimal.NewFromString(rate.UnitPrice.Nanos.String())
+			amount := units.Add(nanos.Shift(-9))
+
+			prices = append(prices, &Price{
+				PurchaseOption:     sku.Category.UsageType,
+				Unit:               expr.UsageUnit,
+				StartUsageAmount:   rate.StartUsageAmount.String(),
+				EffectiveDateStart: start,
+				Amounts: map[string]string{
+					strings.ToUpper(rate.UnitPrice.CurrencyCode): amount.String(),
+				},
+			})
+		}
+	}
+
+	for _, region := range sku.ServiceRegions {
+		p := &Product{
+			VendorName:    "gcp",
+			Service:       sku.Category.ServiceDisplayName,
+			ProductFamily: sku.Category.ResourceFamily,
+			Region:        region,
+			Sku:           sku.SkuID,
+			Attributes: sortedAttributes(map[string]string{
+				"description":   sku.Description,
+				"resourceGroup": sku.Category.ResourceGroup,
+			}),
+			Prices: make([]*Price, 0, len(prices)),
+		}
+		p.ProductHash = productHash(p.VendorName, p.Region, p.Sku)
+
+		for _, price := range prices {
+			copied := *price
+			copied.Amounts = make(map[string]string, len(price.Amounts))
+			for currency, amount := range price.Amounts {
+				copied.Amounts[currency] = amount
+			}
+			p.Prices = append(p.Prices, &copied)
+		}
+
+		products = append(products, p)
+	}
+
+	return products
+}
@@ -0,0 +1,92 @@
+package pricestore
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportAWSPriceList(t *testing.T) {
+	s := NewStore()
+
+	count, err := s.ImportFile("aws", "testdata/aws_price_list.json")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	products := s.Query(&schema.ProductFilter{
+		VendorName:       util.StrPtr("aws"),
+		Service:          util.StrPtr("AmazonEC2"),
+		Region:           util.StrPtr("us-east-1"),
+		AttributeFilters: []*schema.AttributeFilter{{Key: "instanceType", Value: util.StrPtr("t3.micro")}},
+	}, &schema.PriceFilter{
+		PurchaseOption:     util.StrPtr("reserved"),
+		TermLength:         util.StrPtr("1yr"),
+		TermPurchaseOption: util.StrPtr("No Upfront"),
+		TermOfferingClass:  util.StrPtr("standard"),
+	})
+
+	require.Len(t, products, 1)
+	assert.Equal(t, "Compute Instance", products[0].ProductFamily)
+	require.Len(t, products[0].Prices, 1)
+	assert.Equal(t, "0.0065000000", products[0].Prices[0].Amounts["USD"])
+	assert.Equal(t, "", products[0].Prices[0].EndUsageAmount)
+}
+
+func TestImportAzureRetailPrices(t *testing.T) {
+	s := NewStore()
+
+	count, err := s.ImportFile("azure", "testdata/azure_retail_prices.json")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	products := s.Query(&schema.ProductFilter{
+		VendorName:       util.StrPtr("azure"),
+		Service:          util.StrPtr("Virtual Machines"),
+		Region:           util.StrPtr("eastus"),
+		AttributeFilters: []*schema.AttributeFilter{{Key: "armSkuName", Value: util.StrPtr("Standard_B1s")}},
+	}, &schema.PriceFilter{
+		PurchaseOption: util.StrPtr("Consumption"),
+	})
+
+	require.Len(t, products, 1)
+	require.Len(t, products[0].Prices, 1)
+	assert.Equal(t, "0.0104", products[0].Prices[0].Amounts["USD"])
+	assert.Equal(t, "1 Hour", products[0].Prices[0].Unit)
+	assert.Equal(t, "2020-06-01", products[0].Prices[0].EffectiveDateStart)
+}
+
+func TestImportGCPSkus(t *testing.T) {
+	s := NewStore()
+
+	count, err := s.ImportFile("gcp", "testdata/gcp_skus.json")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	products := s.Query(&schema.ProductFilter{
+		VendorName:       util.StrPtr("gcp"),
+		Service:          util.StrPtr("Compute Engine"),
+		Region:           util.StrPtr("us-east1"),
+		AttributeFilters: []*schema.AttributeFilter{{Key: "description", ValueRegex: util.StrPtr("/^E2 Instance Core/")}},
+	}, &schema.PriceFilter{
+		PurchaseOption: util.StrPtr("OnDemand"),
+	})
+
+	require.Len(t, products, 1)
+	require.Len(t, products[0].Prices, 1)
+	assert.Equal(t, "0.02181159", products[0].Prices[0].Amounts["USD"])
+}
+
+func TestImportIsIdempotent(t *testing.T) {
+	s := NewStore()
+
+	_, err := s.ImportFile("aws", "testdata/aws_price_list.json")
+	require.NoError(t, err)
+	_, err = s.ImportFile("aws", "testdata/aws_price_list.json")
+	require.NoError(t, err)
+
+	assert.Len(t, s.Products, 2)
+	assert.Len(t, s.Products[0].Prices, 2)
+}
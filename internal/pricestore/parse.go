@@ -0,0 +1,48 @@
+package pricestore
+
+import (
+	"github.com/tidwall/gjson"
+)
+
+// ParseProduct converts a product returned from the Cloud Pricing API into a
+// store product. The amount of each price is read from the currency field.
+func ParseProduct(r gjson.Result, currency string) *Product {
+	p := &Product{
+		ProductHash:   r.Get("productHash").String(),
+		VendorName:    r.Get("vendorName").String(),
+		Service:       r.Get("service").String(),
+		ProductFamily: r.Get("productFamily").String(),
+		Region:        r.Get("region").String(),
+		Sku:           r.Get("sku").String(),
+		Attributes:    make([]*Attribute, 0),
+		Prices:        make([]*Price, 0),
+	}
+
+	for _, a := range r.Get("attributes").Array() {
+		p.Attributes = append(p.Attributes, &Attribute{
+			Key:   a.Get("key").String(),
+			Value: a.Get("value").String(),
+		})
+	}
+
+	for _, price := range r.Get("prices").Array() {
+		p.Prices = append(p.Prices, &Price{
+			PriceHash:          price.Get("priceHash").String(),
+			PurchaseOption:     price.Get("purchaseOption").String(),
+			Unit:               price.Get("unit").String(),
+			Description:        price.Get("description").String(),
+			StartUsageAmount:   price.Get("startUsageAmount").String(),
+			EndUsageAmount:     price.Get("endUsageAmount").String(),
+			TermLength:         price.Get("termLength").String(),
+			TermPurchaseOption: price.Get("termPurchaseOption").String(),
+			TermOfferingClass:  price.Get("termOfferingClass").String(),
+			EffectiveDateStart: price.Get("effectiveDateStart").String(),
+			EffectiveDateEnd:   price.Get("effectiveDateEnd").String(),
+			Amounts: map[string]string{
+				currency: price.Get(currency).String(),
+			},
+		})
+	}
+
+	return p
+}
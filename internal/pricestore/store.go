@@ -0,0 +1,226 @@
+package pricestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/infracost/infracost/internal/version"
+	"github.com/pkg/errors"
+)
+
+const minStoreVersion = "0.1"
+const maxStoreVersion = "0.1"
+
+var storeVersion = "0.1"
+
+// EffectiveDateLayout is the format of the effective dates of prices.
+const EffectiveDateLayout = "2006-01-02"
+
+type Attribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Price is a price of a product. EffectiveDateStart and EffectiveDateEnd are
+// the dates, in YYYY-MM-DD format, that the price applies from (inclusive)
+// and until (exclusive). An empty date means the range is open at that end.
+type Price struct {
+	PriceHash          string            `json:"priceHash"`
+	PurchaseOption     string            `json:"purchaseOption,omitempty"`
+	Unit               string            `json:"unit,omitempty"`
+	Description        string            `json:"description,omitempty"`
+	StartUsageAmount   string            `json:"startUsageAmount,omitempty"`
+	EndUsageAmount     string            `json:"endUsageAmount,omitempty"`
+	TermLength         string            `json:"termLength,omitempty"`
+	TermPurchaseOption string            `json:"termPurchaseOption,omitempty"`
+	TermOfferingClass  string            `json:"termOfferingClass,omitempty"`
+	EffectiveDateStart string            `json:"effectiveDateStart,omitempty"`
+	EffectiveDateEnd   string            `json:"effectiveDateEnd,omitempty"`
+	Amounts            map[string]string `json:"amounts"`
+}
+
+type Product struct {
+	ProductHash   string       `json:"productHash"`
+	VendorName    string       `json:"vendorName"`
+	Service       string       `json:"service"`
+	ProductFamily string       `json:"productFamily"`
+	Region        string       `json:"region"`
+	Sku           string       `json:"sku"`
+	Attributes    []*Attribute `json:"attributes"`
+	Prices        []*Price     `json:"prices"`
+}
+
+// Store is a local set of products and prices that can answer the same
+// product and price filter queries as the Cloud Pricing API.
+type Store struct {
+	Version       string     `json:"version"`
+	TimeGenerated time.Time  `json:"timeGenerated"`
+	Products      []*Product `json:"products"`
+
+	productsByHash    map[string]*Product
+	productsByService map[string][]*Product
+}
+
+func NewStore() *Store {
+	s := &Store{
+		Version:       storeVersion,
+		TimeGenerated: time.Now(),
+		Products:      make([]*Product, 0),
+	}
+	s.buildIndex()
+
+	return s
+}
+
+func Load(path string) (*Store, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading price snapshot file")
+	}
+
+	var s Store
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing price snapshot file")
+	}
+
+	if !version.IsSupportedFileVersion(s.Version, minStoreVersion, maxStoreVersion) {
+		return nil, fmt.Errorf("Invalid price snapshot file version. Supported versions are %s ≤ x ≤ %s", minStoreVersion, maxStoreVersion)
+	}
+
+	s.buildIndex()
+
+	return &s, nil
+}
+
+func (s *Store) Save(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return errors.Wrap(err, "Error generating price snapshot file")
+	}
+
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// AddProduct adds a product to the store. If a product with the same hash
+// already exists then any new prices are merged into it. A price with a
+// different effective start date to the existing price with the same hash is
+// kept as another version of the price, so loading price files from different
+// dates builds up the price history.
+func (s *Store) AddProduct(p *Product) {
+	existing, ok := s.productsByHash[p.ProductHash]
+	if !ok {
+		s.Products = append(s.Products, p)
+		s.indexProduct(p)
+		setEffectiveDateEnds(p)
+		return
+	}
+
+	mergeProduct(existing, p)
+}
+
+// mergeProduct merges the prices of p into the existing product with the same
+// hash.
+func mergeProduct(existing *Product, p *Product) {
+	for _, price := range p.Prices {
+		found := false
+		for _, existingPrice := range existing.Prices {
+			if existingPrice.PriceHash == price.PriceHash && sameEffectiveDate(existingPrice, price) {
+				for currency, amount := range price.Amounts {
+					existingPrice.Amounts[currency] = amount
+				}
+				if existingPrice.EffectiveDateStart == "" {
+					existingPrice.EffectiveDateStart = price.EffectiveDateStart
+				}
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			existing.Prices = append(existing.Prices, price)
+		}
+	}
+
+	setEffectiveDateEnds(existing)
+}
+
+// sameEffectiveDate returns true if the prices are the same version of a
+// price. Prices without an effective start date are treated as the same
+// version as any other price so files without dates update prices in place.
+func sameEffectiveDate(a *Price, b *Price) bool {
+	return a.EffectiveDateStart == "" || b.EffectiveDateStart == "" || a.EffectiveDateStart == b.EffectiveDateStart
+}
+
+// setEffectiveDateEnds ends each version of a price on the day the next
+// version starts.
+func setEffectiveDateEnds(p *Product) {
+	versions := make(map[string][]*Price)
+	for _, price := range p.Prices {
+		if price.EffectiveDateStart != "" {
+			versions[price.PriceHash] = append(versions[price.PriceHash], price)
+		}
+	}
+
+	for _, prices := range versions {
+		if len(prices) < 2 {
+			continue
+		}
+
+		sort.Slice(prices, func(i, j int) bool {
+			return prices[i].EffectiveDateStart < prices[j].EffectiveDateStart
+		})
+
+		for i := 0; i < len(prices)-1; i++ {
+			prices[i].EffectiveDateEnd = prices[i+1].EffectiveDateStart
+		}
+	}
+}
+
+func (s *Store) buildIndex() {
+	s.productsByHash = make(map[string]*Product, len(s.Products))
+	s.productsByService = make(map[string][]*Product)
+
+	for _, p := range s.Products {
+		s.indexProduct(p)
+	}
+}
+
+func (s *Store) indexProduct(p *Product) {
+	if p.ProductHash != "" {
+		s.productsByHash[p.ProductHash] = p
+	}
+
+	k := serviceKey(p.VendorName, p.Service)
+	s.productsByService[k] = append(s.productsByService[k], p)
+}
+
+// HasPriceHistory returns true if any of the prices have effective dates.
+func (s *Store) HasPriceHistory() bool {
+	for _, p := range s.Products {
+		for _, price := range p.Prices {
+			if price.EffectiveDateStart != "" || price.EffectiveDateEnd != "" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (p *Product) Attribute(key string) (string, bool) {
+	for _, a := range p.Attributes {
+		if a.Key == key {
+			return a.Value, true
+		}
+	}
+
+	return "", false
+}
+
+func serviceKey(vendorName, service string) string {
+	return fmt.Sprintf("%s/%s", vendorName, service)
+}
@@ -0,0 +1,192 @@
+package pricingserver
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/graphql-go/graphql"
+	"github.com/infracost/infracost/internal/pricestore"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+)
+
+// defaultCurrencies are always in the schema so the CLI gets no price instead
+// of a query error when it asks for a currency that isn't in the price files.
+var defaultCurrencies = []string{
+	"AUD", "BRL", "CAD", "CHF", "CNY", "DKK", "EUR", "GBP", "HKD", "INR", "JPY",
+	"KRW", "MXN", "NOK", "NZD", "SEK", "SGD", "USD", "ZAR",
+}
+
+var currencyFieldRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
+var attributeFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "AttributeFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"key":         {Type: graphql.NewNonNull(graphql.String)},
+		"value":       {Type: graphql.String},
+		"value_regex": {Type: graphql.String},
+	},
+})
+
+var productFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "ProductFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"vendorName":       {Type: graphql.String},
+		"service":          {Type: graphql.String},
+		"productFamily":    {Type: graphql.String},
+		"region":           {Type: graphql.String},
+		"sku":              {Type: graphql.String},
+		"attributeFilters": {Type: graphql.NewList(attributeFilterInput)},
+	},
+})
+
+var priceFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "PriceFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"purchaseOption":     {Type: graphql.String},
+		"unit":               {Type: graphql.String},
+		"description":        {Type: graphql.String},
+		"description_regex":  {Type: graphql.String},
+		"startUsageAmount":   {Type: graphql.String},
+		"endUsageAmount":     {Type: graphql.String},
+		"termLength":         {Type: graphql.String},
+		"termPurchaseOption": {Type: graphql.String},
+		"termOfferingClass":  {Type: graphql.String},
+		"effectiveDate":      {Type: graphql.String},
+	},
+})
+
+var attributeType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Attribute",
+	Fields: graphql.Fields{
+		"key":   {Type: graphql.String},
+		"value": {Type: graphql.String},
+	},
+})
+
+// newSchema returns the products(filter:) and prices(filter:) schema of the
+// Cloud Pricing API. The Price type has a field for the amount in each of the
+// currencies.
+func newSchema(s *Server, currencies []string) (graphql.Schema, error) {
+	priceFields := graphql.Fields{
+		"priceHash":          {Type: graphql.String},
+		"purchaseOption":     {Type: graphql.String},
+		"unit":               {Type: graphql.String},
+		"description":        {Type: graphql.String},
+		"startUsageAmount":   {Type: graphql.String},
+		"endUsageAmount":     {Type: graphql.String},
+		"termLength":         {Type: graphql.String},
+		"termPurchaseOption": {Type: graphql.String},
+		"termOfferingClass":  {Type: graphql.String},
+		"effectiveDateStart": {Type: graphql.String},
+		"effectiveDateEnd":   {Type: graphql.String},
+	}
+
+	for _, currency := range append(defaultCurrencies, currencies...) {
+		if !currencyFieldRegex.MatchString(currency) {
+			continue
+		}
+
+		priceFields[currency] = &graphql.Field{Type: graphql.String, Resolve: resolveAmount(currency)}
+	}
+
+	priceType := graphql.NewObject(graphql.ObjectConfig{
+		Name:   "Price",
+		Fields: priceFields,
+	})
+
+	productType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Product",
+		Fields: graphql.Fields{
+			"productHash":   {Type: graphql.String},
+			"vendorName":    {Type: graphql.String},
+			"service":       {Type: graphql.String},
+			"productFamily": {Type: graphql.String},
+			"region":        {Type: graphql.String},
+			"sku":           {Type: graphql.String},
+			"attributes":    {Type: graphql.NewList(attributeType)},
+			"prices": {
+				Type: graphql.NewList(priceType),
+				Args: graphql.FieldConfigArgument{
+					"filter": {Type: priceFilterInput},
+				},
+				Resolve: resolvePrices,
+			},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"products": {
+				Type: graphql.NewList(productType),
+				Args: graphql.FieldConfigArgument{
+					"filter": {Type: graphql.NewNonNull(productFilterInput)},
+				},
+				Resolve: s.resolveProducts,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+func (s *Server) resolveProducts(p graphql.ResolveParams) (interface{}, error) {
+	var productFilter schema.ProductFilter
+	err := decodeArgument(p.Args["filter"], &productFilter)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid product filter")
+	}
+
+	return s.DB.Products(&productFilter)
+}
+
+func resolvePrices(p graphql.ResolveParams) (interface{}, error) {
+	product, ok := p.Source.(*pricestore.Product)
+	if !ok {
+		return nil, nil
+	}
+
+	var priceFilter *schema.PriceFilter
+	if filter, ok := p.Args["filter"]; ok && filter != nil {
+		priceFilter = &schema.PriceFilter{}
+		err := decodeArgument(filter, priceFilter)
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid price filter")
+		}
+	}
+
+	return product.FilterPrices(priceFilter), nil
+}
+
+// resolveAmount returns the amount of the price in the currency, or null if
+// the price files didn't have the price in that currency.
+func resolveAmount(currency string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		price, ok := p.Source.(*pricestore.Price)
+		if !ok {
+			return nil, nil
+		}
+
+		if amount, ok := price.Amounts[currency]; ok {
+			return amount, nil
+		}
+
+		return nil, nil
+	}
+}
+
+// decodeArgument converts an argument value into a filter struct by going
+// through its JSON representation.
+func decodeArgument(arg interface{}, v interface{}) error {
+	if arg == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(arg)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,155 @@
+package pricingserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/infracost/infracost/internal/pricestore"
+	"github.com/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxRequestSize limits the size of the request bodies the server will read.
+const maxRequestSize = 10 << 20
+
+// Timeouts of the HTTP server. The queries from the CLI are small, so these
+// only need to allow for slow clients and large batches of queries.
+const (
+	readTimeout  = 30 * time.Second
+	writeTimeout = 60 * time.Second
+	idleTimeout  = 120 * time.Second
+)
+
+// Server serves the GraphQL products and prices schema of the Cloud Pricing
+// API from a local price database, so infracost can be pointed at it using the
+// pricing_api_endpoint setting.
+type Server struct {
+	DB     *pricestore.DB
+	schema graphql.Schema
+}
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+func NewServer(db *pricestore.DB) (*Server, error) {
+	currencies, err := db.Currencies()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading price database currencies")
+	}
+
+	s := &Server{DB: db}
+
+	s.schema, err = newSchema(s, currencies)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating GraphQL schema")
+	}
+
+	return s, nil
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graphql", s.handleGraphQL)
+	mux.HandleFunc("/event", s.handleEvent)
+	mux.HandleFunc("/health", s.handleHealth)
+
+	return mux
+}
+
+// HTTPServer returns an HTTP server for the handler with timeouts, so slow or
+// idle clients can't hold connections open indefinitely.
+func (s *Server) HTTPServer(addr string) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: readTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+}
+
+// handleGraphQL handles single queries and the batched queries that are sent
+// by the infracost CLI as a JSON array.
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "Method not allowed"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+
+	if len(body) > 0 && body[0] == '[' {
+		var reqs []graphQLRequest
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+			return
+		}
+
+		results := make([]*graphql.Result, 0, len(reqs))
+		for _, req := range reqs {
+			results = append(results, s.execute(req))
+		}
+
+		writeJSON(w, http.StatusOK, results)
+		return
+	}
+
+	var req graphQLRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.execute(req))
+}
+
+// handleEvent accepts the usage events sent by the CLI. Nothing is recorded
+// since the server is meant to run without sending anything out.
+func (s *Server) handleEvent(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	count, err := s.DB.ProductCount()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"status": "error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":   "ok",
+		"products": count,
+	})
+}
+
+func (s *Server) execute(req graphQLRequest) *graphql.Result {
+	return graphql.Do(graphql.Params{
+		Schema:         s.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		log.Debugf("Error writing response: %s", err)
+	}
+}
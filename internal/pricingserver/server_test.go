@@ -0,0 +1,232 @@
+package pricingserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/pricestore"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testServer(t *testing.T) *Server {
+	db, err := pricestore.OpenDB(filepath.Join(t.TempDir(), "prices.db"), false)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	err = db.AddProducts([]*pricestore.Product{
+		{
+			ProductHash: "p1",
+			VendorName:  "aws",
+			Service:     "AmazonEC2",
+			Region:      "us-east-1",
+			Attributes:  []*pricestore.Attribute{{Key: "instanceType", Value: "t3.micro"}},
+			Prices: []*pricestore.Price{
+				{PriceHash: "p1-od", PurchaseOption: "on_demand", Unit: "Hrs", Amounts: map[string]string{"USD": "0.0104"}},
+				{PriceHash: "p1-ri", PurchaseOption: "reserved", Unit: "Hrs", Amounts: map[string]string{"USD": "0.0065"}},
+				{PriceHash: "p1-old", PurchaseOption: "spot", Unit: "Hrs", EffectiveDateStart: "2020-01-01", EffectiveDateEnd: "2021-01-01", Amounts: map[string]string{"USD": "0.0031"}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	s, err := NewServer(db)
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestClientQueries(t *testing.T) {
+	srv := httptest.NewServer(testServer(t).Handler())
+	defer srv.Close()
+
+	c := apiclient.NewPricingAPIClient(&config.Config{
+		PricingAPIEndpoint:   srv.URL,
+		PricingCacheDisabled: true,
+	})
+
+	r := &schema.Resource{
+		Name: "aws_instance.web",
+		CostComponents: []*schema.CostComponent{
+			{
+				Name: "Instance usage",
+				ProductFilter: &schema.ProductFilter{
+					VendorName:       util.StrPtr("aws"),
+					Service:          util.StrPtr("AmazonEC2"),
+					Region:           util.StrPtr("us-east-1"),
+					AttributeFilters: []*schema.AttributeFilter{{Key: "instanceType", Value: util.StrPtr("t3.micro")}},
+				},
+				PriceFilter: &schema.PriceFilter{PurchaseOption: util.StrPtr("on_demand")},
+			},
+			{
+				Name: "Missing",
+				ProductFilter: &schema.ProductFilter{
+					VendorName: util.StrPtr("aws"),
+					Service:    util.StrPtr("AmazonRDS"),
+				},
+			},
+		},
+	}
+
+	results, err := c.RunQueries(r)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "p1-od", results[0].Result.Get("data.products.0.prices.0.priceHash").String())
+	assert.Equal(t, "0.0104", results[0].Result.Get("data.products.0.prices.0.USD").String())
+	assert.Equal(t, int64(1), results[0].Result.Get("data.products.0.prices.#").Int())
+	assert.Equal(t, int64(0), results[1].Result.Get("data.products.#").Int())
+}
+
+func TestExecute(t *testing.T) {
+	s := testServer(t)
+
+	tests := map[string]struct {
+		query    string
+		vars     map[string]interface{}
+		expected string
+	}{
+		"inline filters and aliases": {
+			query: `{
+				p: products(filter: {vendorName: "aws", attributeFilters: [{key: "instanceType", value_regex: "/T3\\.MICRO/i"}]}) {
+					region
+					reserved: prices(filter: {purchaseOption: "reserved"}) { priceHash EUR }
+				}
+			}`,
+			expected: `{"data":{"p":[{"region":"us-east-1","reserved":[{"EUR":null,"priceHash":"p1-ri"}]}]}}`,
+		},
+		"variables": {
+			query: `query($productFilter: ProductFilter!, $priceFilter: PriceFilter) {
+				products(filter: $productFilter) { prices(filter: $priceFilter) { priceHash USD } }
+			}`,
+			vars: map[string]interface{}{
+				"productFilter": map[string]interface{}{"vendorName": "aws", "region": "us-east-1"},
+				"priceFilter":   map[string]interface{}{"purchaseOption": "spot", "effectiveDate": "2020-06-01"},
+			},
+			expected: `{"data":{"products":[{"prices":[{"priceHash":"p1-old","USD":"0.0031"}]}]}}`,
+		},
+		"variable defaults": {
+			query: `query($productFilter: ProductFilter = {sku: "missing"}) {
+				products(filter: $productFilter) { productHash }
+			}`,
+			expected: `{"data":{"products":[]}}`,
+		},
+		"unknown field": {
+			query:    `{ products(filter: {vendorName: "aws"}) { colour } }`,
+			expected: `{"data":null,"errors":[{"message":"Cannot query field \"colour\" on type \"Product\".","locations":[{"line":1,"column":43}]}]}`,
+		},
+		"unknown query": {
+			query:    `{ resources { name } }`,
+			expected: `{"data":null,"errors":[{"message":"Cannot query field \"resources\" on type \"Query\".","locations":[{"line":1,"column":3}]}]}`,
+		},
+		"missing filter variable": {
+			query:    `query($productFilter: ProductFilter!) { products(filter: $productFilter) { sku } }`,
+			expected: `{"data":null,"errors":[{"message":"Variable \"$productFilter\" of required type \"ProductFilter!\" was not provided.","locations":[{"line":1,"column":7}]}]}`,
+		},
+		"missing filter argument": {
+			query:    `{ products { sku } }`,
+			expected: `{"data":null,"errors":[{"message":"Field \"products\" argument \"filter\" of type \"ProductFilter!\" is required but not provided.","locations":[{"line":1,"column":3}]}]}`,
+		},
+		"unknown filter field": {
+			query:    `{ products(filter: {colour: "red"}) { sku } }`,
+			expected: `{"data":null,"errors":[{"message":"Argument \"filter\" has invalid value {colour: \"red\"}.\nIn field \"colour\": Unknown field.","locations":[{"line":1,"column":20}]}]}`,
+		},
+		"wrong filter type": {
+			query:    `{ products(filter: {vendorName: ["aws"]}) { sku } }`,
+			expected: `{"data":null,"errors":[{"message":"Argument \"filter\" has invalid value {vendorName: [\"aws\"]}.\nIn field \"vendorName\": Expected type \"String\", found [\"aws\"].","locations":[{"line":1,"column":20}]}]}`,
+		},
+		"syntax error": {
+			query:    `{ products(filter: {vendorName: "aws"}) { sku }`,
+			expected: `{"data":null,"errors":[{"message":"Syntax Error GraphQL request (1:48) Expected Name, found EOF\n\n1: { products(filter: {vendorName: \"aws\"}) { sku }\n                                                  ^\n","locations":[{"line":1,"column":48}]}]}`,
+		},
+		"empty query": {
+			query:    ``,
+			expected: `{"data":null,"errors":[{"message":"Must provide an operation.","locations":[]}]}`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := s.execute(graphQLRequest{Query: tc.query, Variables: tc.vars})
+
+			rec := httptest.NewRecorder()
+			writeJSON(rec, 200, resp)
+
+			assert.JSONEq(t, tc.expected, rec.Body.String())
+		})
+	}
+}
+
+func TestHandleGraphQL(t *testing.T) {
+	handler := testServer(t).Handler()
+
+	tests := map[string]struct {
+		method         string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		"single query": {
+			method:         http.MethodPost,
+			body:           `{"query": "{ products(filter: {sku: \"missing\"}) { sku } }"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data":{"products":[]}}`,
+		},
+		"batched queries": {
+			method:         http.MethodPost,
+			body:           `[{"query": "{ products(filter: {sku: \"missing\"}) { sku } }"}, {"query": "{ products(filter: {region: \"us-east-1\"}) { productHash } }"}]`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"data":{"products":[]}},{"data":{"products":[{"productHash":"p1"}]}}]`,
+		},
+		"invalid JSON": {
+			method:         http.MethodPost,
+			body:           `{"query": `,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Invalid request body"}`,
+		},
+		"invalid batch": {
+			method:         http.MethodPost,
+			body:           `[{"query": 1}]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Invalid request body"}`,
+		},
+		"invalid variables": {
+			method:         http.MethodPost,
+			body:           `{"query": "query($f: ProductFilter!) { products(filter: $f) { sku } }", "variables": {"f": "aws"}}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data":null,"errors":[{"message":"Variable \"$f\" got invalid value \"aws\".\nExpected \"ProductFilter\", found not an object.","locations":[{"line":1,"column":7}]}]}`,
+		},
+		"GET": {
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   `{"error":"Method not allowed"}`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tc.method, "/graphql", strings.NewReader(tc.body)))
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assert.JSONEq(t, tc.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHTTPServerTimeouts(t *testing.T) {
+	srv := testServer(t).HTTPServer(":4000")
+
+	assert.Equal(t, ":4000", srv.Addr)
+	assert.NotZero(t, srv.ReadHeaderTimeout)
+	assert.NotZero(t, srv.ReadTimeout)
+	assert.NotZero(t, srv.WriteTimeout)
+	assert.NotZero(t, srv.IdleTimeout)
+}
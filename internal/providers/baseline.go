@@ -0,0 +1,67 @@
+package providers
+
+import (
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/cloudformation"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+)
+
+// LoadBaseline sets the past resources of the projects to the resources of the
+// project's compare_to path, so the diff is between that baseline and the
+// projects. The baseline can be anything the projects can be loaded from, e.g.
+// a Terraform state JSON file or another CloudFormation template. If it's a
+// CloudFormation change set, the past resources are the resources of the
+// template before the change set.
+func LoadBaseline(ctx *config.ProjectContext, projects []*schema.Project, usage map[string]*schema.UsageData) error {
+	path := ctx.ProjectConfig.CompareTo
+	if path == "" {
+		return nil
+	}
+
+	if cloudformation.IsChangeSet(path) {
+		return cloudformation.ApplyChangeSet(ctx, path, projects, usage)
+	}
+
+	baselineCfg := *ctx.ProjectConfig
+	baselineCfg.Path = path
+	baselineCfg.CompareTo = ""
+	baselineCfg.TerraformUseState = false
+
+	provider, err := Detect(config.NewProjectContext(ctx.RunContext, &baselineCfg))
+	if err != nil {
+		return errors.Wrap(err, "Error detecting the path type of --compare-to")
+	}
+
+	baselineProjects, err := provider.LoadResources(usage)
+	if err != nil {
+		return errors.Wrap(err, "Error loading --compare-to")
+	}
+
+	for _, p := range projects {
+		p.PastResources = nil
+		p.HasDiff = true
+
+		if b := matchingBaselineProject(baselineProjects, p, len(projects) == 1 && len(baselineProjects) == 1); b != nil {
+			p.PastResources = b.Resources
+		}
+	}
+
+	return nil
+}
+
+// matchingBaselineProject returns the baseline project with the same name as
+// the project, or if there's only one of each, the baseline project.
+func matchingBaselineProject(baselineProjects []*schema.Project, project *schema.Project, matchSingle bool) *schema.Project {
+	if matchSingle {
+		return baselineProjects[0]
+	}
+
+	for _, b := range baselineProjects {
+		if b.Name == project.Name {
+			return b
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package providers
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func resourceNames(resources []*schema.Resource) []string {
+	names := make([]string, 0, len(resources))
+	for _, r := range resources {
+		names = append(names, r.Name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func TestMatchingBaselineProject(t *testing.T) {
+	a := &schema.Project{Name: "a"}
+	b := &schema.Project{Name: "b"}
+
+	tests := []struct {
+		name        string
+		baseline    []*schema.Project
+		project     *schema.Project
+		matchSingle bool
+		expected    *schema.Project
+	}{
+		{"same name", []*schema.Project{a, b}, &schema.Project{Name: "b"}, false, b},
+		{"no matching name", []*schema.Project{a, b}, &schema.Project{Name: "c"}, false, nil},
+		{"single project", []*schema.Project{a}, &schema.Project{Name: "c"}, true, a},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Same(t, tt.expected, matchingBaselineProject(tt.baseline, tt.project, tt.matchSingle))
+		})
+	}
+}
+
+func TestLoadBaseline(t *testing.T) {
+	dir := t.TempDir()
+
+	before := writeTestFile(t, dir, "before.json", `{
+  "Resources": {
+    "OrdersTable": {"Type": "AWS::DynamoDB::Table", "Properties": {"BillingMode": "PAY_PER_REQUEST"}},
+    "AuditTable": {"Type": "AWS::DynamoDB::Table", "Properties": {"BillingMode": "PAY_PER_REQUEST"}}
+  }
+}`)
+
+	after := writeTestFile(t, dir, "after.json", `{
+  "Resources": {
+    "OrdersTable": {"Type": "AWS::DynamoDB::Table", "Properties": {"BillingMode": "PAY_PER_REQUEST"}},
+    "EventsTable": {"Type": "AWS::DynamoDB::Table", "Properties": {"BillingMode": "PAY_PER_REQUEST"}}
+  }
+}`)
+
+	changeSet := writeTestFile(t, dir, "change-set.json", `{
+  "ChangeSetName": "my-change-set",
+  "Changes": [
+    {"Type": "Resource", "ResourceChange": {"Action": "Add", "LogicalResourceId": "EventsTable", "ResourceType": "AWS::DynamoDB::Table"}},
+    {
+      "Type": "Resource",
+      "ResourceChange": {
+        "Action": "Remove",
+        "LogicalResourceId": "AuditTable",
+        "ResourceType": "AWS::DynamoDB::Table",
+        "BeforeContext": "{\"Properties\":{\"BillingMode\":\"PAY_PER_REQUEST\"}}"
+      }
+    }
+  ]
+}`)
+
+	tests := []struct {
+		name      string
+		compareTo string
+		expected  []string
+	}{
+		{"template", before, []string{"AuditTable", "OrdersTable"}},
+		{"change set", changeSet, []string{"AuditTable", "OrdersTable"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: after, CompareTo: tt.compareTo})
+
+			provider, err := Detect(ctx)
+			require.NoError(t, err)
+
+			projects, err := provider.LoadResources(map[string]*schema.UsageData{})
+			require.NoError(t, err)
+
+			err = LoadBaseline(ctx, projects, map[string]*schema.UsageData{})
+			require.NoError(t, err)
+
+			require.Len(t, projects, 1)
+			assert.True(t, projects[0].HasDiff)
+			assert.Equal(t, tt.expected, resourceNames(projects[0].PastResources))
+			assert.Equal(t, []string{"EventsTable", "OrdersTable"}, resourceNames(projects[0].Resources))
+		})
+	}
+}
+
+func TestLoadBaselineWithoutCompareTo(t *testing.T) {
+	project := &schema.Project{}
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{})
+
+	require.NoError(t, LoadBaseline(ctx, []*schema.Project{project}, nil))
+	assert.False(t, project.HasDiff)
+}
+
+func TestLoadBaselineInvalidPath(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{CompareTo: filepath.Join(t.TempDir(), "missing.json")})
+
+	err := LoadBaseline(ctx, []*schema.Project{{}}, nil)
+	assert.Error(t, err)
+}
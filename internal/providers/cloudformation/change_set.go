@@ -0,0 +1,244 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/awslabs/goformation/v4"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// changeSet is the output of aws cloudformation describe-change-set. The
+// BeforeContext of the resource changes is only included with the
+// --include-property-values flag.
+type changeSet struct {
+	ChangeSetName string `json:"ChangeSetName"`
+	Changes       []struct {
+		Type           string         `json:"Type"`
+		ResourceChange resourceChange `json:"ResourceChange"`
+	} `json:"Changes"`
+}
+
+type resourceChange struct {
+	Action            string `json:"Action"`
+	LogicalResourceID string `json:"LogicalResourceId"`
+	ResourceType      string `json:"ResourceType"`
+	BeforeContext     string `json:"BeforeContext"`
+}
+
+func loadChangeSet(path string) (*changeSet, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c changeSet
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// IsChangeSet returns true if the path is a CloudFormation change set JSON
+// file from aws cloudformation describe-change-set.
+func IsChangeSet(path string) bool {
+	c, err := loadChangeSet(path)
+	if err != nil {
+		return false
+	}
+
+	return c.ChangeSetName != "" && c.Changes != nil
+}
+
+// ApplyChangeSet sets the past resources of the projects to the resources of
+// the stack before the change set, so the diff shows the cost of the change
+// set. The project's path should be the template the change set was created
+// from. The resources before the change set are that template without the
+// added resources and with the previous properties of the modified and removed
+// resources from the change set.
+func ApplyChangeSet(ctx *config.ProjectContext, path string, projects []*schema.Project, usage map[string]*schema.UsageData) error {
+	c, err := loadChangeSet(path)
+	if err != nil {
+		return errors.Wrap(err, "Error reading CloudFormation change set file")
+	}
+
+	template, err := goformation.Open(ctx.ProjectConfig.Path)
+	if err != nil {
+		return errors.Wrap(err, "Error reading the CloudFormation template the change set was created from")
+	}
+
+	pastTemplate := c.pastTemplate(template)
+
+	_, pastResources, err := NewParser(ctx).parseTemplate(pastTemplate, usage)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing the CloudFormation template before the change set")
+	}
+
+	for _, p := range projects {
+		p.PastResources = pastResources
+		p.HasDiff = true
+	}
+
+	return nil
+}
+
+// pastTemplate returns the template as it was before the change set.
+func (c *changeSet) pastTemplate(template *cloudformation.Template) *cloudformation.Template {
+	past := cloudformation.NewTemplate()
+	for id, r := range template.Resources {
+		past.Resources[id] = r
+	}
+
+	for _, change := range c.Changes {
+		if change.Type != "Resource" {
+			continue
+		}
+
+		rc := change.ResourceChange
+		id := rc.LogicalResourceID
+
+		switch rc.Action {
+		case "Add":
+			delete(past.Resources, id)
+		case "Modify":
+			if rc.BeforeContext == "" {
+				log.Warnf("%s is modified by the change set but its previous properties aren't in the change set, so its cost is assumed to be unchanged. Use aws cloudformation describe-change-set --include-property-values to include them", id)
+				continue
+			}
+
+			r, err := rc.pastResource()
+			if err != nil {
+				log.Warnf("%s is modified by the change set but its previous properties can't be read, so its cost is assumed to be unchanged: %s", id, err)
+				continue
+			}
+
+			past.Resources[id] = r
+		case "Remove":
+			if rc.BeforeContext == "" {
+				log.Warnf("%s is removed by the change set but its properties aren't in the change set, so its cost isn't included in the diff. Use aws cloudformation describe-change-set --include-property-values to include them", id)
+				continue
+			}
+
+			r, err := rc.pastResource()
+			if err != nil {
+				log.Warnf("%s is removed by the change set but its properties can't be read, so its cost isn't included in the diff: %s", id, err)
+				continue
+			}
+
+			past.Resources[id] = r
+		}
+	}
+
+	return past
+}
+
+// pastResource returns the resource with the properties from the
+// BeforeContext of the change. CloudFormation gives numbers and booleans as
+// strings in the BeforeContext, so they are converted using the types of the
+// resource's properties.
+func (rc resourceChange) pastResource() (cloudformation.Resource, error) {
+	var before struct {
+		Properties map[string]interface{} `json:"Properties"`
+	}
+
+	err := json.Unmarshal([]byte(rc.BeforeContext), &before)
+	if err != nil {
+		return nil, err
+	}
+
+	var properties interface{} = before.Properties
+	if r, ok := cloudformation.AllResources()[rc.ResourceType]; ok {
+		properties = convertStrings(properties, reflect.TypeOf(r))
+	}
+
+	b, err := json.Marshal(map[string]interface{}{
+		"Resources": map[string]interface{}{
+			rc.LogicalResourceID: map[string]interface{}{
+				"Type":       rc.ResourceType,
+				"Properties": properties,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := goformation.ParseJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Resources[rc.LogicalResourceID], nil
+}
+
+// convertStrings converts the strings in v to numbers or booleans where the
+// field with the same JSON name in t is a number or boolean.
+func convertStrings(v interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			m[k] = item
+			if ft, ok := fieldType(t, k); ok {
+				m[k] = convertStrings(item, ft)
+			}
+		}
+		return m
+	case []interface{}:
+		if t.Kind() != reflect.Slice {
+			return v
+		}
+
+		s := make([]interface{}, len(val))
+		for i, item := range val {
+			s[i] = convertStrings(item, t.Elem())
+		}
+		return s
+	case string:
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			if f, err := strconv.ParseFloat(val, 64); err == nil {
+				return f
+			}
+		case reflect.Bool:
+			if b, err := strconv.ParseBool(val); err == nil {
+				return b
+			}
+		}
+	}
+
+	return v
+}
+
+// fieldType returns the type of the struct field with the JSON name, or the
+// element type if t is a map.
+func fieldType(t reflect.Type, name string) (reflect.Type, bool) {
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem(), true
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if strings.Split(f.Tag.Get("json"), ",")[0] == name {
+				return f.Type, true
+			}
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,240 @@
+package cloudformation
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/awslabs/goformation/v4"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChangeSet = `{
+  "ChangeSetName": "my-change-set",
+  "StackName": "my-stack",
+  "Changes": [
+    {
+      "Type": "Resource",
+      "ResourceChange": {
+        "Action": "Add",
+        "LogicalResourceId": "NewTable",
+        "ResourceType": "AWS::DynamoDB::Table"
+      }
+    },
+    {
+      "Type": "Resource",
+      "ResourceChange": {
+        "Action": "Modify",
+        "LogicalResourceId": "ExistingTable",
+        "ResourceType": "AWS::DynamoDB::Table"
+      }
+    },
+    {
+      "Type": "Resource",
+      "ResourceChange": {
+        "Action": "Remove",
+        "LogicalResourceId": "OldTable",
+        "ResourceType": "AWS::DynamoDB::Table"
+      }
+    }
+  ]
+}`
+
+func writeTestFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestIsChangeSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{"change set", testChangeSet, true},
+		{"empty change set", `{"ChangeSetName": "my-change-set", "Changes": []}`, true},
+		{"template", `{"AWSTemplateFormatVersion": "2010-09-09", "Resources": {}}`, false},
+		{"plan JSON", `{"format_version": "0.1", "planned_values": {}}`, false},
+		{"not JSON", "Resources:\n  Table:\n    Type: AWS::DynamoDB::Table\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsChangeSet(writeTestFile(t, "file.json", tt.content)))
+		})
+	}
+
+	assert.False(t, IsChangeSet(filepath.Join(t.TempDir(), "missing.json")))
+}
+
+const testTemplate = `{
+  "Resources": {
+    "NewTable": {
+      "Type": "AWS::DynamoDB::Table",
+      "Properties": {
+        "BillingMode": "PROVISIONED",
+        "ProvisionedThroughput": {"ReadCapacityUnits": 1, "WriteCapacityUnits": 1}
+      }
+    },
+    "ExistingTable": {
+      "Type": "AWS::DynamoDB::Table",
+      "Properties": {
+        "BillingMode": "PROVISIONED",
+        "ProvisionedThroughput": {"ReadCapacityUnits": 20, "WriteCapacityUnits": 10}
+      }
+    },
+    "UnchangedTable": {
+      "Type": "AWS::DynamoDB::Table",
+      "Properties": {
+        "BillingMode": "PAY_PER_REQUEST"
+      }
+    }
+  }
+}`
+
+func hourlyQuantities(resources []*schema.Resource) map[string]map[string]string {
+	quantities := make(map[string]map[string]string)
+	for _, r := range resources {
+		quantities[r.Name] = make(map[string]string)
+		for _, c := range r.CostComponents {
+			if c.HourlyQuantity != nil {
+				quantities[r.Name][c.Name] = c.HourlyQuantity.String()
+			}
+		}
+	}
+
+	return quantities
+}
+
+func TestApplyChangeSet(t *testing.T) {
+	changeSet := `{
+  "ChangeSetName": "my-change-set",
+  "Changes": [
+    {"Type": "Resource", "ResourceChange": {"Action": "Add", "LogicalResourceId": "NewTable", "ResourceType": "AWS::DynamoDB::Table"}},
+    {
+      "Type": "Resource",
+      "ResourceChange": {
+        "Action": "Modify",
+        "LogicalResourceId": "ExistingTable",
+        "ResourceType": "AWS::DynamoDB::Table",
+        "BeforeContext": "{\"Properties\":{\"BillingMode\":\"PROVISIONED\",\"ProvisionedThroughput\":{\"ReadCapacityUnits\":\"10\",\"WriteCapacityUnits\":\"5\"}}}"
+      }
+    },
+    {
+      "Type": "Resource",
+      "ResourceChange": {
+        "Action": "Remove",
+        "LogicalResourceId": "OldTable",
+        "ResourceType": "AWS::DynamoDB::Table",
+        "BeforeContext": "{\"Properties\":{\"BillingMode\":\"PROVISIONED\",\"ProvisionedThroughput\":{\"ReadCapacityUnits\":\"3\",\"WriteCapacityUnits\":\"4\"}}}"
+      }
+    }
+  ]
+}`
+
+	templatePath := writeTestFile(t, "template.json", testTemplate)
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: templatePath})
+
+	_, resources, err := NewParser(ctx).parseTemplate(mustOpenTemplate(t, templatePath), nil)
+	require.NoError(t, err)
+
+	project := &schema.Project{Resources: resources}
+
+	err = ApplyChangeSet(ctx, writeTestFile(t, "change-set.json", changeSet), []*schema.Project{project}, nil)
+	require.NoError(t, err)
+
+	assert.True(t, project.HasDiff)
+	assert.Equal(t, map[string]map[string]string{
+		"ExistingTable":  {"Write capacity unit (WCU)": "5", "Read capacity unit (RCU)": "10"},
+		"OldTable":       {"Write capacity unit (WCU)": "4", "Read capacity unit (RCU)": "3"},
+		"UnchangedTable": {},
+	}, hourlyQuantities(project.PastResources))
+	assert.Equal(t, map[string]map[string]string{
+		"NewTable":       {"Write capacity unit (WCU)": "1", "Read capacity unit (RCU)": "1"},
+		"ExistingTable":  {"Write capacity unit (WCU)": "10", "Read capacity unit (RCU)": "20"},
+		"UnchangedTable": {},
+	}, hourlyQuantities(project.Resources))
+
+	for _, past := range project.PastResources {
+		for _, r := range project.Resources {
+			assert.NotSame(t, r, past, "past resources should be separate from the resources")
+		}
+	}
+}
+
+func TestApplyChangeSetWithoutPropertyValues(t *testing.T) {
+	templatePath := writeTestFile(t, "template.json", testTemplate)
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: templatePath})
+
+	project := &schema.Project{}
+
+	err := ApplyChangeSet(ctx, writeTestFile(t, "change-set.json", testChangeSet), []*schema.Project{project}, nil)
+	require.NoError(t, err)
+
+	// Without the BeforeContext the modified resource is assumed to be
+	// unchanged and the removed resource can't be priced.
+	assert.Equal(t, map[string]map[string]string{
+		"ExistingTable":  {"Write capacity unit (WCU)": "10", "Read capacity unit (RCU)": "20"},
+		"UnchangedTable": {},
+	}, hourlyQuantities(project.PastResources))
+}
+
+func TestApplyChangeSetInvalidFile(t *testing.T) {
+	templatePath := writeTestFile(t, "template.json", testTemplate)
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: templatePath})
+
+	err := ApplyChangeSet(ctx, writeTestFile(t, "change-set.json", "not json"), []*schema.Project{{}}, nil)
+	assert.Error(t, err)
+
+	ctx = config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: filepath.Join(t.TempDir(), "missing.json")})
+
+	err = ApplyChangeSet(ctx, writeTestFile(t, "change-set.json", testChangeSet), []*schema.Project{{}}, nil)
+	assert.Error(t, err)
+}
+
+func TestConvertStrings(t *testing.T) {
+	type nested struct {
+		Count int64 `json:"Count"`
+	}
+
+	type properties struct {
+		Name    string            `json:"Name,omitempty"`
+		Size    float64           `json:"Size"`
+		Enabled bool              `json:"Enabled"`
+		Nested  *nested           `json:"Nested,omitempty"`
+		Items   []nested          `json:"Items,omitempty"`
+		Tags    map[string]string `json:"Tags,omitempty"`
+	}
+
+	actual := convertStrings(map[string]interface{}{
+		"Name":    "10",
+		"Size":    "2.5",
+		"Enabled": "true",
+		"Nested":  map[string]interface{}{"Count": "3"},
+		"Items":   []interface{}{map[string]interface{}{"Count": "4"}},
+		"Tags":    map[string]interface{}{"Team": "1"},
+		"Unknown": "5",
+	}, reflect.TypeOf(&properties{}))
+
+	assert.Equal(t, map[string]interface{}{
+		"Name":    "10",
+		"Size":    2.5,
+		"Enabled": true,
+		"Nested":  map[string]interface{}{"Count": float64(3)},
+		"Items":   []interface{}{map[string]interface{}{"Count": float64(4)}},
+		"Tags":    map[string]interface{}{"Team": "1"},
+		"Unknown": "5",
+	}, actual)
+}
+
+func mustOpenTemplate(t *testing.T, path string) *cloudformation.Template {
+	template, err := goformation.Open(path)
+	require.NoError(t, err)
+	return template
+}
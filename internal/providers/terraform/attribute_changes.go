@@ -0,0 +1,294 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/tidwall/gjson"
+)
+
+// attributeChange is a changed attribute of a resource in the plan, with the
+// path and raw values needed to undo the change in the resource's values.
+type attributeChange struct {
+	*schema.AttributeChange
+	path   []string
+	before gjson.Result
+	after  gjson.Result
+}
+
+// flatAttribute is a scalar value of a resource's attributes and its path.
+type flatAttribute struct {
+	path  []string
+	value gjson.Result
+}
+
+// parseAttributeChanges returns the attributes that changed for each resource
+// that's updated in place or replaced, so the diff can explain why its cost
+// changed. Attributes that are added or removed have a null before or after
+// value. Attributes that aren't known until apply are left out.
+func parseAttributeChanges(resourceChanges gjson.Result) map[string][]*attributeChange {
+	changes := make(map[string][]*attributeChange)
+
+	for _, c := range resourceChanges.Array() {
+		before := c.Get("change.before")
+		after := c.Get("change.after")
+		if !before.IsObject() || !after.IsObject() {
+			continue
+		}
+
+		beforeAttrs := make(map[string]flatAttribute)
+		flattenAttributes(nil, before, beforeAttrs)
+		afterAttrs := make(map[string]flatAttribute)
+		flattenAttributes(nil, after, afterAttrs)
+		unknownAttrs := make(map[string]flatAttribute)
+		flattenAttributes(nil, c.Get("change.after_unknown"), unknownAttrs)
+
+		names := make([]string, 0, len(afterAttrs))
+		for name := range afterAttrs {
+			names = append(names, name)
+		}
+		for name := range beforeAttrs {
+			if _, ok := afterAttrs[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		addr := c.Get("address").String()
+
+		for _, name := range names {
+			b, a := beforeAttrs[name], afterAttrs[name]
+			if attributeString(b.value) == attributeString(a.value) {
+				continue
+			}
+
+			path := a.path
+			if path == nil {
+				path = b.path
+			}
+
+			// Computed attributes aren't in the after values, so they'd
+			// otherwise look like they've been removed
+			if isUnknownAttribute(path, unknownAttrs) {
+				continue
+			}
+
+			changes[addr] = append(changes[addr], &attributeChange{
+				AttributeChange: &schema.AttributeChange{
+					Name:   name,
+					Before: attributeString(b.value),
+					After:  attributeString(a.value),
+				},
+				path:   path,
+				before: b.value,
+				after:  a.value,
+			})
+		}
+	}
+
+	return changes
+}
+
+// isUnknownAttribute returns true if the attribute or the block it's in is
+// marked as known after apply in the after_unknown values of the change.
+func isUnknownAttribute(path []string, unknownAttrs map[string]flatAttribute) bool {
+	for i := 1; i <= len(path); i++ {
+		if u, ok := unknownAttrs[strings.Join(path[:i], ".")]; ok && u.value.Type == gjson.True {
+			return true
+		}
+	}
+
+	return false
+}
+
+// flattenAttributes adds the scalar values of the attributes to attrs, keyed
+// by their path, e.g. root_block_device.0.volume_size.
+func flattenAttributes(path []string, v gjson.Result, attrs map[string]flatAttribute) {
+	if v.IsArray() {
+		for i, e := range v.Array() {
+			flattenAttributes(appendPath(path, strconv.Itoa(i)), e, attrs)
+		}
+		return
+	}
+
+	if v.IsObject() {
+		v.ForEach(func(k, e gjson.Result) bool {
+			flattenAttributes(appendPath(path, k.String()), e, attrs)
+			return true
+		})
+		return
+	}
+
+	attrs[strings.Join(path, ".")] = flatAttribute{path: path, value: v}
+}
+
+func appendPath(path []string, key string) []string {
+	p := make([]string, len(path), len(path)+1)
+	copy(p, path)
+	return append(p, key)
+}
+
+// attributeString returns the value of the attribute as shown in the diff.
+// Attributes that don't exist are shown as null.
+func attributeString(v gjson.Result) string {
+	if !v.Exists() || v.Type == gjson.Null {
+		return "null"
+	}
+
+	return v.String()
+}
+
+// explainChanges sets the changed attributes of the resource's cost
+// components to the attribute changes that are used to build them. Each
+// attribute change is undone in the resource's values and the resource is
+// built again, so a cost component is explained by a change if it's only
+// built, or only has the same product filter, price filter and quantities,
+// when the attribute has its planned value. The past resources are built from
+// the values before the changes, so for those the changes are applied instead.
+func (p *Parser) explainChanges(r *schema.Resource, d *schema.ResourceData, u *schema.UsageData, isPast bool) {
+	changes := p.changes[d.Address]
+	if len(changes) == 0 || r.IsSkipped {
+		return
+	}
+
+	components := costComponentsByKey(r)
+
+	for _, c := range changes {
+		v := c.before
+		if isPast {
+			v = c.after
+		}
+
+		rawValues, ok := setAttribute(d.RawValues, c.path, v)
+		if !ok {
+			continue
+		}
+
+		alt := *d
+		alt.RawValues = rawValues
+
+		altComponents := make(map[string]*schema.CostComponent)
+		if altRes := p.createResource(&alt, u); altRes != nil {
+			altComponents = costComponentsByKey(altRes)
+		}
+
+		for key, cc := range components {
+			if !sameCostComponent(cc, altComponents[key]) {
+				cc.ChangedAttributes = append(cc.ChangedAttributes, c.AttributeChange)
+			}
+		}
+	}
+}
+
+// costComponentsByKey returns the cost components of the resource and its
+// sub-resources keyed by the sub-resource names and the cost component name.
+func costComponentsByKey(r *schema.Resource) map[string]*schema.CostComponent {
+	m := make(map[string]*schema.CostComponent)
+	addCostComponentsByKey(m, "", r)
+	return m
+}
+
+func addCostComponentsByKey(m map[string]*schema.CostComponent, prefix string, r *schema.Resource) {
+	for _, c := range r.CostComponents {
+		m[prefix+c.Name] = c
+	}
+
+	for _, s := range r.SubResources {
+		addCostComponentsByKey(m, fmt.Sprintf("%s%s/", prefix, s.Name), s)
+	}
+}
+
+func sameCostComponent(c *schema.CostComponent, other *schema.CostComponent) bool {
+	if other == nil {
+		return false
+	}
+
+	return sameJSON(c.ProductFilter, other.ProductFilter) &&
+		sameJSON(c.PriceFilter, other.PriceFilter) &&
+		sameJSON(c.HourlyQuantity, other.HourlyQuantity) &&
+		sameJSON(c.MonthlyQuantity, other.MonthlyQuantity)
+}
+
+func sameJSON(a interface{}, b interface{}) bool {
+	aj, aErr := json.Marshal(a)
+	bj, bErr := json.Marshal(b)
+
+	return aErr == nil && bErr == nil && string(aj) == string(bj)
+}
+
+// setAttribute returns the values with the attribute at the path set to v, or
+// removed if v doesn't exist. It returns false if the path can't be set, e.g.
+// because the block containing it doesn't exist.
+func setAttribute(values gjson.Result, path []string, v gjson.Result) (gjson.Result, bool) {
+	var j interface{}
+	if err := json.Unmarshal([]byte(values.Raw), &j); err != nil {
+		return values, false
+	}
+
+	var newVal interface{}
+	if v.Exists() {
+		if err := json.Unmarshal([]byte(v.Raw), &newVal); err != nil {
+			return values, false
+		}
+	}
+
+	j, ok := setPath(j, path, newVal, v.Exists())
+	if !ok {
+		return values, false
+	}
+
+	b, err := json.Marshal(j)
+	if err != nil {
+		return values, false
+	}
+
+	return gjson.ParseBytes(b), true
+}
+
+func setPath(j interface{}, path []string, v interface{}, exists bool) (interface{}, bool) {
+	if len(path) == 0 {
+		return v, true
+	}
+
+	key, rest := path[0], path[1:]
+
+	switch val := j.(type) {
+	case map[string]interface{}:
+		child, ok := val[key]
+		if len(rest) == 0 {
+			if exists {
+				val[key] = v
+			} else {
+				delete(val, key)
+			}
+			return val, true
+		}
+
+		if !ok {
+			return j, false
+		}
+
+		child, ok = setPath(child, rest, v, exists)
+		val[key] = child
+		return val, ok
+	case []interface{}:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(val) {
+			return j, false
+		}
+
+		if len(rest) == 0 && !exists {
+			return append(val[:i], val[i+1:]...), true
+		}
+
+		child, ok := setPath(val[i], rest, v, exists)
+		val[i] = child
+		return val, ok
+	}
+
+	return j, false
+}
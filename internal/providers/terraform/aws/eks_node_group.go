@@ -50,7 +50,8 @@ func NewEKSNodeGroup(d *schema.ResourceData, u *schema.UsageData) *schema.Resour
 		data := launchTemplateRef[0]
 
 		onDemandPercentageAboveBaseCount := int64(100)
-		if strings.ToLower(launchTemplateRef[0].Get("instance_market_options.0.market_type").String()) == "spot" {
+		if strings.ToLower(launchTemplateRef[0].Get("instance_market_options.0.market_type").String()) == "spot" ||
+			strings.ToLower(d.Get("capacity_type").String()) == "spot" {
 			onDemandPercentageAboveBaseCount = int64(0)
 		}
 
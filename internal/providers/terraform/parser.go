@@ -40,10 +40,13 @@ var arnAttributeMap = map[string]string{
 type Parser struct {
 	ctx              *config.ProjectContext
 	terraformVersion string
+	sourceLocations  map[string]*schema.SourceLocation
+	movedFrom        map[string]string
+	changes          map[string][]*attributeChange
 }
 
 func NewParser(ctx *config.ProjectContext) *Parser {
-	return &Parser{ctx, ""}
+	return &Parser{ctx: ctx}
 }
 
 func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
@@ -56,12 +59,14 @@ func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *sc
 	if registryItem, ok := (*registryMap)[d.Type]; ok {
 		if registryItem.NoPrice {
 			return &schema.Resource{
-				Name:         d.Address,
-				ResourceType: d.Type,
-				Tags:         d.Tags,
-				IsSkipped:    true,
-				NoPrice:      true,
-				SkipMessage:  "Free resource.",
+				Name:           d.Address,
+				ResourceType:   d.Type,
+				Tags:           d.Tags,
+				SourceLocation: d.SourceLocation,
+				MovedFrom:      d.MovedFrom,
+				IsSkipped:      true,
+				NoPrice:        true,
+				SkipMessage:    "Free resource.",
 			}
 		}
 
@@ -69,6 +74,11 @@ func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *sc
 		if res != nil {
 			res.ResourceType = d.Type
 			res.Tags = d.Tags
+			res.SourceLocation = d.SourceLocation
+			res.MovedFrom = d.MovedFrom
+			if az := d.Get("availability_zone"); az.Type == gjson.String {
+				res.AvailabilityZone = az.String()
+			}
 			if u != nil {
 				res.EstimationSummary = u.CalcEstimationSummary()
 			}
@@ -77,11 +87,13 @@ func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *sc
 	}
 
 	return &schema.Resource{
-		Name:         d.Address,
-		ResourceType: d.Type,
-		Tags:         d.Tags,
-		IsSkipped:    true,
-		SkipMessage:  "This resource is not currently supported",
+		Name:           d.Address,
+		ResourceType:   d.Type,
+		Tags:           d.Tags,
+		SourceLocation: d.SourceLocation,
+		MovedFrom:      d.MovedFrom,
+		IsSkipped:      true,
+		SkipMessage:    "This resource is not currently supported",
 	}
 }
 
@@ -121,6 +133,7 @@ func (p *Parser) parseJSONResources(parsePrior bool, baseResources []*schema.Res
 			}
 		}
 		if r := p.createResource(d, usageData); r != nil {
+			p.explainChanges(r, d, usageData, parsePrior)
 			resources = append(resources, r)
 		}
 	}
@@ -138,6 +151,9 @@ func (p *Parser) parseJSON(j []byte, usage map[string]*schema.UsageData) ([]*sch
 	parsed := gjson.ParseBytes(j)
 
 	p.terraformVersion = parsed.Get("terraform_version").String()
+	p.sourceLocations = parseSourceLocations(p.sourceDir(), parsed.Get("configuration.root_module"))
+	p.movedFrom = parseMovedFrom(parsed.Get("resource_changes"))
+	p.changes = parseAttributeChanges(parsed.Get("resource_changes"))
 	providerConf := parsed.Get("configuration.provider_config")
 	conf := parsed.Get("configuration.root_module")
 	vars := parsed.Get("variables")
@@ -207,6 +223,10 @@ func (p *Parser) parseResourceData(isState bool, providerConf, planVals gjson.Re
 		tags := parseTags(t, v)
 
 		resources[addr] = schema.NewResourceData(t, provider, addr, tags, v)
+		resources[addr].SourceLocation = p.sourceLocation(addr)
+		if !isState {
+			resources[addr].MovedFrom = p.movedFrom[addr]
+		}
 	}
 
 	// Recursively add any resources for child modules
@@ -219,6 +239,22 @@ func (p *Parser) parseResourceData(isState bool, providerConf, planVals gjson.Re
 	return resources
 }
 
+// parseMovedFrom returns the previous address of the resources that have been
+// moved, e.g. with a moved block. Terraform v1.1 and later add this to the
+// resource changes of the plan.
+func parseMovedFrom(resourceChanges gjson.Result) map[string]string {
+	movedFrom := make(map[string]string)
+
+	for _, c := range resourceChanges.Array() {
+		prev := c.Get("previous_address").String()
+		if prev != "" {
+			movedFrom[c.Get("address").String()] = prev
+		}
+	}
+
+	return movedFrom
+}
+
 func parseTags(resourceType string, v gjson.Result) map[string]string {
 	tags := make(map[string]string)
 
@@ -1,6 +1,8 @@
 package terraform
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/infracost/infracost/internal/config"
@@ -316,6 +318,18 @@ func TestCreateResource(t *testing.T) {
 				NoPrice:      false,
 			},
 		},
+		{
+			data: &schema.ResourceData{
+				Address:   "aws_instance.zonal_resource",
+				Type:      "aws_instance",
+				RawValues: gjson.Parse(`{"availability_zone": "us-east-1a"}`),
+			},
+			expected: &schema.Resource{
+				Name:             "aws_instance.zonal_resource",
+				ResourceType:     "aws_instance",
+				AvailabilityZone: "us-east-1a",
+			},
+		},
 		{
 			data: &schema.ResourceData{
 				Address: "null_resource.free_resource",
@@ -352,6 +366,7 @@ func TestCreateResource(t *testing.T) {
 		assert.Equal(t, test.expected.ResourceType, actual.ResourceType)
 		assert.Equal(t, test.expected.IsSkipped, actual.IsSkipped)
 		assert.Equal(t, test.expected.SkipMessage, actual.SkipMessage)
+		assert.Equal(t, test.expected.AvailabilityZone, actual.AvailabilityZone)
 	}
 }
 
@@ -931,3 +946,296 @@ func TestGetModuleNames(t *testing.T) {
 		assert.Equal(t, test.expected, actual)
 	}
 }
+
+func TestParseSourceLocations(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  instance_type = "m5.large"
+}
+
+data "aws_ami" "ubuntu" {
+  most_recent = true
+}
+
+module "db" {
+  source = "./modules/db"
+}
+
+module "cache" {
+  source = "terraform-aws-modules/elasticache/aws"
+}
+`,
+		"modules/db/main.tf": `variable "name" {}
+
+resource "aws_db_instance" "db" {
+  instance_class = "db.t3.medium"
+}
+`,
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	config := gjson.Parse(`{
+		"module_calls": {
+			"cache": {
+				"source": "terraform-aws-modules/elasticache/aws",
+				"module": {"resources": [{"address": "aws_elasticache_cluster.this"}]}
+			}
+		}
+	}`)
+
+	locations := parseSourceLocations(dir, config)
+
+	mainTf := filepath.Join(dir, "main.tf")
+	assert.Equal(t, map[string]*schema.SourceLocation{
+		"aws_instance.web":                          {Filename: mainTf, Line: 1},
+		"data.aws_ami.ubuntu":                       {Filename: mainTf, Line: 5},
+		"module.db.aws_db_instance.db":              {Filename: filepath.Join(dir, "modules/db/main.tf"), Line: 3, ModuleSource: "./modules/db"},
+		"module.cache.aws_elasticache_cluster.this": {Filename: mainTf, Line: 13, ModuleSource: "terraform-aws-modules/elasticache/aws"},
+	}, locations)
+
+	p := &Parser{sourceLocations: locations}
+	assert.Equal(t, locations["aws_instance.web"], p.sourceLocation("aws_instance.web[0]"))
+	assert.Equal(t, locations["module.db.aws_db_instance.db"], p.sourceLocation(`module.db["a.b"].aws_db_instance.db`))
+	assert.Nil(t, p.sourceLocation("module.other.aws_instance.web"))
+}
+
+func TestParseSourceLocationsWithoutDir(t *testing.T) {
+	config := gjson.Parse(`{
+		"resources": [{"address": "aws_instance.web"}],
+		"module_calls": {
+			"cache": {
+				"source": "terraform-aws-modules/elasticache/aws",
+				"module": {
+					"resources": [{"address": "aws_elasticache_cluster.this"}],
+					"module_calls": {
+						"sg": {
+							"source": "terraform-aws-modules/security-group/aws",
+							"module": {"resources": [{"address": "aws_security_group.this"}]}
+						}
+					}
+				}
+			},
+			"db": {
+				"source": "./modules/db",
+				"module": {"resources": [{"address": "aws_db_instance.db"}]}
+			}
+		}
+	}`)
+
+	assert.Equal(t, map[string]*schema.SourceLocation{
+		"module.cache.aws_elasticache_cluster.this":      {ModuleSource: "terraform-aws-modules/elasticache/aws"},
+		"module.cache.module.sg.aws_security_group.this": {ModuleSource: "terraform-aws-modules/security-group/aws"},
+		"module.db.aws_db_instance.db":                   {ModuleSource: "./modules/db"},
+	}, parseSourceLocations("", config))
+}
+
+func TestSourceDir(t *testing.T) {
+	dir := t.TempDir()
+	planJSON := filepath.Join(dir, "plan.json")
+	assert.NoError(t, os.WriteFile(planJSON, []byte("{}"), 0600))
+
+	tests := []struct {
+		name     string
+		project  *config.Project
+		expected string
+	}{
+		{"directory", &config.Project{Path: dir}, dir},
+		{"plan JSON", &config.Project{Path: planJSON}, ""},
+		{"plan JSON with source dir", &config.Project{Path: planJSON, TerraformSourceDir: "../code"}, "../code"},
+		{"no path", &config.Project{}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := NewParser(config.NewProjectContext(nil, test.project))
+			assert.Equal(t, test.expected, p.sourceDir())
+		})
+	}
+}
+
+func TestParseMovedFrom(t *testing.T) {
+	resourceChanges := gjson.Parse(`[
+		{"address": "module.app.aws_instance.web", "previous_address": "aws_instance.web"},
+		{"address": "aws_instance.db"}
+	]`)
+
+	assert.Equal(t, map[string]string{"module.app.aws_instance.web": "aws_instance.web"}, parseMovedFrom(resourceChanges))
+}
+
+func TestParseAttributeChanges(t *testing.T) {
+	resourceChanges := gjson.Parse(`[
+		{
+			"address": "aws_instance.web",
+			"change": {
+				"before": {"instance_type": "t3.medium", "ami": "ami-1", "root_block_device": [{"volume_size": 8}], "tags": {"team": "web"}, "monitoring": true},
+				"after": {"instance_type": "m5.xlarge", "ami": "ami-1", "root_block_device": [{"volume_size": 20}], "tags": {"team": "web"}, "ebs_optimized": true}
+			}
+		},
+		{
+			"address": "aws_instance.new",
+			"change": {
+				"before": null,
+				"after": {"instance_type": "t3.micro"}
+			}
+		},
+		{
+			"address": "aws_instance.replaced",
+			"change": {
+				"before": {"instance_type": "t3.micro", "root_block_device": [{"volume_size": 50}], "ebs_block_device": [{"iops": 100}], "private_ip": "10.0.0.1"},
+				"after": {"instance_type": "t3.small", "root_block_device": [{}], "ebs_block_device": []},
+				"after_unknown": {"root_block_device": [{"volume_size": true}], "ebs_block_device": true, "private_ip": true}
+			}
+		}
+	]`)
+
+	expected := map[string][]*schema.AttributeChange{
+		"aws_instance.web": {
+			{Name: "ebs_optimized", Before: "null", After: "true"},
+			{Name: "instance_type", Before: "t3.medium", After: "m5.xlarge"},
+			{Name: "monitoring", Before: "true", After: "null"},
+			{Name: "root_block_device.0.volume_size", Before: "8", After: "20"},
+		},
+		"aws_instance.replaced": {
+			{Name: "instance_type", Before: "t3.micro", After: "t3.small"},
+		},
+	}
+
+	actual := make(map[string][]*schema.AttributeChange)
+	for addr, changes := range parseAttributeChanges(resourceChanges) {
+		for _, c := range changes {
+			actual[addr] = append(actual[addr], c.AttributeChange)
+		}
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestSetAttribute(t *testing.T) {
+	values := gjson.Parse(`{"instance_type": "m5.xlarge", "root_block_device": [{"volume_size": 20}], "ebs_optimized": true}`)
+
+	tests := []struct {
+		name     string
+		path     []string
+		value    gjson.Result
+		expected string
+		ok       bool
+	}{
+		{"set", []string{"instance_type"}, gjson.Parse(`"t3.medium"`), `{"ebs_optimized":true,"instance_type":"t3.medium","root_block_device":[{"volume_size":20}]}`, true},
+		{"set nested", []string{"root_block_device", "0", "volume_size"}, gjson.Parse(`8`), `{"ebs_optimized":true,"instance_type":"m5.xlarge","root_block_device":[{"volume_size":8}]}`, true},
+		{"remove", []string{"ebs_optimized"}, gjson.Result{}, `{"instance_type":"m5.xlarge","root_block_device":[{"volume_size":20}]}`, true},
+		{"add", []string{"monitoring"}, gjson.Parse(`true`), `{"ebs_optimized":true,"instance_type":"m5.xlarge","monitoring":true,"root_block_device":[{"volume_size":20}]}`, true},
+		{"missing block", []string{"ebs_block_device", "0", "volume_size"}, gjson.Parse(`8`), "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := setAttribute(values, tt.path, tt.value)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.JSONEq(t, tt.expected, actual.Raw)
+			}
+		})
+	}
+}
+
+func TestParseJSONChangedAttributes(t *testing.T) {
+	plan := []byte(`{
+		"format_version": "0.1",
+		"terraform_version": "1.1.0",
+		"planned_values": {
+			"root_module": {
+				"resources": [
+					{
+						"address": "aws_instance.web",
+						"type": "aws_instance",
+						"name": "web",
+						"provider_name": "registry.terraform.io/hashicorp/aws",
+						"values": {"instance_type": "m5.xlarge", "root_block_device": [{"volume_size": 20}], "tags": {"team": "api"}}
+					}
+				]
+			}
+		},
+		"prior_state": {
+			"values": {
+				"root_module": {
+					"resources": [
+						{
+							"address": "aws_instance.web",
+							"type": "aws_instance",
+							"name": "web",
+							"provider_name": "registry.terraform.io/hashicorp/aws",
+							"values": {"instance_type": "t3.medium", "root_block_device": [{"volume_size": 20}], "tags": {"team": "web"}}
+						}
+					]
+				}
+			}
+		},
+		"resource_changes": [
+			{
+				"address": "aws_instance.web",
+				"type": "aws_instance",
+				"change": {
+					"actions": ["update"],
+					"before": {"instance_type": "t3.medium", "root_block_device": [{"volume_size": 20}], "tags": {"team": "web"}},
+					"after": {"instance_type": "m5.xlarge", "root_block_device": [{"volume_size": 20}], "tags": {"team": "api"}}
+				}
+			}
+		],
+		"configuration": {
+			"provider_config": {
+				"aws": {"name": "aws", "expressions": {"region": {"constant_value": "us-east-1"}}}
+			},
+			"root_module": {}
+		}
+	}`)
+
+	instanceType := &schema.AttributeChange{Name: "instance_type", Before: "t3.medium", After: "m5.xlarge"}
+
+	p := NewParser(config.EmptyProjectContext())
+	pastResources, resources, err := p.parseJSON(plan, map[string]*schema.UsageData{})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		resources []*schema.Resource
+		expected  map[string][]*schema.AttributeChange
+	}{
+		{
+			name:      "past",
+			resources: pastResources,
+			expected: map[string][]*schema.AttributeChange{
+				"Instance usage (Linux/UNIX, on-demand, t3.medium)": {instanceType},
+				"CPU credits": {instanceType},
+				"root_block_device/Storage (general purpose SSD, gp2)": nil,
+			},
+		},
+		{
+			name:      "current",
+			resources: resources,
+			expected: map[string][]*schema.AttributeChange{
+				"Instance usage (Linux/UNIX, on-demand, m5.xlarge)":    {instanceType},
+				"root_block_device/Storage (general purpose SSD, gp2)": nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, tt.resources, 1)
+
+			actual := make(map[string][]*schema.AttributeChange)
+			for key, c := range costComponentsByKey(tt.resources[0]) {
+				actual[key] = c.ChangedAttributes
+			}
+
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package terraform
 
 import (
+	"fmt"
 	"strings"
 	"sync"
 
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
 	"github.com/infracost/infracost/internal/schema"
 
 	"github.com/infracost/infracost/internal/providers/terraform/aws"
@@ -71,3 +75,36 @@ func createFreeResources(l []string) []*schema.RegistryItem {
 	}
 	return freeResources
 }
+
+// NewResourceTypeResources returns a resource for each of the resource types
+// built from the region alone, so the products they use can be looked up
+// without a Terraform project. It returns an error if a resource type isn't
+// supported or is free.
+func NewResourceTypeResources(resourceTypes []string, region string) ([]*schema.Resource, error) {
+	registryMap := GetResourceRegistryMap()
+	resources := make([]*schema.Resource, 0, len(resourceTypes))
+
+	for _, t := range resourceTypes {
+		registryItem, ok := (*registryMap)[t]
+		if !ok {
+			return resources, errors.Errorf("Resource type %s is not supported", t)
+		}
+		if registryItem.NoPrice {
+			return resources, errors.Errorf("Resource type %s is free", t)
+		}
+
+		// Azure resources get their region from the location
+		rawValues := gjson.Parse(fmt.Sprintf(`{"region": %q, "location": %q}`, region, region))
+		d := schema.NewResourceData(t, strings.SplitN(t, "_", 2)[0], fmt.Sprintf("%s.this", t), map[string]string{}, rawValues)
+
+		r := registryItem.RFunc(d, nil)
+		if r == nil {
+			return resources, errors.Errorf("Resource type %s has no prices without its attributes", t)
+		}
+		r.ResourceType = t
+
+		resources = append(resources, r)
+	}
+
+	return resources, nil
+}
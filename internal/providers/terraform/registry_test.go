@@ -0,0 +1,38 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResourceTypeResources(t *testing.T) {
+	resources, err := NewResourceTypeResources([]string{"aws_instance", "aws_db_instance"}, "eu-west-1")
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	assert.Equal(t, "aws_instance", resources[0].ResourceType)
+	assert.Equal(t, "AmazonEC2", *resources[0].CostComponents[0].ProductFilter.Service)
+	assert.Equal(t, "eu-west-1", *resources[0].CostComponents[0].ProductFilter.Region)
+
+	assert.Equal(t, "aws_db_instance", resources[1].ResourceType)
+	assert.Equal(t, "AmazonRDS", *resources[1].CostComponents[0].ProductFilter.Service)
+}
+
+func TestNewResourceTypeResourcesErrors(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		err          string
+	}{
+		{"aws_not_a_resource", "Resource type aws_not_a_resource is not supported"},
+		{"aws_vpc", "Resource type aws_vpc is free"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.resourceType, func(t *testing.T) {
+			_, err := NewResourceTypeResources([]string{tt.resourceType}, "us-east-1")
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
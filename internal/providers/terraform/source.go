@@ -0,0 +1,232 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/hashicorp/hcl2/hclparse"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var addressPartIndexRegex = regexp.MustCompile(`\[.*\]$`)
+
+// sourceDir returns the directory with the Terraform files of the project.
+// Plan and state JSON files are often generated in a different directory, e.g.
+// in a CI workspace, so any .tf files next to them aren't used unless the
+// directory they were generated from is given with terraform_source_dir.
+func (p *Parser) sourceDir() string {
+	if p.ctx == nil || p.ctx.ProjectConfig == nil {
+		return ""
+	}
+
+	if p.ctx.ProjectConfig.TerraformSourceDir != "" {
+		return p.ctx.ProjectConfig.TerraformSourceDir
+	}
+
+	path := p.ctx.ProjectConfig.Path
+	if path == "" {
+		return ""
+	}
+
+	info, err := os.Stat(path)
+	if err == nil && info.IsDir() {
+		return path
+	}
+
+	return ""
+}
+
+// sourceLocation returns the location of the block that defines the resource,
+// or nil if it's not known.
+func (p *Parser) sourceLocation(addr string) *schema.SourceLocation {
+	return p.sourceLocations[sourceLocationKey(addr)]
+}
+
+// sourceLocationKey returns the address without any indexes, e.g.
+// module.app.aws_instance.web for module.app["a"].aws_instance.web[0].
+func sourceLocationKey(addr string) string {
+	parts := splitAddress(addr)
+	for i, part := range parts {
+		parts[i] = addressPartIndexRegex.ReplaceAllString(part, "")
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// sourceBlocks are the resource, data and module blocks in the .tf files of
+// a directory.
+type sourceBlocks struct {
+	resources map[string]*schema.SourceLocation
+	modules   map[string]sourceModuleBlock
+}
+
+type sourceModuleBlock struct {
+	source   string
+	location *schema.SourceLocation
+}
+
+// sourceLocationParser finds the source locations of the resources in a
+// Terraform project and the modules it calls.
+type sourceLocationParser struct {
+	locations map[string]*schema.SourceLocation
+	parsed    map[string]sourceBlocks
+	hcl       *hclparse.Parser
+}
+
+// parseSourceLocations returns the location of each resource and data block
+// keyed by the address without any indexes. The module sources come from the
+// configuration in the plan JSON when it's there, otherwise from the module
+// blocks in the .tf files. Local modules are parsed from their directories.
+// Without a directory only the module sources of the resources in modules are
+// known, so their locations have no filename or line.
+func parseSourceLocations(dir string, config gjson.Result) map[string]*schema.SourceLocation {
+	p := &sourceLocationParser{
+		locations: make(map[string]*schema.SourceLocation),
+		parsed:    make(map[string]sourceBlocks),
+		hcl:       hclparse.NewParser(),
+	}
+
+	p.addModule("", dir, "", nil, config)
+
+	return p.locations
+}
+
+// addModule adds the locations of the resources in the module at dir. For
+// remote modules dir is empty and the resources from the configuration are
+// located at the module block that calls them, if it's known.
+func (p *sourceLocationParser) addModule(prefix string, dir string, moduleSource string, call *schema.SourceLocation, config gjson.Result) {
+	var blocks sourceBlocks
+	if dir != "" {
+		blocks = p.parseDir(dir)
+	}
+
+	for addr, loc := range blocks.resources {
+		p.locations[prefix+addr] = &schema.SourceLocation{
+			Filename:     loc.Filename,
+			Line:         loc.Line,
+			ModuleSource: moduleSource,
+		}
+	}
+
+	if dir == "" && (call != nil || moduleSource != "") {
+		for _, r := range config.Get("resources").Array() {
+			loc := &schema.SourceLocation{ModuleSource: moduleSource}
+			if call != nil {
+				loc.Filename = call.Filename
+				loc.Line = call.Line
+			}
+
+			p.locations[prefix+r.Get("address").String()] = loc
+		}
+	}
+
+	moduleCalls := config.Get("module_calls").Map()
+
+	names := make(map[string]bool)
+	for name := range blocks.modules {
+		names[name] = true
+	}
+	for name := range moduleCalls {
+		names[name] = true
+	}
+
+	for name := range names {
+		block := blocks.modules[name]
+		moduleCall := moduleCalls[name]
+
+		source := block.source
+		if moduleCall.Get("source").Exists() {
+			source = moduleCall.Get("source").String()
+		}
+
+		// Resources in remote modules, or modules nested in them, are
+		// located at the outermost module block in the project
+		childCall := call
+		if block.location != nil {
+			childCall = block.location
+		}
+
+		childDir := ""
+		if dir != "" && isLocalModuleSource(source) {
+			childDir = filepath.Join(dir, source)
+		}
+
+		p.addModule(prefix+"module."+name+".", childDir, source, childCall, moduleCall.Get("module"))
+	}
+}
+
+func (p *sourceLocationParser) parseDir(dir string) sourceBlocks {
+	if blocks, ok := p.parsed[dir]; ok {
+		return blocks
+	}
+
+	blocks := sourceBlocks{
+		resources: make(map[string]*schema.SourceLocation),
+		modules:   make(map[string]sourceModuleBlock),
+	}
+	p.parsed[dir] = blocks
+
+	filenames, _ := filepath.Glob(filepath.Join(dir, "*.tf"))
+
+	for _, filename := range filenames {
+		f, diags := p.hcl.ParseHCLFile(filename)
+		if diags.HasErrors() {
+			log.Debugf("Error parsing %s for source locations: %s", filename, diags.Error())
+			continue
+		}
+
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+
+		for _, block := range body.Blocks {
+			loc := &schema.SourceLocation{
+				Filename: filename,
+				Line:     block.DefRange().Start.Line,
+			}
+
+			switch {
+			case block.Type == "resource" && len(block.Labels) == 2:
+				blocks.resources[block.Labels[0]+"."+block.Labels[1]] = loc
+			case block.Type == "data" && len(block.Labels) == 2:
+				blocks.resources["data."+block.Labels[0]+"."+block.Labels[1]] = loc
+			case block.Type == "module" && len(block.Labels) == 1:
+				blocks.modules[block.Labels[0]] = sourceModuleBlock{
+					source:   moduleBlockSource(block),
+					location: loc,
+				}
+			}
+		}
+	}
+
+	return blocks
+}
+
+// moduleBlockSource returns the source of a module block if it's a literal
+// string.
+func moduleBlockSource(block *hclsyntax.Block) string {
+	attr, ok := block.Body.Attributes["source"]
+	if !ok {
+		return ""
+	}
+
+	v, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !v.Type().Equals(cty.String) || v.IsNull() {
+		return ""
+	}
+
+	return v.AsString()
+}
+
+// isLocalModuleSource returns true if the module source is a local path,
+// which Terraform requires to start with ./ or ../.
+func isLocalModuleSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
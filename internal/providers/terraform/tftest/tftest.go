@@ -261,11 +261,12 @@ func RunCostCalculations(t *testing.T, runCtx *config.RunContext, tfProject Terr
 		return projects, err
 	}
 
+	err = prices.PopulatePrices(runCtx.Config, projects)
+	if err != nil {
+		return projects, err
+	}
+
 	for _, project := range projects {
-		err = prices.PopulatePrices(runCtx.Config, project)
-		if err != nil {
-			return projects, err
-		}
 		schema.CalculateCosts(project)
 	}
 	return projects, nil
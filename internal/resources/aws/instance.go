@@ -33,12 +33,13 @@ type Instance struct {
 	EBSBlockDevices                 []*EBSVolume
 
 	// "usage" args
-	OperatingSystem               *string `infracost_usage:"operating_system"`
-	ReservedInstanceType          *string `infracost_usage:"reserved_instance_type"`
-	ReservedInstanceTerm          *string `infracost_usage:"reserved_instance_term"`
-	ReservedInstancePaymentOption *string `infracost_usage:"reserved_instance_payment_option"`
-	MonthlyCPUCreditHours         *int64  `infracost_usage:"monthly_cpu_credit_hrs"`
-	VCPUCount                     *int64  `infracost_usage:"vcpu_count"`
+	OperatingSystem               *string  `infracost_usage:"operating_system"`
+	ReservedInstanceType          *string  `infracost_usage:"reserved_instance_type"`
+	ReservedInstanceTerm          *string  `infracost_usage:"reserved_instance_term"`
+	ReservedInstancePaymentOption *string  `infracost_usage:"reserved_instance_payment_option"`
+	MonthlyCPUCreditHours         *int64   `infracost_usage:"monthly_cpu_credit_hrs"`
+	VCPUCount                     *int64   `infracost_usage:"vcpu_count"`
+	SpotFallbackRatio             *float64 `infracost_usage:"spot_fallback_ratio"`
 }
 
 var InstanceUsageSchema = []*schema.UsageSchemaItem{
@@ -48,6 +49,7 @@ var InstanceUsageSchema = []*schema.UsageSchemaItem{
 	{Key: "reserved_instance_payment_option", DefaultValue: "", ValueType: schema.String},
 	{Key: "monthly_cpu_credit_hrs", DefaultValue: 0, ValueType: schema.Int64},
 	{Key: "vcpu_count", DefaultValue: 0, ValueType: schema.Int64},
+	{Key: "spot_fallback_ratio", DefaultValue: 0, ValueType: schema.Float64},
 }
 
 func (a *Instance) PopulateUsage(u *schema.UsageData) {
@@ -87,7 +89,7 @@ func (a *Instance) BuildResource() *schema.Resource {
 		subResources = append(subResources, ebs.BuildResource())
 	}
 
-	costComponents = append(costComponents, a.computeCostComponent())
+	costComponents = append(costComponents, a.purchaseOptionCostComponents(decimal.NewFromInt(1))...)
 
 	if a.EBSOptimized {
 		costComponents = append(costComponents, a.ebsOptimizedCostComponent())
@@ -188,6 +190,52 @@ func (a *Instance) computeCostComponent() *schema.CostComponent {
 	}
 }
 
+// purchaseOptionCostComponents returns the instance usage cost components for
+// the given number of instances. Spot instances are split into spot and
+// on-demand usage using the spot fallback ratio, which models the share of the
+// time spot capacity is interrupted or unavailable and on-demand capacity is
+// used instead.
+func (a *Instance) purchaseOptionCostComponents(count decimal.Decimal) []*schema.CostComponent {
+	purchaseOption := a.PurchaseOption
+
+	fallback := decimal.Zero
+	if purchaseOption == "spot" {
+		fallback = a.spotFallbackRatio()
+	}
+
+	costComponents := make([]*schema.CostComponent, 0, 2)
+
+	if fallback.IsPositive() {
+		a.PurchaseOption = "on_demand"
+		c := a.computeCostComponent()
+		c.HourlyQuantity = decimalPtr(c.HourlyQuantity.Mul(count).Mul(fallback))
+		costComponents = append(costComponents, c)
+		a.PurchaseOption = purchaseOption
+	}
+
+	if fallback.LessThan(decimal.NewFromInt(1)) {
+		c := a.computeCostComponent()
+		c.HourlyQuantity = decimalPtr(c.HourlyQuantity.Mul(count).Mul(decimal.NewFromInt(1).Sub(fallback)))
+		costComponents = append(costComponents, c)
+	}
+
+	return costComponents
+}
+
+func (a *Instance) spotFallbackRatio() decimal.Decimal {
+	if a.SpotFallbackRatio == nil {
+		return decimal.Zero
+	}
+
+	r := *a.SpotFallbackRatio
+	if r < 0 || r > 1 {
+		log.Warnf("Invalid spot_fallback_ratio for %s, ignoring it. Expected a value between 0 and 1. Got: %v", a.Address, r)
+		return decimal.Zero
+	}
+
+	return decimal.NewFromFloat(r)
+}
+
 func (a *Instance) validateReserveInstanceParams() (bool, string) {
 	validTypes := []string{"convertible", "standard"}
 	if !stringInSlice(validTypes, strVal(a.ReservedInstanceType)) {
@@ -29,13 +29,14 @@ type LaunchTemplate struct {
 
 	// "usage" args
 	// These are populated from the Autoscaling Group/EKS Node Group resource
-	InstanceCount                 *int64  `infracost_usage:"instances"`
-	OperatingSystem               *string `infracost_usage:"operating_system"`
-	ReservedInstanceType          *string `infracost_usage:"reserved_instance_type"`
-	ReservedInstanceTerm          *string `infracost_usage:"reserved_instance_term"`
-	ReservedInstancePaymentOption *string `infracost_usage:"reserved_instance_payment_option"`
-	MonthlyCPUCreditHours         *int64  `infracost_usage:"monthly_cpu_credit_hrs"`
-	VCPUCount                     *int64  `infracost_usage:"vcpu_count"`
+	InstanceCount                 *int64   `infracost_usage:"instances"`
+	OperatingSystem               *string  `infracost_usage:"operating_system"`
+	ReservedInstanceType          *string  `infracost_usage:"reserved_instance_type"`
+	ReservedInstanceTerm          *string  `infracost_usage:"reserved_instance_term"`
+	ReservedInstancePaymentOption *string  `infracost_usage:"reserved_instance_payment_option"`
+	MonthlyCPUCreditHours         *int64   `infracost_usage:"monthly_cpu_credit_hrs"`
+	VCPUCount                     *int64   `infracost_usage:"vcpu_count"`
+	SpotFallbackRatio             *float64 `infracost_usage:"spot_fallback_ratio"`
 }
 
 var LaunchTemplateUsageSchema = InstanceUsageSchema
@@ -73,6 +74,7 @@ func (a *LaunchTemplate) BuildResource() *schema.Resource {
 		ReservedInstancePaymentOption:   a.ReservedInstancePaymentOption,
 		MonthlyCPUCreditHours:           a.MonthlyCPUCreditHours,
 		VCPUCount:                       a.VCPUCount,
+		SpotFallbackRatio:               a.SpotFallbackRatio,
 	}
 	instanceResource := instance.BuildResource()
 
@@ -100,17 +102,27 @@ func (a *LaunchTemplate) BuildResource() *schema.Resource {
 
 	onDemandCount, spotCount := a.calculateOnDemandAndSpotInstanceCounts()
 
+	// Spot instances that fall back to on-demand are merged into the
+	// on-demand cost component so there is only one of each.
+	onDemandQuantity := decimal.NewFromInt(onDemandCount)
+	spotQuantity := decimal.NewFromInt(spotCount)
 	if spotCount > 0 {
+		fallback := instance.spotFallbackRatio()
+		onDemandQuantity = onDemandQuantity.Add(spotQuantity.Mul(fallback))
+		spotQuantity = spotQuantity.Sub(spotQuantity.Mul(fallback))
+	}
+
+	if spotQuantity.IsPositive() {
 		instance.PurchaseOption = "spot"
 		c := instance.computeCostComponent()
-		c.HourlyQuantity = decimalPtr(c.HourlyQuantity.Mul(decimal.NewFromInt(spotCount)))
+		c.HourlyQuantity = decimalPtr(c.HourlyQuantity.Mul(spotQuantity))
 		r.CostComponents = append([]*schema.CostComponent{c}, r.CostComponents...)
 	}
 
-	if onDemandCount > 0 {
+	if onDemandQuantity.IsPositive() {
 		instance.PurchaseOption = "on_demand"
 		c := instance.computeCostComponent()
-		c.HourlyQuantity = decimalPtr(c.HourlyQuantity.Mul(decimal.NewFromInt(onDemandCount)))
+		c.HourlyQuantity = decimalPtr(c.HourlyQuantity.Mul(onDemandQuantity))
 		r.CostComponents = append([]*schema.CostComponent{c}, r.CostComponents...)
 	}
 
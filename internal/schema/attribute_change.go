@@ -0,0 +1,35 @@
+package schema
+
+// AttributeChange is an input attribute of a resource that changed in the
+// plan. Nested attributes are named by their path, e.g.
+// root_block_device.0.volume_size.
+type AttributeChange struct {
+	Name   string `json:"name"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// costComponentChanges returns the attribute changes that explain the change
+// of the cost component. The providers set the changed attributes of the past
+// and current cost components when they find which attributes are used to
+// build them, so these are combined.
+func costComponentChanges(past *CostComponent, current *CostComponent) []*AttributeChange {
+	var changes []*AttributeChange
+
+	seen := make(map[string]bool)
+
+	for _, c := range []*CostComponent{past, current} {
+		if c == nil {
+			continue
+		}
+
+		for _, a := range c.ChangedAttributes {
+			if !seen[a.Name] {
+				seen[a.Name] = true
+				changes = append(changes, a)
+			}
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,87 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCostComponentChanges(t *testing.T) {
+	instanceType := &AttributeChange{Name: "instance_type", Before: "t3.medium", After: "m5.xlarge"}
+	storage := &AttributeChange{Name: "allocated_storage", Before: "20", After: "50"}
+
+	tests := []struct {
+		name     string
+		past     *CostComponent
+		current  *CostComponent
+		expected []*AttributeChange
+	}{
+		{
+			name:     "past and current",
+			past:     &CostComponent{Name: "Storage", ChangedAttributes: []*AttributeChange{instanceType}},
+			current:  &CostComponent{Name: "Storage", ChangedAttributes: []*AttributeChange{instanceType, storage}},
+			expected: []*AttributeChange{instanceType, storage},
+		},
+		{
+			name:     "added",
+			past:     nil,
+			current:  &CostComponent{Name: "Instance usage (Linux/UNIX, on-demand, m5.xlarge)", ChangedAttributes: []*AttributeChange{instanceType}},
+			expected: []*AttributeChange{instanceType},
+		},
+		{
+			name:     "removed",
+			past:     &CostComponent{Name: "CPU credits", ChangedAttributes: []*AttributeChange{instanceType}},
+			current:  nil,
+			expected: []*AttributeChange{instanceType},
+		},
+		{
+			name:     "unrelated",
+			past:     &CostComponent{Name: "Data transfer"},
+			current:  &CostComponent{Name: "Data transfer"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, costComponentChanges(tt.past, tt.current))
+		})
+	}
+}
+
+func TestCalculateDiffChangedAttributes(t *testing.T) {
+	volumeSize := &AttributeChange{Name: "root_block_device.0.volume_size", Before: "8", After: "20"}
+
+	past := []*Resource{
+		{
+			Name: "aws_instance.web",
+			SubResources: []*Resource{
+				{
+					Name: "root_block_device",
+					CostComponents: []*CostComponent{
+						{Name: "Storage", MonthlyQuantity: decimalPtr(decimal.NewFromInt(8)), price: decimal.NewFromInt(1), MonthlyCost: decimalPtr(decimal.NewFromInt(8))},
+					},
+				},
+			},
+		},
+	}
+	current := []*Resource{
+		{
+			Name: "aws_instance.web",
+			SubResources: []*Resource{
+				{
+					Name: "root_block_device",
+					CostComponents: []*CostComponent{
+						{Name: "Storage", MonthlyQuantity: decimalPtr(decimal.NewFromInt(20)), price: decimal.NewFromInt(1), MonthlyCost: decimalPtr(decimal.NewFromInt(20)), ChangedAttributes: []*AttributeChange{volumeSize}},
+					},
+				},
+			},
+		},
+	}
+
+	diff := CalculateDiff(past, current)
+
+	assert.Len(t, diff, 1)
+	assert.Equal(t, []*AttributeChange{volumeSize}, diff[0].SubResources[0].CostComponents[0].ChangedAttributes)
+}
@@ -0,0 +1,47 @@
+package schema
+
+import "github.com/shopspring/decimal"
+
+// CommitmentType is the type of an AWS pricing commitment.
+type CommitmentType string
+
+const (
+	CommitmentReservedInstance       CommitmentType = "reserved_instance"
+	CommitmentEC2InstanceSavingsPlan CommitmentType = "ec2_instance_savings_plan"
+	CommitmentComputeSavingsPlan     CommitmentType = "compute_savings_plan"
+)
+
+// CommitmentCoverage is the part of a cost component's usage that is covered
+// by a Reserved Instance or Savings Plan. The covered usage is charged at the
+// commitment rate instead of the on-demand rate.
+type CommitmentCoverage struct {
+	Commitment         string
+	Type               CommitmentType
+	HourlyOnDemandCost decimal.Decimal
+	HourlyCost         decimal.Decimal
+}
+
+// HourlySavings is the difference between the on-demand cost and the cost at
+// the commitment rate.
+func (c *CommitmentCoverage) HourlySavings() decimal.Decimal {
+	return c.HourlyOnDemandCost.Sub(c.HourlyCost)
+}
+
+// Commitment is a Reserved Instance or Savings Plan and how much of it was
+// used by the resources it was allocated to.
+type Commitment struct {
+	Name             string
+	Type             CommitmentType
+	HourlyCommitment decimal.Decimal
+	HourlyUsed       decimal.Decimal
+}
+
+// CommitmentReport summarises how a portfolio of commitments was allocated.
+// The on-demand cost is the spend that could have been covered by the
+// commitments but wasn't.
+type CommitmentReport struct {
+	Commitments               []*Commitment
+	HourlyOnDemandCost        decimal.Decimal
+	HourlyCoveredOnDemandCost decimal.Decimal
+	HourlyCoveredCost         decimal.Decimal
+}
@@ -16,8 +16,13 @@ type CostComponent struct {
 	MonthlyDiscountPerc  float64
 	price                decimal.Decimal
 	priceHash            string
+	listPrice            *decimal.Decimal
+	monthlyCredit        *decimal.Decimal
 	HourlyCost           *decimal.Decimal
 	MonthlyCost          *decimal.Decimal
+	PricingIssues        []*PricingIssue
+	CommitmentCoverage   []*CommitmentCoverage
+	ChangedAttributes    []*AttributeChange
 }
 
 func (c *CostComponent) CalculateCosts() {
@@ -29,6 +34,22 @@ func (c *CostComponent) CalculateCosts() {
 		discountMul := decimal.NewFromFloat(1.0 - c.MonthlyDiscountPerc)
 		c.MonthlyCost = decimalPtr(c.price.Mul(*c.MonthlyQuantity).Mul(discountMul))
 	}
+	for _, cov := range c.CommitmentCoverage {
+		if c.HourlyCost != nil {
+			c.HourlyCost = decimalPtr(c.HourlyCost.Sub(cov.HourlySavings()))
+		}
+		if c.MonthlyCost != nil {
+			c.MonthlyCost = decimalPtr(c.MonthlyCost.Sub(cov.HourlySavings().Mul(HourToMonthUnitMultiplier)))
+		}
+	}
+	if c.monthlyCredit != nil {
+		if c.MonthlyCost != nil {
+			c.MonthlyCost = decimalPtr(c.MonthlyCost.Sub(*c.monthlyCredit))
+		}
+		if c.HourlyCost != nil {
+			c.HourlyCost = decimalPtr(c.HourlyCost.Sub(c.monthlyCredit.Div(HourToMonthUnitMultiplier)))
+		}
+	}
 }
 
 func (c *CostComponent) fillQuantities() {
@@ -55,10 +76,55 @@ func (c *CostComponent) PriceHash() string {
 	return c.priceHash
 }
 
+// OverridePrice replaces the price of the cost component, keeping the original
+// list price so both can be shown.
+func (c *CostComponent) OverridePrice(price decimal.Decimal) {
+	if c.listPrice == nil {
+		c.listPrice = decimalPtr(c.price)
+	}
+	c.price = price
+}
+
+// ListPrice returns the original price if the price has been overridden.
+func (c *CostComponent) ListPrice() *decimal.Decimal {
+	return c.listPrice
+}
+
+// AddMonthlyCredit adds a credit that is taken off the monthly cost.
+func (c *CostComponent) AddMonthlyCredit(amount decimal.Decimal) {
+	c.monthlyCredit = decimalPtr(c.MonthlyCreditAmount().Add(amount))
+}
+
+func (c *CostComponent) MonthlyCreditAmount() decimal.Decimal {
+	if c.monthlyCredit == nil {
+		return decimal.Zero
+	}
+	return *c.monthlyCredit
+}
+
+func (c *CostComponent) MonthlyCredit() *decimal.Decimal {
+	return c.monthlyCredit
+}
+
+func (c *CostComponent) AddPricingIssue(t PricingIssueType, matches int) {
+	c.PricingIssues = append(c.PricingIssues, &PricingIssue{Type: t, Matches: matches})
+}
+
+func (c *CostComponent) AddCommitmentCoverage(coverage *CommitmentCoverage) {
+	c.CommitmentCoverage = append(c.CommitmentCoverage, coverage)
+}
+
 func (c *CostComponent) UnitMultiplierPrice() decimal.Decimal {
 	return c.Price().Mul(c.UnitMultiplier)
 }
 
+func (c *CostComponent) UnitMultiplierListPrice() *decimal.Decimal {
+	if c.listPrice == nil {
+		return nil
+	}
+	return decimalPtr(c.listPrice.Mul(c.UnitMultiplier))
+}
+
 func (c *CostComponent) UnitMultiplierHourlyQuantity() *decimal.Decimal {
 	if c.HourlyQuantity == nil {
 		return nil
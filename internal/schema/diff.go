@@ -2,13 +2,17 @@ package schema
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
 )
 
+var addressIndexRegex = regexp.MustCompile(`\[[^\]]*\]`)
+
 // CalculateDiff calculates the diff of past and current resources
-func calculateDiff(past []*Resource, current []*Resource) []*Resource {
+func CalculateDiff(past []*Resource, current []*Resource) []*Resource {
 	// There are many ways to calculate a diff between two sets of
 	// nested objects. The method used here is to create a nested
 	// hashmap of each set of states for fast lookup so the structure
@@ -24,8 +28,13 @@ func calculateDiff(past []*Resource, current []*Resource) []*Resource {
 	// calculate the diff for them. This way a complete diff for
 	// all resources is calculated.
 
+	// Resources that have been moved are keyed by their current name so they're
+	// compared with each other instead of being removed and added.
+	movedTo := detectMoves(past, current)
+
 	pastRMap := make(map[string]*Resource)
 	fillResourcesMap(pastRMap, "", past)
+	renameResourcesMap(pastRMap, movedTo)
 	currentRMap := make(map[string]*Resource)
 	fillResourcesMap(currentRMap, "", current)
 
@@ -33,8 +42,17 @@ func calculateDiff(past []*Resource, current []*Resource) []*Resource {
 
 	for _, resource := range past {
 		resourceKey := resource.Name
+		newName, moved := movedTo[resource.Name]
+		if moved {
+			resourceKey = newName
+		}
+
 		changed, resources := diffResourcesByKey(resourceKey, pastRMap, currentRMap)
-		if changed {
+		if moved {
+			// Moves are always shown, even if the cost hasn't changed
+			resources.MovedFrom = resource.Name
+			diff = append(diff, resources)
+		} else if changed {
 			diff = append(diff, resources)
 		}
 	}
@@ -53,6 +71,161 @@ func calculateDiff(past []*Resource, current []*Resource) []*Resource {
 	return diff
 }
 
+// detectMoves returns the new name of the past resources that have been moved.
+// A resource has been moved if Terraform reports its previous address, or if
+// a resource is removed and one of the same type with identical cost
+// components is added.
+func detectMoves(past []*Resource, current []*Resource) map[string]string {
+	movedTo := make(map[string]string)
+
+	pastResources := make(map[string]*Resource, len(past))
+	for _, r := range past {
+		pastResources[r.Name] = r
+	}
+
+	currentResources := make(map[string]*Resource, len(current))
+	for _, r := range current {
+		currentResources[r.Name] = r
+	}
+
+	// The resources that have been added and removed, in order
+	removed := make([]*Resource, 0)
+	for _, r := range past {
+		if _, ok := currentResources[r.Name]; !ok {
+			removed = append(removed, r)
+		}
+	}
+
+	added := make([]*Resource, 0)
+	for _, r := range current {
+		if _, ok := pastResources[r.Name]; !ok {
+			added = append(added, r)
+		}
+	}
+
+	movedFrom := make(map[string]bool)
+
+	for _, r := range added {
+		if r.MovedFrom == "" {
+			continue
+		}
+
+		if _, ok := currentResources[r.MovedFrom]; ok {
+			continue
+		}
+
+		if _, ok := pastResources[r.MovedFrom]; ok && !movedFrom[r.MovedFrom] {
+			movedTo[r.MovedFrom] = r.Name
+			movedFrom[r.MovedFrom] = true
+		}
+	}
+
+	for _, r := range added {
+		if (r.MovedFrom != "" && movedFrom[r.MovedFrom]) || resourceType(r) == "" {
+			continue
+		}
+
+		for _, p := range removed {
+			if movedFrom[p.Name] || resourceType(p) != resourceType(r) || !hasCostComponents(p) || !sameCostComponents(p, r) {
+				continue
+			}
+
+			movedTo[p.Name] = r.Name
+			movedFrom[p.Name] = true
+
+			break
+		}
+	}
+
+	return movedTo
+}
+
+// resourceType returns the type of the resource, falling back to parsing it
+// from the address.
+func resourceType(r *Resource) string {
+	if r.ResourceType != "" {
+		return r.ResourceType
+	}
+
+	parts := strings.Split(addressIndexRegex.ReplaceAllString(r.Name, ""), ".")
+	if len(parts) < 2 {
+		return ""
+	}
+
+	return parts[len(parts)-2]
+}
+
+func hasCostComponents(r *Resource) bool {
+	if len(r.CostComponents) > 0 {
+		return true
+	}
+
+	for _, s := range r.SubResources {
+		if hasCostComponents(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sameCostComponents returns true if the resources and their sub-resources
+// have the same cost components with the same prices and quantities.
+func sameCostComponents(a *Resource, b *Resource) bool {
+	if len(a.CostComponents) != len(b.CostComponents) || len(a.SubResources) != len(b.SubResources) {
+		return false
+	}
+
+	for i, ac := range a.CostComponents {
+		bc := b.CostComponents[i]
+
+		if ac.Name != bc.Name || ac.Unit != bc.Unit || !ac.price.Equal(bc.price) ||
+			!equalDecimalPtrs(ac.HourlyQuantity, bc.HourlyQuantity) ||
+			!equalDecimalPtrs(ac.MonthlyQuantity, bc.MonthlyQuantity) ||
+			!equalDecimalPtrs(ac.MonthlyCost, bc.MonthlyCost) {
+			return false
+		}
+	}
+
+	for i, as := range a.SubResources {
+		bs := b.SubResources[i]
+
+		if as.Name != bs.Name || !sameCostComponents(as, bs) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalDecimalPtrs(a *decimal.Decimal, b *decimal.Decimal) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return a.Equal(*b)
+}
+
+// renameResourcesMap changes the keys of the moved resources and their
+// sub-resources to their new name.
+func renameResourcesMap(resourcesMap map[string]*Resource, movedTo map[string]string) {
+	renamed := make(map[string]string)
+
+	for key := range resourcesMap {
+		for oldName, newName := range movedTo {
+			if key == oldName || strings.HasPrefix(key, oldName+".") {
+				renamed[key] = newName + strings.TrimPrefix(key, oldName)
+				break
+			}
+		}
+	}
+
+	for oldKey, newKey := range renamed {
+		resourcesMap[newKey] = resourcesMap[oldKey]
+		delete(resourcesMap, oldKey)
+	}
+}
+
 // diffResourcesByKey calculates the diff between two resources given their resourcesMap and
 // their key.
 func diffResourcesByKey(resourceKey string, pastResMap, currentResMap map[string]*Resource) (bool, *Resource) {
@@ -72,12 +245,13 @@ func diffResourcesByKey(resourceKey string, pastResMap, currentResMap map[string
 	}
 	changed := false
 	diff := &Resource{
-		Name:         baseResource.Name,
-		IsSkipped:    baseResource.IsSkipped,
-		NoPrice:      baseResource.NoPrice,
-		SkipMessage:  baseResource.SkipMessage,
-		ResourceType: baseResource.ResourceType,
-		Tags:         baseResource.Tags,
+		Name:           baseResource.Name,
+		IsSkipped:      baseResource.IsSkipped,
+		NoPrice:        baseResource.NoPrice,
+		SkipMessage:    baseResource.SkipMessage,
+		ResourceType:   baseResource.ResourceType,
+		Tags:           baseResource.Tags,
+		SourceLocation: baseResource.SourceLocation,
 
 		HourlyCost:  diffDecimals(current.HourlyCost, past.HourlyCost),
 		MonthlyCost: diffDecimals(current.MonthlyCost, past.MonthlyCost),
@@ -155,6 +329,7 @@ func diffCostComponentsByKey(key string, pastCCMap, currentCCMap map[string]*Cos
 		log.Debugf("diffCostComponentsByKey nil current and past with key %s", key)
 		return false, nil
 	}
+	changedAttributes := costComponentChanges(past, current)
 	baseCostComponent := current
 	if current == nil {
 		baseCostComponent = past
@@ -179,6 +354,7 @@ func diffCostComponentsByKey(key string, pastCCMap, currentCCMap map[string]*Cos
 		price:               *diffDecimals(&current.price, &past.price),
 		HourlyCost:          diffDecimals(current.HourlyCost, past.HourlyCost),
 		MonthlyCost:         diffDecimals(current.MonthlyCost, past.MonthlyCost),
+		ChangedAttributes:   changedAttributes,
 	}
 	if !diff.HourlyQuantity.IsZero() || !diff.MonthlyQuantity.IsZero() ||
 		diff.MonthlyDiscountPerc != 0 || !diff.price.IsZero() ||
@@ -129,7 +129,7 @@ func TestCalculateDiff(t *testing.T) {
 		},
 	}
 
-	diff := calculateDiff(pastResources, currentResources)
+	diff := CalculateDiff(pastResources, currentResources)
 	assert.Equal(t, expectedDiff, diff)
 }
 
@@ -289,3 +289,101 @@ func TestDiffCostComponentsByKey_bothNil(t *testing.T) {
 	changed, _ := diffCostComponentsByKey("random_resource", emptyRMap, emptyRMap)
 	assert.Equal(t, false, changed)
 }
+
+func TestCalculateDiffMoves(t *testing.T) {
+	instance := func(name string, price int64) *Resource {
+		return &Resource{
+			Name:         name,
+			ResourceType: "aws_instance",
+			HourlyCost:   decimalPtr(decimal.NewFromInt(price)),
+			MonthlyCost:  decimalPtr(decimal.NewFromInt(price * 730)),
+			CostComponents: []*CostComponent{
+				{
+					Name:            "Instance usage",
+					HourlyQuantity:  decimalPtr(decimal.NewFromInt(1)),
+					MonthlyQuantity: decimalPtr(decimal.NewFromInt(730)),
+					price:           decimal.NewFromInt(price),
+					HourlyCost:      decimalPtr(decimal.NewFromInt(price)),
+					MonthlyCost:     decimalPtr(decimal.NewFromInt(price * 730)),
+				},
+			},
+			SubResources: []*Resource{
+				{
+					Name: "root_block_device",
+					CostComponents: []*CostComponent{
+						{
+							Name:            "Storage",
+							MonthlyQuantity: decimalPtr(decimal.NewFromInt(8)),
+							price:           decimal.NewFromInt(1),
+							MonthlyCost:     decimalPtr(decimal.NewFromInt(8)),
+						},
+					},
+				},
+			},
+		}
+	}
+
+	movedBlock := instance("module.app.aws_instance.web", 2)
+	movedBlock.MovedFrom = "aws_instance.web"
+
+	tests := []struct {
+		name      string
+		past      []*Resource
+		current   []*Resource
+		expected  []string
+		movedFrom []string
+		monthly   []string
+	}{
+		{
+			name:      "identical resource with a new address",
+			past:      []*Resource{instance("aws_instance.a", 1)},
+			current:   []*Resource{instance("module.app.aws_instance.a", 1)},
+			expected:  []string{"module.app.aws_instance.a"},
+			movedFrom: []string{"aws_instance.a"},
+			monthly:   []string{"0"},
+		},
+		{
+			name:      "different cost components aren't a move",
+			past:      []*Resource{instance("aws_instance.a", 1)},
+			current:   []*Resource{instance("module.app.aws_instance.a", 2)},
+			expected:  []string{"aws_instance.a", "module.app.aws_instance.a"},
+			movedFrom: []string{"", ""},
+			monthly:   []string{"-730", "1460"},
+		},
+		{
+			name:      "previous address from the plan",
+			past:      []*Resource{instance("aws_instance.web", 1)},
+			current:   []*Resource{movedBlock},
+			expected:  []string{"module.app.aws_instance.web"},
+			movedFrom: []string{"aws_instance.web"},
+			monthly:   []string{"730"},
+		},
+		{
+			name:      "each removed resource is only moved once",
+			past:      []*Resource{instance("aws_instance.a[0]", 1)},
+			current:   []*Resource{instance("aws_instance.a[\"x\"]", 1), instance("aws_instance.a[\"y\"]", 1)},
+			expected:  []string{"aws_instance.a[\"x\"]", "aws_instance.a[\"y\"]"},
+			movedFrom: []string{"aws_instance.a[0]", ""},
+			monthly:   []string{"0", "730"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := CalculateDiff(tt.past, tt.current)
+
+			names := make([]string, 0, len(diff))
+			movedFrom := make([]string, 0, len(diff))
+			monthly := make([]string, 0, len(diff))
+			for _, r := range diff {
+				names = append(names, r.Name)
+				movedFrom = append(movedFrom, r.MovedFrom)
+				monthly = append(monthly, r.MonthlyCost.String())
+			}
+
+			assert.Equal(t, tt.expected, names)
+			assert.Equal(t, tt.movedFrom, movedFrom)
+			assert.Equal(t, tt.monthly, monthly)
+		})
+	}
+}
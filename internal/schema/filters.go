@@ -9,6 +9,9 @@ type ProductFilter struct {
 	AttributeFilters []*AttributeFilter `json:"attributeFilters,omitempty"`
 }
 
+// PriceFilter filters the prices of a product. EffectiveDate, in YYYY-MM-DD
+// format, is only supported by price snapshots and self-hosted pricing APIs
+// since the Cloud Pricing API only has the current prices.
 type PriceFilter struct {
 	PurchaseOption     *string `json:"purchaseOption,omitempty"`
 	Unit               *string `json:"unit,omitempty"`
@@ -19,6 +22,7 @@ type PriceFilter struct {
 	TermLength         *string `json:"termLength,omitempty"`
 	TermPurchaseOption *string `json:"termPurchaseOption,omitempty"`
 	TermOfferingClass  *string `json:"termOfferingClass,omitempty"`
+	EffectiveDate      *string `json:"effectiveDate,omitempty"`
 }
 
 type AttributeFilter struct {
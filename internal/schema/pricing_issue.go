@@ -0,0 +1,37 @@
+package schema
+
+// PricingIssueType is the type of problem found when looking up the price of
+// a cost component.
+type PricingIssueType string
+
+const (
+	PricingIssueNoProducts       PricingIssueType = "no_products"
+	PricingIssueMultipleProducts PricingIssueType = "multiple_products"
+	PricingIssueNoPrices         PricingIssueType = "no_prices"
+	PricingIssueMultiplePrices   PricingIssueType = "multiple_prices"
+	PricingIssueInvalidPrice     PricingIssueType = "invalid_price"
+)
+
+// PricingIssue records that the price lookup of a cost component didn't match
+// exactly one price, so the price used for it might be wrong.
+type PricingIssue struct {
+	Type    PricingIssueType
+	Matches int
+}
+
+func (i *PricingIssue) Message() string {
+	switch i.Type {
+	case PricingIssueNoProducts:
+		return "no products found"
+	case PricingIssueMultipleProducts:
+		return "multiple products found, used the first product"
+	case PricingIssueNoPrices:
+		return "no prices found"
+	case PricingIssueMultiplePrices:
+		return "multiple prices found, used the first price"
+	case PricingIssueInvalidPrice:
+		return "price could not be converted to a number"
+	}
+
+	return string(i.Type)
+}
@@ -22,6 +22,9 @@ type Resource struct {
 	SkipMessage       string
 	ResourceType      string
 	Tags              map[string]string
+	SourceLocation    *SourceLocation
+	MovedFrom         string
+	AvailabilityZone  string
 	UsageSchema       []*UsageSchemaItem
 	EstimateUsage     EstimateFunc
 	EstimationSummary map[string]bool
@@ -8,13 +8,15 @@ import (
 )
 
 type ResourceData struct {
-	Type          string
-	ProviderName  string
-	Address       string
-	Tags          map[string]string
-	RawValues     gjson.Result
-	referencesMap map[string][]*ResourceData
-	CFResource    cloudformation.Resource
+	Type           string
+	ProviderName   string
+	Address        string
+	Tags           map[string]string
+	RawValues      gjson.Result
+	SourceLocation *SourceLocation
+	MovedFrom      string
+	referencesMap  map[string][]*ResourceData
+	CFResource     cloudformation.Resource
 }
 
 func NewResourceData(resourceType string, providerName string, address string, tags map[string]string, rawValues gjson.Result) *ResourceData {
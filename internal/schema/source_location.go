@@ -0,0 +1,22 @@
+package schema
+
+import "fmt"
+
+// SourceLocation is the file and line of the block that defines a resource.
+// For resources in a remote module this is the module block that calls it,
+// since the module's files aren't part of the project. ModuleSource is the
+// source of the module the resource is in, if it's not in the root module.
+type SourceLocation struct {
+	Filename     string `json:"filename"`
+	Line         int    `json:"line"`
+	ModuleSource string `json:"moduleSource,omitempty"`
+}
+
+// String returns the location as filename:line.
+func (l *SourceLocation) String() string {
+	if l == nil || l.Filename == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%d", l.Filename, l.Line)
+}
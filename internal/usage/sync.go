@@ -0,0 +1,306 @@
+package usage
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+)
+
+// staleResourceComment is added above resources in the usage file that aren't
+// in the projects anymore. They are kept in case they have only been removed
+// temporarily or the usage file is shared with other projects.
+const staleResourceComment = "# Not found in the last sync, this resource might have been removed"
+
+// syncUsageFile returns the usage file contents updated with the synced
+// resource usage. It edits the YAML nodes of the file instead of re-marshaling
+// the usage data, so comments, the order of the keys and any keys that aren't
+// in the usage schema are kept. Resources in the file that aren't in
+// resourceNames are marked as stale instead of being removed.
+func syncUsageFile(contents []byte, syncedResourceUsage map[string]interface{}, resourceNames map[string]bool) ([]byte, error) {
+	var doc yaml.Node
+
+	err := yaml.Unmarshal(contents, &doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing usage YAML")
+	}
+
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{
+			Kind: yaml.DocumentNode,
+			Content: []*yaml.Node{
+				{Kind: yaml.MappingNode, Tag: "!!map"},
+			},
+		}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("Error parsing usage YAML: expected a mapping of version and resource_usage")
+	}
+
+	if mappingValue(root, "version") == nil {
+		root.Content = append([]*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"},
+			{Kind: yaml.ScalarNode, Tag: "!!float", Value: maxUsageFileVersion},
+		}, root.Content...)
+	}
+
+	resourceUsage := mappingValue(root, "resource_usage")
+	if resourceUsage == nil {
+		resourceUsage = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		appendMappingItem(root, "resource_usage", resourceUsage)
+	}
+
+	if isNullNode(resourceUsage) {
+		setMapping(resourceUsage)
+	}
+
+	if resourceUsage.Kind != yaml.MappingNode {
+		return nil, errors.New("Error parsing usage YAML: expected resource_usage to be a mapping of resource names to usage")
+	}
+
+	for i := 0; i+1 < len(resourceUsage.Content); i += 2 {
+		key, value := resourceUsage.Content[i], resourceUsage.Content[i+1]
+
+		usage, ok := syncedResourceUsage[key.Value]
+		if !ok {
+			setStale(key, isStaleResource(key.Value, resourceNames))
+			continue
+		}
+
+		setStale(key, false)
+
+		err := mergeNode(value, usage)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range sortedKeys(syncedResourceUsage) {
+		if mappingValue(resourceUsage, name) != nil {
+			continue
+		}
+
+		value, err := toNode(syncedResourceUsage[name])
+		if err != nil {
+			return nil, err
+		}
+
+		appendMappingItem(resourceUsage, name, value)
+	}
+
+	var buf bytes.Buffer
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+
+	err = enc.Encode(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	err = enc.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// mergeNode updates the node to the value. Maps are merged key by key so any
+// keys that are only in the node are kept. Nodes that already have the value
+// aren't changed so their formatting is kept.
+func mergeNode(node *yaml.Node, value interface{}) error {
+	if m, ok := value.(map[string]interface{}); ok && (node.Kind == yaml.MappingNode || isNullNode(node)) {
+		if node.Kind != yaml.MappingNode {
+			setMapping(node)
+		}
+
+		for _, k := range sortedKeys(m) {
+			if v := mappingValue(node, k); v != nil {
+				err := mergeNode(v, m[k])
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			v, err := toNode(m[k])
+			if err != nil {
+				return err
+			}
+
+			appendMappingItem(node, k, v)
+		}
+
+		return nil
+	}
+
+	synced, err := toNode(value)
+	if err != nil {
+		return err
+	}
+
+	var existingValue, syncedValue interface{}
+	if node.Decode(&existingValue) == nil && synced.Decode(&syncedValue) == nil && equalValues(existingValue, syncedValue) {
+		return nil
+	}
+
+	// Only replace the value so any comments on the node are kept
+	node.Kind = synced.Kind
+	node.Tag = synced.Tag
+	node.Value = synced.Value
+	node.Style = synced.Style
+	node.Content = synced.Content
+
+	return nil
+}
+
+// equalValues returns true if the decoded YAML values are equal. Numbers are
+// compared by value since usage such as 100000000 is synced as the float 1e+08.
+func equalValues(a interface{}, b interface{}) bool {
+	af, aOk := toFloat(a)
+	bf, bOk := toFloat(b)
+	if aOk && bOk {
+		return af == bf
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+
+	return 0, false
+}
+
+// usageFileDiff returns a unified diff of the changes to the usage file.
+func usageFileDiff(path string, before []byte, after []byte) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(before),
+		B:        splitLines(after),
+		FromFile: path,
+		ToFile:   path,
+		Context:  3,
+	})
+}
+
+// splitLines splits the contents into lines that all end with a newline, as
+// needed by difflib.
+func splitLines(contents []byte) []string {
+	lines := strings.SplitAfter(string(contents), "\n")
+
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+
+	lines[len(lines)-1] += "\n"
+
+	return lines
+}
+
+func isStaleResource(name string, resourceNames map[string]bool) bool {
+	if resourceNames[name] {
+		return false
+	}
+
+	// Wildcard usage such as aws_lambda_function.my_function[*] applies to
+	// all the resources of a count or for_each.
+	if strings.HasSuffix(name, "[*]") {
+		prefix := strings.TrimSuffix(name, "*]")
+		for n := range resourceNames {
+			if strings.HasPrefix(n, prefix) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// setStale adds or removes the stale resource comment from the head comment
+// of the resource's key.
+func setStale(key *yaml.Node, stale bool) {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(key.HeadComment, "\n") {
+		if line != "" && line != staleResourceComment {
+			lines = append(lines, line)
+		}
+	}
+
+	if stale {
+		lines = append(lines, staleResourceComment)
+	}
+
+	key.HeadComment = strings.Join(lines, "\n")
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func appendMappingItem(node *yaml.Node, key string, value *yaml.Node) {
+	// Flow style mappings such as {} would otherwise stay on one line
+	node.Style &^= yaml.FlowStyle
+
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+func setMapping(node *yaml.Node) {
+	node.Kind = yaml.MappingNode
+	node.Tag = "!!map"
+	node.Value = ""
+	node.Style = 0
+}
+
+func isNullNode(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null"
+}
+
+func toNode(value interface{}) (*yaml.Node, error) {
+	b, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+
+	err = yaml.Unmarshal(b, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.Content[0], nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,208 @@
+package usage
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncUsageFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		contents      string
+		synced        map[string]interface{}
+		resourceNames []string
+		expected      string
+	}{
+		{
+			name:     "empty file",
+			contents: "",
+			synced: map[string]interface{}{
+				"aws_lambda_function.b": map[string]interface{}{"monthly_requests": 0},
+				"aws_lambda_function.a": map[string]interface{}{"monthly_requests": 0, "request_duration_ms": 0},
+			},
+			resourceNames: []string{"aws_lambda_function.a", "aws_lambda_function.b"},
+			expected: `version: 0.1
+resource_usage:
+  aws_lambda_function.a:
+    monthly_requests: 0
+    request_duration_ms: 0
+  aws_lambda_function.b:
+    monthly_requests: 0
+`,
+		},
+		{
+			name: "new file",
+			contents: `version: "0.1"
+resource_usage: {}
+`,
+			synced: map[string]interface{}{
+				"aws_lambda_function.a": map[string]interface{}{"monthly_requests": 0},
+			},
+			resourceNames: []string{"aws_lambda_function.a"},
+			expected: `version: "0.1"
+resource_usage:
+  aws_lambda_function.a:
+    monthly_requests: 0
+`,
+		},
+		{
+			name: "keeps comments, order and unknown keys",
+			contents: `# Usage for the prod account
+version: 0.1
+resource_usage:
+  # Checked against the CloudWatch metrics
+  aws_lambda_function.z:
+    monthly_requests: 100 # peak month
+    custom_key: foo
+  aws_lambda_function.a:
+    request_duration_ms: 250
+`,
+			synced: map[string]interface{}{
+				"aws_lambda_function.z": map[string]interface{}{"monthly_requests": int64(100), "request_duration_ms": 0},
+				"aws_lambda_function.a": map[string]interface{}{"monthly_requests": 0, "request_duration_ms": int64(250)},
+			},
+			resourceNames: []string{"aws_lambda_function.a", "aws_lambda_function.z"},
+			expected: `# Usage for the prod account
+version: 0.1
+resource_usage:
+  # Checked against the CloudWatch metrics
+  aws_lambda_function.z:
+    monthly_requests: 100 # peak month
+    custom_key: foo
+    request_duration_ms: 0
+  aws_lambda_function.a:
+    request_duration_ms: 250
+    monthly_requests: 0
+`,
+		},
+		{
+			name: "updates estimated values",
+			contents: `version: 0.1
+resource_usage:
+  aws_dynamodb_table.a:
+    storage_gb: 10 # estimated
+    monthly_read_request_units: null
+    monthly_write_request_units: 100000000
+`,
+			synced: map[string]interface{}{
+				"aws_dynamodb_table.a": map[string]interface{}{"storage_gb": 12.5, "monthly_read_request_units": map[string]interface{}{"on_demand": 10}, "monthly_write_request_units": float64(100000000)},
+			},
+			resourceNames: []string{"aws_dynamodb_table.a"},
+			expected: `version: 0.1
+resource_usage:
+  aws_dynamodb_table.a:
+    storage_gb: 12.5 # estimated
+    monthly_read_request_units:
+      on_demand: 10
+    monthly_write_request_units: 100000000
+`,
+		},
+		{
+			name: "marks and unmarks stale resources",
+			contents: `version: 0.1
+resource_usage:
+  aws_lambda_function.removed:
+    monthly_requests: 100
+  # Not found in the last sync, this resource might have been removed
+  aws_lambda_function.restored:
+    monthly_requests: 200
+  aws_lambda_function.counted[*]:
+    monthly_requests: 300
+`,
+			synced: map[string]interface{}{
+				"aws_lambda_function.restored":   map[string]interface{}{"monthly_requests": int64(200)},
+				"aws_lambda_function.counted[0]": map[string]interface{}{"monthly_requests": 0},
+			},
+			resourceNames: []string{"aws_lambda_function.restored", "aws_lambda_function.counted[0]", "aws_instance.free"},
+			expected: `version: 0.1
+resource_usage:
+  # Not found in the last sync, this resource might have been removed
+  aws_lambda_function.removed:
+    monthly_requests: 100
+  aws_lambda_function.restored:
+    monthly_requests: 200
+  aws_lambda_function.counted[*]:
+    monthly_requests: 300
+  aws_lambda_function.counted[0]:
+    monthly_requests: 0
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resourceNames := make(map[string]bool)
+			for _, n := range tt.resourceNames {
+				resourceNames[n] = true
+			}
+
+			actual, err := syncUsageFile([]byte(tt.contents), tt.synced, resourceNames)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(actual))
+
+			// Syncing again shouldn't change the file
+			again, err := syncUsageFile(actual, tt.synced, resourceNames)
+			require.NoError(t, err)
+			assert.Equal(t, string(actual), string(again))
+		})
+	}
+}
+
+func TestSyncUsageFileInvalid(t *testing.T) {
+	_, err := syncUsageFile([]byte("- not a mapping"), map[string]interface{}{}, map[string]bool{})
+	assert.Error(t, err)
+
+	_, err = syncUsageFile([]byte("version: 0.1\nresource_usage: [foo]\n"), map[string]interface{}{}, map[string]bool{})
+	assert.Error(t, err)
+}
+
+func TestSyncUsageDataDryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "infracost-usage.yml")
+	contents := "version: 0.1\n# Kept\nresource_usage:\n  aws_lambda_function.removed:\n    monthly_requests: 100\n"
+	require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+
+	projects := []*schema.Project{
+		{Resources: []*schema.Resource{
+			{
+				Name: "aws_lambda_function.a",
+				UsageSchema: []*schema.UsageSchemaItem{
+					{Key: "monthly_requests", ValueType: schema.Int64, DefaultValue: 0},
+				},
+			},
+		}},
+	}
+
+	result, err := SyncUsageData(projects, map[string]*schema.UsageData{}, path, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.ResourceCount)
+	assert.Equal(t, `--- `+path+`
++++ `+path+`
+@@ -1,5 +1,8 @@
+ version: 0.1
+ # Kept
+ resource_usage:
++  # Not found in the last sync, this resource might have been removed
+   aws_lambda_function.removed:
+     monthly_requests: 100
++  aws_lambda_function.a:
++    monthly_requests: 0
+`, result.Diff)
+
+	actual, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, contents, string(actual))
+
+	result, err = SyncUsageData(projects, map[string]*schema.UsageData{}, path, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Diff)
+
+	synced, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(synced), "aws_lambda_function.a:\n    monthly_requests: 0\n")
+}
@@ -5,14 +5,13 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"sort"
 	"strings"
 
 	"github.com/infracost/infracost"
 	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/version"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
-	"golang.org/x/mod/semver"
 	"gopkg.in/yaml.v2"
 )
 
@@ -34,9 +33,17 @@ type SyncResult struct {
 	ResourceCount    int
 	EstimationCount  int
 	EstimationErrors map[string]error
+	// Diff is the unified diff of the changes to the usage file when the sync
+	// is a dry run.
+	Diff string
 }
 
-func SyncUsageData(projects []*schema.Project, existingUsageData map[string]*schema.UsageData, usageFilePath string) (*SyncResult, error) {
+// SyncUsageData adds the usage keys of the projects' resources to the usage
+// file, with estimates from the cloud where possible. Comments, the order of
+// the keys and any keys that aren't in the usage schema are kept. Resources in
+// the file that aren't in the projects are marked as stale. If dryRun is true
+// the file isn't written and the result has a diff of the changes instead.
+func SyncUsageData(projects []*schema.Project, existingUsageData map[string]*schema.UsageData, usageFilePath string, dryRun bool) (*SyncResult, error) {
 	if usageFilePath == "" {
 		return nil, nil
 	}
@@ -47,21 +54,34 @@ func SyncUsageData(projects []*schema.Project, existingUsageData map[string]*sch
 
 	// TODO: update this when we properly support multiple projects in usage
 	resources := make([]*schema.Resource, 0)
+	resourceNames := make(map[string]bool)
 	for _, project := range projects {
 		resources = append(resources, project.Resources...)
+		for _, r := range project.Resources {
+			resourceNames[r.Name] = true
+		}
 	}
 
-	syncResult, syncedResourcesUsage := syncResourcesUsage(resources, usageSchema, existingUsageData)
-	// yaml.MapSlice is used to maintain the order of keys, so re-running
-	// the code won't change the output.
-	syncedUsageData := yaml.MapSlice{
-		{Key: "version", Value: 0.1},
-		{Key: "resource_usage", Value: syncedResourcesUsage},
+	existing, err := ioutil.ReadFile(usageFilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "Error reading usage file")
 	}
-	d, err := yaml.Marshal(syncedUsageData)
+
+	syncResult, syncedResourcesUsage := syncResourcesUsage(resources, usageSchema, existingUsageData)
+
+	d, err := syncUsageFile(existing, syncedResourcesUsage, resourceNames)
 	if err != nil {
 		return nil, err
 	}
+
+	if dryRun {
+		syncResult.Diff, err = usageFileDiff(usageFilePath, existing, d)
+		if err != nil {
+			return nil, err
+		}
+		return &syncResult, nil
+	}
+
 	err = ioutil.WriteFile(usageFilePath, d, 0600)
 	if err != nil {
 		return nil, err
@@ -69,7 +89,7 @@ func SyncUsageData(projects []*schema.Project, existingUsageData map[string]*sch
 	return &syncResult, nil
 }
 
-func syncResourcesUsage(resources []*schema.Resource, usageSchema map[string][]*SchemaItem, existingUsageData map[string]*schema.UsageData) (SyncResult, yaml.MapSlice) {
+func syncResourcesUsage(resources []*schema.Resource, usageSchema map[string][]*SchemaItem, existingUsageData map[string]*schema.UsageData) (SyncResult, map[string]interface{}) {
 	syncResult := SyncResult{EstimationErrors: make(map[string]error)}
 	syncedResourceUsage := make(map[string]interface{})
 	for _, resource := range resources {
@@ -142,10 +162,7 @@ func syncResourcesUsage(resources []*schema.Resource, usageSchema map[string][]*
 		}
 		syncedResourceUsage[resourceName] = unFlattenHelper(resourceUsage)
 	}
-	// yaml.MapSlice is used to maintain the order of keys, so re-running
-	// the code won't change the output.
-	result := mapToSortedMapSlice(syncedResourceUsage)
-	return syncResult, result
+	return syncResult, syncedResourceUsage
 }
 
 func loadUsageSchema() (map[string][]*SchemaItem, error) {
@@ -195,26 +212,6 @@ func unFlattenHelper(input map[string]interface{}) map[string]interface{} {
 	return result
 }
 
-func mapToSortedMapSlice(input map[string]interface{}) yaml.MapSlice {
-	result := make(yaml.MapSlice, 0)
-	// sort keys of the input to maintain same output for different runs.
-	keys := make([]string, 0)
-	for k := range input {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-	// Iterate over sorted keys
-	for _, k := range keys {
-		v := input[k]
-		if casted, ok := v.(map[string]interface{}); ok {
-			result = append(result, yaml.MapItem{Key: k, Value: mapToSortedMapSlice(casted)})
-		} else {
-			result = append(result, yaml.MapItem{Key: k, Value: v})
-		}
-	}
-	return result
-}
-
 func loadReferenceFile() (map[string]*schema.UsageData, error) {
 	referenceUsageFileContents := infracost.GetReferenceUsageFileContents()
 	usageData, err := parseYAML(*referenceUsageFileContents)
@@ -272,7 +269,7 @@ func parseYAML(y []byte) (map[string]*schema.UsageData, error) {
 		return map[string]*schema.UsageData{}, errors.Wrap(err, "Error parsing usage YAML")
 	}
 
-	if !checkVersion(usageFile.Version) {
+	if !version.IsSupportedFileVersion(usageFile.Version, minUsageFileVersion, maxUsageFileVersion) {
 		return map[string]*schema.UsageData{}, fmt.Errorf("Invalid usage file version. Supported versions are %s ≤ x ≤ %s", minUsageFileVersion, maxUsageFileVersion)
 	}
 
@@ -280,10 +277,3 @@ func parseYAML(y []byte) (map[string]*schema.UsageData, error) {
 
 	return usageMap, nil
 }
-
-func checkVersion(v string) bool {
-	if !strings.HasPrefix(v, "v") {
-		v = "v" + v
-	}
-	return semver.Compare(v, "v"+minUsageFileVersion) >= 0 && semver.Compare(v, "v"+maxUsageFileVersion) <= 0
-}
@@ -0,0 +1,22 @@
+// Package util has small helpers that are shared by several packages.
+package util
+
+import "github.com/shopspring/decimal"
+
+func StrPtr(s string) *string {
+	return &s
+}
+
+func DecimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
+
+// StringValue returns the string the pointer points to, or an empty string if
+// it's nil.
+func StringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
@@ -0,0 +1,23 @@
+package version
+
+import (
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// IsSupportedFileVersion returns true if the version of a file, such as the
+// config or usage file, is between the minimum and maximum supported
+// versions. The versions don't need the v prefix, e.g. 0.1 is the same as
+// v0.1.
+func IsSupportedFileVersion(v string, min string, max string) bool {
+	return semver.Compare(withPrefix(v), withPrefix(min)) >= 0 && semver.Compare(withPrefix(v), withPrefix(max)) <= 0
+}
+
+func withPrefix(v string) string {
+	if !strings.HasPrefix(v, "v") {
+		return "v" + v
+	}
+
+	return v
+}
@@ -0,0 +1,26 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSupportedFileVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected bool
+	}{
+		{"0.1", true},
+		{"v0.1", true},
+		{"0.2", true},
+		{"0.0.9", false},
+		{"0.3", false},
+		{"", false},
+		{"invalid", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, IsSupportedFileVersion(tt.version, "0.1", "0.2"), tt.version)
+	}
+}